@@ -0,0 +1,9 @@
+// Command git-air auto-commits and pushes the git repositories below the
+// working directory. The service lives in pkg/daemon; this is its entry point.
+package main
+
+import "git-air/pkg/daemon"
+
+func main() {
+	daemon.Main()
+}
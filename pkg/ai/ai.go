@@ -0,0 +1,123 @@
+// Package ai talks to the language model behind git-air's AI-assisted
+// features: commit messages, changelogs and .gitignore suggestions.
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config selects the language model used for AI-assisted features. Both
+// providers speak the OpenAI chat completions protocol, so any compatible
+// server (LM Studio, vLLM, OpenRouter, ...) works via Endpoint.
+type Config struct {
+	Provider  string `json:"provider,omitempty"`    // "openai" or "ollama"
+	Model     string `json:"model,omitempty"`       // default gpt-4o-mini / llama3.2
+	Endpoint  string `json:"endpoint,omitempty"`    // API base URL, e.g. http://localhost:11434/v1
+	APIKeyEnv string `json:"api_key_env,omitempty"` // env var holding the API key (default OPENAI_API_KEY)
+}
+
+// Enabled reports whether an AI provider is configured
+func (c Config) Enabled() bool {
+	return c.Provider != ""
+}
+
+// ModelName returns the configured model or the provider's default
+func (c Config) ModelName() string {
+	if c.Model != "" {
+		return c.Model
+	}
+	switch c.Provider {
+	case "openai":
+		return "gpt-4o-mini"
+	case "ollama":
+		return "llama3.2"
+	}
+	return ""
+}
+
+// Complete sends a system and user prompt to the configured provider and returns the reply
+func Complete(cfg Config, system, prompt string) (string, error) {
+	endpoint, model, apiKey := cfg.Endpoint, cfg.ModelName(), ""
+	switch cfg.Provider {
+	case "openai":
+		if endpoint == "" {
+			endpoint = "https://api.openai.com/v1"
+		}
+		keyEnv := cfg.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = "OPENAI_API_KEY"
+		}
+		if apiKey = os.Getenv(keyEnv); apiKey == "" {
+			return "", fmt.Errorf("%s is not set", keyEnv)
+		}
+	case "ollama":
+		if endpoint == "" {
+			endpoint = "http://localhost:11434/v1"
+		}
+		if cfg.APIKeyEnv != "" {
+			apiKey = os.Getenv(cfg.APIKeyEnv)
+		}
+	case "":
+		return "", fmt.Errorf("no AI provider configured")
+	default:
+		return "", fmt.Errorf("unknown AI provider %q", cfg.Provider)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       model,
+		"temperature": 0.2,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", strings.TrimSuffix(endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	client := http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("unexpected response: %v", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("empty response from %s", cfg.Provider)
+	}
+	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"git-air/pkg/gitair"
+)
+
+// diffLimit is how much of the staged diff is sent to the provider
+const diffLimit = 12000
+
+// Messages is a gitair.CommitMessageGenerator that has the provider
+// describe the staged changes. The repo must run git commands like
+// gitair.GitRepo does; the daemon's own generator also applies commitlint
+// rules and message settings.
+type Messages struct {
+	Config Config
+
+	// Fallback writes the message when the provider fails, if set
+	Fallback gitair.CommitMessageGenerator
+}
+
+// Message returns the provider's description of the staged changes
+func (m Messages) Message(repo gitair.Repo, changes []gitair.Change) (string, error) {
+	message, err := m.describe(repo)
+	if err != nil && m.Fallback != nil {
+		return m.Fallback.Message(repo, changes)
+	}
+	return message, err
+}
+
+// describe sends the staged diff to the provider and tidies its reply
+func (m Messages) describe(repo gitair.Repo) (string, error) {
+	runner, ok := repo.(interface {
+		Git(args ...string) (string, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("%s: can't read the diff of a %T", repo.Path(), repo)
+	}
+	stat, err := runner.Git("diff", "--cached", "-M", "--stat")
+	if err != nil {
+		return "", fmt.Errorf("%s: diff: %v", repo.Path(), err)
+	}
+	diff, _ := runner.Git("diff", "--cached", "-M")
+	if len(diff) > diffLimit {
+		diff = diff[:diffLimit] + "\n[diff truncated]"
+	}
+
+	system := "You write git commit messages. Reply with only the message: a short imperative " +
+		"summary line, optionally followed by a blank line and a few lines of explanation. " +
+		"No quotes, no code fences, no commentary."
+	reply, err := Complete(m.Config, system, fmt.Sprintf("Diffstat:\n%s\nDiff:\n%s", stat, diff))
+	if err != nil {
+		return "", err
+	}
+	reply = strings.TrimSpace(strings.Trim(strings.TrimSpace(reply), "`"))
+	if reply == "" {
+		return "", fmt.Errorf("empty reply from %s", m.Config.Provider)
+	}
+	return reply, nil
+}
@@ -0,0 +1,161 @@
+// Package airsync runs the git-air cycle for programs that embed it: find
+// the repositories below a root, commit and push each one's changes, pull
+// from its remotes, and do it again every interval.
+//
+//	runner := airsync.Runner{Root: home, Pull: true, Events: &gitair.Bus{}}
+//	err := runner.Run(ctx)
+//
+// The pieces come from pkg/gitair and can be swapped: another Discoverer,
+// a Syncer with AI messages from pkg/ai, the daemon's own daemon.Syncer,
+// or Repos that aren't plain git.
+package airsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// DefaultInterval is the time between cycles when Runner.Interval is unset,
+// the daemon's default check interval
+const DefaultInterval = 30 * time.Second
+
+// Runner syncs every repository below Root. The zero value plus a Root
+// commits and pushes like git-air without a config.
+type Runner struct {
+	// Root is the directory searched for repositories
+	Root string
+
+	// Discoverer finds the repositories, gitair.WalkDiscoverer if nil
+	Discoverer gitair.Discoverer
+
+	// Open turns a discovered path into a Repo, gitair.Open if nil
+	Open func(path string) gitair.Repo
+
+	// Syncer commits and pushes a repo; if nil, a gitair.AutoSyncer with
+	// timestamp messages that pushes and publishes to Events
+	Syncer gitair.Syncer
+
+	// Pull merges the checked-out branch from every remote after syncing
+	Pull bool
+
+	// Interval is the time between cycles of Run
+	Interval time.Duration
+
+	// Events receives what a cycle does, if set
+	Events *gitair.Bus
+}
+
+// Report is what a cycle did in one repository. Err is set when the repo
+// could not be synced; failed pushes and pulls are listed, not errors.
+type Report struct {
+	Repo   string
+	Result gitair.Result
+	Pulled []string
+	Failed map[string]string // Remote to git's output of the failed pull
+	Err    error
+}
+
+// Cycle syncs every repository once and reports on each, in discovery order
+func (r *Runner) Cycle() ([]Report, error) {
+	discoverer := r.Discoverer
+	if discoverer == nil {
+		discoverer = gitair.WalkDiscoverer{}
+	}
+	paths, err := discoverer.Discover(r.Root)
+	if err != nil {
+		return nil, fmt.Errorf("discovering repos in %s: %v", r.Root, err)
+	}
+
+	reports := make([]Report, 0, len(paths))
+	for _, path := range paths {
+		repo := r.open(path)
+		report := Report{Repo: repo.Path()}
+		report.Result, report.Err = r.syncer().Sync(repo)
+		if report.Err == nil && r.Pull {
+			r.pull(repo, &report)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Run runs a cycle every Interval until ctx is done, which it returns as
+// the error. A cycle that can't discover the repos ends Run.
+func (r *Runner) Run(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Cycle(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// open returns the Repo for a discovered path
+func (r *Runner) open(path string) gitair.Repo {
+	if r.Open != nil {
+		return r.Open(path)
+	}
+	return gitair.Open(path)
+}
+
+// syncer returns the Syncer, or the default auto-syncer
+func (r *Runner) syncer() gitair.Syncer {
+	if r.Syncer != nil {
+		return r.Syncer
+	}
+	return gitair.AutoSyncer{Messages: gitair.TimestampMessages{}, Push: true, Events: r.Events}
+}
+
+// pull merges the checked-out branch from each remote of the repo
+func (r *Runner) pull(repo gitair.Repo, report *Report) {
+	branch := repo.Branch()
+	if branch == "" {
+		return // Detached HEAD, nothing to merge into
+	}
+	for _, remote := range repo.Remotes() {
+		before := head(repo)
+		output, err := repo.Pull(remote, branch)
+		if err != nil {
+			if report.Failed == nil {
+				report.Failed = map[string]string{}
+			}
+			report.Failed[remote] = output
+			r.Events.Publish(gitair.Event{Type: gitair.Error, Repo: repo.Path(), Op: "pull", Remote: remote, Err: output})
+			continue
+		}
+		if after := head(repo); after != "" && after == before {
+			continue // Already up to date
+		}
+		report.Pulled = append(report.Pulled, remote)
+		r.Events.Publish(gitair.Event{Type: gitair.PullMerged, Repo: repo.Path(), Remote: remote})
+	}
+}
+
+// head returns the commit HEAD points to, "" if the repo can't tell
+func head(repo gitair.Repo) string {
+	runner, ok := repo.(interface {
+		Git(args ...string) (string, error)
+	})
+	if !ok {
+		return ""
+	}
+	output, err := runner.Git("rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// heatShades are the heatmap cells from no commits to the busiest day
+var heatShades = []string{"·", "░", "▒", "▓", "█"}
+
+// activityDay is one day of a repository's commits
+type activityDay struct {
+	Date    time.Time
+	Commits int
+	Added   int
+	Deleted int
+}
+
+// repoActivity is one repository's days, oldest first, covering whole weeks
+type repoActivity struct {
+	Repo string
+	Days []activityDay
+	Max  int // commits on the busiest day
+}
+
+// runActivity implements `git-air activity [--weeks N] [--html file] [repo...]`
+func runActivity(args []string) int {
+	fs := flag.NewFlagSet("activity", flag.ExitOnError)
+	weeks := fs.Int("weeks", 12, "Number of weeks to show")
+	days := fs.Int("days", 14, "Number of days to list line totals for")
+	htmlPath := fs.String("html", "", "Also write the charts to this HTML file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air activity [--weeks N] [--days N] [--html <file>] [repo...]"))
+		fmt.Println(tr("\nShows a commit heatmap per repository (one column per week, Monday on top)"))
+		fmt.Println(tr("and the lines added and deleted per day, from the state store. Without"))
+		fmt.Println(tr("repos, every repository git-air committed to."))
+	}
+	repos := parseInterspersed(fs, args)
+	if *weeks < 1 || *days < 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// Start on the Monday weeks-1 weeks before this one
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	start := today.AddDate(0, 0, -((int(today.Weekday())+6)%7)-7*(*weeks-1))
+	events, err := readEvents(start, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error reading history: %v\n"), err)
+		return 1
+	}
+
+	activity := collectActivity(events, repos, start, today)
+	if len(activity) == 0 {
+		fmt.Println(tr("No commits recorded in this period."))
+		return 0
+	}
+	for _, repo := range activity {
+		printActivity(repo, *days)
+	}
+
+	if *htmlPath != "" {
+		if err := writeActivityHTML(*htmlPath, activity, *days); err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error writing %s: %v\n"), *htmlPath, err)
+			return 1
+		}
+		fmt.Printf(tr("✓ Wrote %s\n"), *htmlPath)
+	}
+	return 0
+}
+
+// collectActivity sums the successful commits per repository and day from
+// start to today, for the given repos or all that have commits
+func collectActivity(events []Event, repos []string, start, today time.Time) []repoActivity {
+	wanted := map[string]bool{}
+	for _, repo := range repos {
+		if abs, err := filepath.Abs(repo); err == nil {
+			wanted[abs] = true
+		}
+	}
+
+	perRepo := map[string]*repoActivity{}
+	for _, event := range events {
+		if event.Kind != "commit" || !event.OK || (len(wanted) > 0 && !wanted[event.Repo]) {
+			continue
+		}
+		repo := perRepo[event.Repo]
+		if repo == nil {
+			repo = &repoActivity{Repo: event.Repo}
+			for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+				repo.Days = append(repo.Days, activityDay{Date: day})
+			}
+			perRepo[event.Repo] = repo
+		}
+		local := event.Time.In(time.Local)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+		i := int(day.Sub(start).Hours()+12) / 24 // +12 absorbs DST shifts
+		if i < 0 || i >= len(repo.Days) {
+			continue
+		}
+		repo.Days[i].Commits++
+		repo.Days[i].Added += event.Added
+		repo.Days[i].Deleted += event.Deleted
+		if repo.Days[i].Commits > repo.Max {
+			repo.Max = repo.Days[i].Commits
+		}
+	}
+
+	result := make([]repoActivity, 0, len(perRepo))
+	for _, repo := range perRepo {
+		result = append(result, *repo)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Repo < result[j].Repo })
+	return result
+}
+
+// shade picks the heatmap level (0 for none) of a day's commits
+func (r repoActivity) shade(commits int) int {
+	if commits == 0 || r.Max == 0 {
+		return 0
+	}
+	return 1 + (commits*(len(heatShades)-1)-1)/r.Max
+}
+
+// printActivity renders a repository's heatmap and its recent daily line totals
+func printActivity(repo repoActivity, days int) {
+	commits := 0
+	for _, day := range repo.Days {
+		commits += day.Commits
+	}
+	fmt.Printf(tr("📈 %s: %d commits since %s\n"), repo.Repo, commits, repo.Days[0].Date.Format("2006-01-02"))
+
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		for i := weekday; i < len(repo.Days); i += 7 {
+			row.WriteString(heatShades[repo.shade(repo.Days[i].Commits)])
+		}
+		fmt.Printf("  %s %s\n", repo.Days[weekday].Date.Format("Mon")[:2], row.String())
+	}
+	fmt.Printf(tr("     %s less … more (busiest day: %d commits)\n"), strings.Join(heatShades, ""), repo.Max)
+
+	if days > 0 {
+		fmt.Println()
+		for _, day := range recentDays(repo, days) {
+			fmt.Printf(tr("  %s  %4d commits  %+7d  %7s\n"), day.Date.Format("Mon 2006-01-02"), day.Commits, day.Added, fmt.Sprintf("-%d", day.Deleted))
+		}
+	}
+	fmt.Println()
+}
+
+// recentDays returns up to n of the latest days, newest first
+func recentDays(repo repoActivity, n int) []activityDay {
+	var days []activityDay
+	for i := len(repo.Days) - 1; i >= 0 && len(days) < n; i-- {
+		days = append(days, repo.Days[i])
+	}
+	return days
+}
+
+// activityTemplate renders the HTML export: a GitHub-style grid per repo
+// (weeks as columns) and a table of daily totals
+var activityTemplate = template.Must(template.New("activity").Funcs(template.FuncMap{
+	"weeks": func(repo repoActivity) [][]activityDay {
+		var weeks [][]activityDay
+		for i := 0; i < len(repo.Days); i += 7 {
+			weeks = append(weeks, repo.Days[i:min(i+7, len(repo.Days))])
+		}
+		return weeks
+	},
+	"shade":  func(repo repoActivity, day activityDay) int { return repo.shade(day.Commits) },
+	"recent": recentDays,
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Git Air activity</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+.grid { display: flex; gap: 3px; margin: 0.5em 0 1em; }
+.week { display: flex; flex-direction: column; gap: 3px; }
+.day { width: 12px; height: 12px; border-radius: 2px; }
+.s0 { background: #ebedf0; } .s1 { background: #9be9a8; } .s2 { background: #40c463; }
+.s3 { background: #30a14e; } .s4 { background: #216e39; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+td, th { padding: 2px 10px; text-align: right; } td:first-child, th:first-child { text-align: left; }
+.added { color: #1a7f37; } .deleted { color: #cf222e; }
+</style></head><body>
+<h1>Git Air activity</h1>
+<p>Generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+{{range $repo := .Repos}}
+<h2>{{$repo.Repo}}</h2>
+<div class="grid">{{range weeks $repo}}<div class="week">{{range .}}<div class="day s{{shade $repo .}}" title="{{.Date.Format "Mon 2006-01-02"}}: {{.Commits}} commits, +{{.Added}} -{{.Deleted}}"></div>{{end}}</div>{{end}}</div>
+{{if $.Days}}<table>
+<tr><th>Day</th><th>Commits</th><th>Added</th><th>Deleted</th></tr>
+{{range recent $repo $.Days}}<tr><td>{{.Date.Format "Mon 2006-01-02"}}</td><td>{{.Commits}}</td><td class="added">+{{.Added}}</td><td class="deleted">-{{.Deleted}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}
+</body></html>
+`))
+
+// writeActivityHTML writes the activity of every repository as one HTML page
+func writeActivityHTML(path string, activity []repoActivity, days int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{"Repos": activity, "Days": days, "Generated": time.Now()}
+	if err := activityTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,174 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// AdoptDir is a plain directory git-air turns into a repository and then manages
+type AdoptDir struct {
+	Path   string `json:"path"`             // relative to the scan root, or absolute
+	Remote string `json:"remote,omitempty"` // added as origin; local paths are created as bare repos
+}
+
+// runAdopt implements `git-air adopt <dir>`
+func runAdopt(args []string) int {
+	fs := flag.NewFlagSet("adopt", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	remote := fs.String("remote", "", "Remote URL to add as origin (local paths are created as bare repos)")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air adopt [--remote <url>] [-c <file>] <dir>"))
+		fmt.Println(tr("\nTurns a plain directory into a Git repository with an initial"))
+		fmt.Println(tr("commit, so git-air manages it from then on."))
+	}
+	positional := parseInterspersed(fs, args)
+
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+
+	if !adoptDir(positional[0], *remote) {
+		return 1
+	}
+	return 0
+}
+
+// adoptConfiguredDirs adopts every directory listed under "adopt" in the config
+// and returns the paths of all of them that are repositories now
+func adoptConfiguredDirs() []string {
+	var adopted []string
+	for _, dir := range config.Adopt {
+		path := expandHome(normalizePath(dir.Path))
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil || adoptDir(path, dir.Remote) {
+			adopted = append(adopted, filepath.Clean(path))
+		}
+	}
+	return adopted
+}
+
+// adoptDir runs git init in a directory, makes the initial commit and sets up
+// the remote. Directories that already are repositories are left alone.
+func adoptDir(dir, remote string) bool {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		fmt.Printf(tr("  ❌ Cannot adopt %s: not a directory\n"), dir)
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		fmt.Printf(tr("  ✓ %s is already a Git repository\n"), dir)
+		return true
+	}
+
+	settings := settingsFor(dir)
+	name := filepath.Base(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		name = filepath.Base(abs)
+	}
+
+	// Local remotes are relative to where we are now, not to the adopted dir
+	if remote != "" && isLocalPath(remote) {
+		if abs, err := filepath.Abs(expandHome(strings.TrimPrefix(remote, "file://"))); err == nil {
+			remote = abs
+		}
+	}
+
+	leave, err := enterRepo(dir)
+	if err != nil {
+		fmt.Printf(tr("  ❌ Error changing to %s: %v\n"), dir, err)
+		return false
+	}
+	defer leave()
+
+	fmt.Printf(tr("🌱 Adopting %s...\n"), dir)
+	initArgs := []string{"init"}
+	if settings.Template != nil && settings.Template.Branch != "" {
+		initArgs = append(initArgs, "--initial-branch="+settings.Template.Branch)
+	}
+	if output, err := gitOutput(initArgs...); err != nil {
+		fmt.Printf(tr("  ❌ git init failed: %s\n"), lastLine(output))
+		return false
+	}
+
+	ensureIdentity(settings.Identity)
+	if settings.Template != nil {
+		applyTemplate(*settings.Template, name)
+	}
+	if !hasIdentity() {
+		fmt.Printf(tr("  ⚠️  No git identity configured, initial commit skipped\n"))
+		return true
+	}
+
+	if !stageChanges(settings) {
+		fmt.Printf(tr("  ❌ Error staging files in %s\n"), dir)
+		return false
+	}
+	commitMsg := "initial commit - " + time.Now().Format("2006-01-02 15:04:05")
+	if output, err := gitOutput("commit", "--allow-empty", "-m", commitMsg); err != nil {
+		fmt.Printf(tr("  ❌ Initial commit failed: %s\n"), lastLine(output))
+		return false
+	}
+	fmt.Printf(tr("  ✓ Initial commit created\n"))
+
+	if remote != "" {
+		if !addAdoptRemote(remote) {
+			return true // The repository itself is fine, the next cycles retry pushing
+		}
+		pushToAllRemotes(dir)
+	}
+	return true
+}
+
+// addAdoptRemote adds the remote as origin, creating a bare repository first
+// when the URL is a local path that does not exist yet
+func addAdoptRemote(remote string) bool {
+	if isLocalPath(remote) {
+		if _, err := os.Stat(remote); os.IsNotExist(err) {
+			if output, err := gitOutput("init", "--bare", remote); err != nil {
+				fmt.Printf(tr("  ❌ Creating bare remote %s failed: %s\n"), remote, lastLine(output))
+				return false
+			}
+			fmt.Printf(tr("  📦 Created bare remote %s\n"), remote)
+		}
+	}
+
+	if output, err := gitOutput("remote", "add", "origin", remote); err != nil {
+		fmt.Printf(tr("  ❌ Adding remote failed: %s\n"), lastLine(output))
+		return false
+	}
+	return true
+}
+
+// isLocalPath reports whether a remote URL refers to the local filesystem
+func isLocalPath(remote string) bool {
+	if strings.Contains(remote, "://") {
+		return strings.HasPrefix(remote, "file://")
+	}
+	if filepath.VolumeName(remote) != "" {
+		return true // Windows drive letter
+	}
+	// scp-like syntax (user@host:path) has a colon before any slash
+	if colon := strings.Index(remote, ":"); colon > 0 && !strings.Contains(remote[:colon], "/") {
+		return false
+	}
+	return true
+}
+
+// expandHome replaces a leading ~/ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,6 @@
+package daemon
+
+import "git-air/pkg/ai"
+
+// AIConfig selects the language model for the AI-assisted features (see pkg/ai)
+type AIConfig = ai.Config
@@ -0,0 +1,258 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-air/pkg/ai"
+	"git-air/pkg/gitair"
+)
+
+// aiMessageTrailer marks AI-written messages, so isAutoCommit still
+// recognizes them without the "auto commit" subject
+const aiMessageTrailer = "Git-Air-Message: ai"
+
+// aiCommitMessage asks the AI provider to describe the staged changes. The
+// reply must pass the repo's commitlint rules; it is regenerated once with
+// the problems listed, after that "" is returned and the caller falls back.
+func aiCommitMessage(repoName string, rules lintRules, settings RepoSettings) string {
+	stat, diff, err := aiDiff(settings, "--cached")
+	if err != nil {
+		return ""
+	}
+	return aiMessage(repoName, rules, settings, fmt.Sprintf("Diffstat:\n%s\nDiff:\n%s", stat, diff))
+}
+
+// aiSquashMessage asks the AI provider for one message summarizing a run of
+// commits (oldest first) that are about to be squashed, from their combined
+// diff and when each was made
+func aiSquashMessage(repoName string, rules lintRules, settings RepoSettings, commits []string) string {
+	base := commits[0] + "^"
+	stat, diff, err := aiDiff(settings, base, commits[len(commits)-1])
+	if err != nil {
+		return ""
+	}
+	log, err := gitOutput(append([]string{"log", "--no-walk=unsorted", "--format=%ci %s"}, commits...)...)
+	if err != nil {
+		return ""
+	}
+	prompt := fmt.Sprintf("These %d commits, saved automatically while someone worked, become one commit. "+
+		"Describe the work as a whole, not the individual saves.\n\nCommits (time and subject):\n%s\nCombined diffstat:\n%s\nCombined diff:\n%s",
+		len(commits), log, stat, diff)
+	return aiMessage(repoName, rules, settings, prompt)
+}
+
+// aiDiff returns the diffstat and the diff for git diff <args> as sent to
+// the AI provider: renames detected, generated files left out, truncated
+func aiDiff(settings RepoSettings, args ...string) (string, string, error) {
+	// -M -C shows moved and copied files as renames instead of whole-file hunks
+	stat, err := gitCommand(append(append([]string{"diff"}, args...), "-M", "-C", "--stat")...).Output()
+	if err != nil {
+		return "", "", err
+	}
+	// Generated files would only drown out the real changes
+	diffArgs := append(append([]string{"diff"}, args...), "-M", "-C")
+	names, _ := gitCommand(append(append([]string{"diff"}, args...), "--name-only", "-z")...).Output()
+	pathspecs, skipped := withoutGenerated(nulList(names), settings.Generated)
+	if len(pathspecs) > 0 {
+		diffArgs = append(append(diffArgs, "--"), pathspecs...)
+	}
+	diff, _ := gitCommand(diffArgs...).Output()
+	diffText := string(diff)
+	if len(diffText) > 12000 {
+		diffText = diffText[:12000] + "\n[diff truncated]"
+	}
+	if len(skipped) > 0 {
+		diffText += "\n[diff of generated files left out: " + strings.Join(skipped, ", ") + "]"
+	}
+	return string(stat), diffText, nil
+}
+
+// aiMessage sends a prompt describing changes and returns the tidied reply,
+// retrying once if it breaks the commitlint rules
+func aiMessage(repoName string, rules lintRules, settings RepoSettings, prompt string) string {
+	system := "You write git commit messages. Reply with only the message: a short imperative " +
+		"summary line, optionally followed by a blank line and a few lines of explanation. " +
+		"No quotes, no code fences, no commentary."
+	if rules != nil {
+		system += " " + rules.promptText()
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		reply, err := ai.Complete(settings.aiConfig(), system, prompt)
+		if err == nil && strings.TrimSpace(reply) == "" {
+			err = fmt.Errorf("empty reply")
+		}
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  %s: AI commit message unavailable: %v\n"), repoName, err)
+			return ""
+		}
+
+		message := tidyMessage(reply, settings.subjectLength(), settings.bodyWidth())
+		problems := rules.violations(message)
+		if len(problems) == 0 {
+			return message
+		}
+		fmt.Printf(tr("  ⚠️  %s: AI commit message breaks commitlint rules (%s)\n"), repoName, strings.Join(problems, "; "))
+		prompt += fmt.Sprintf("\n\nYour previous message was:\n%s\n\nIt breaks these rules, write a new one: %s",
+			message, strings.Join(problems, "; "))
+	}
+	return ""
+}
+
+// tidyMessage strips code fences and quotes models like to add, shortens the
+// subject line at a word boundary and wraps the body (0 disables either)
+func tidyMessage(reply string, subjectLength, bodyWidth int) string {
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```")
+	reply = strings.TrimSuffix(reply, "```")
+	lines := strings.Split(strings.TrimSpace(reply), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+
+	subject := shortenSubject(strings.Trim(lines[0], "\"'` "), subjectLength)
+	body := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + wrapBody(body, bodyWidth)
+}
+
+// shortenSubject cuts a subject line to at most limit characters, at the last
+// word boundary that fits
+func shortenSubject(subject string, limit int) string {
+	runes := []rune(subject)
+	if limit <= 0 || len(runes) <= limit {
+		return subject
+	}
+	cut := limit
+	for i := limit; i > limit/2; i-- {
+		if runes[i] == ' ' {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(runes[:cut]), " ,;:-")
+}
+
+// wrapBody re-wraps the paragraphs and list items of a message body at width
+// columns. Indented lines (code) and words longer than a line are left as they are.
+func wrapBody(body string, width int) string {
+	if width <= 0 {
+		return body
+	}
+
+	var out []string
+	for _, paragraph := range strings.Split(body, "\n\n") {
+		var wrapped []string
+		var words []string
+		indent := ""
+		flush := func() {
+			if len(words) > 0 {
+				wrapped = append(wrapped, wrapWords(words, width, indent)...)
+				words, indent = nil, ""
+			}
+		}
+
+		for _, line := range strings.Split(paragraph, "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") || trimmed == "":
+				flush()
+				wrapped = append(wrapped, line)
+			case listMarker(trimmed) != "":
+				// Each list item wraps on its own with a hanging indent
+				flush()
+				indent = strings.Repeat(" ", len([]rune(listMarker(trimmed))))
+				words = strings.Fields(trimmed)
+			default:
+				words = append(words, strings.Fields(trimmed)...)
+			}
+		}
+		flush()
+		out = append(out, strings.Join(wrapped, "\n"))
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// listMarker returns the bullet ("- ", "* ") or number ("1. ") a list item starts with
+func listMarker(line string) string {
+	for _, bullet := range []string{"- ", "* ", "• "} {
+		if strings.HasPrefix(line, bullet) {
+			return bullet
+		}
+	}
+	if dot := strings.Index(line, ". "); dot > 0 && dot <= 3 {
+		if _, err := strconv.Atoi(line[:dot]); err == nil {
+			return line[:dot+2]
+		}
+	}
+	return ""
+}
+
+// wrapWords fills lines of at most width characters; continuation lines get indent
+func wrapWords(words []string, width int, indent string) []string {
+	var lines []string
+	line := ""
+	for _, word := range words {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = indent + word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// defaultMessage is the auto-commit message unless the AI provider writes
+// one: message_template if set, else the fallback message
+func defaultMessage(settings RepoSettings, rules lintRules, repoName string, changes []fileChange, monorepo bool) string {
+	if settings.MessageTemplate != nil {
+		return templateMessage(*settings.MessageTemplate, repoName, changePaths(changes))
+	}
+	return fallbackMessage(rules, monorepo)
+}
+
+// templateMessage fills in a message_template
+func templateMessage(template, repoName string, paths []string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	now := time.Now()
+	return strings.NewReplacer(
+		"{repo}", repoName,
+		"{files}", describeCount(paths),
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("15:04:05"),
+		"{host}", host,
+	).Replace(template)
+}
+
+// fallbackMessage is the default auto-commit message, given a conventional
+// type when commitlint rules would reject it
+func fallbackMessage(rules lintRules, monorepo bool) string {
+	message := gitair.TimestampMessage(monorepo)
+	if len(rules.violations(message)) == 0 {
+		return message
+	}
+
+	typ := "chore"
+	if rule, ok := rules["type-enum"]; ok && rule.When == "always" && !inValues(rule, typ) {
+		if values := ruleValues(rule); len(values) > 0 {
+			typ = values[0]
+		}
+	}
+	return typ + ": " + message
+}
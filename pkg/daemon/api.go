@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// apiListen is the address of the REST API (--api), "" for none
+var apiListen string
+
+// APIConfig secures the REST API started with --api
+type APIConfig struct {
+	TokenEnv string `json:"token_env,omitempty"` // env var holding the bearer token callers must send, empty allows local callers
+}
+
+// defaultCommitsListed is how many commits GET /api/commits returns
+// without ?n=
+const defaultCommitsListed = 20
+
+// startAPI serves the REST API on apiListen: the control socket's status,
+// trigger, pause and resume as JSON over TCP, plus the recent commits, for
+// dashboards and automation on other machines, and the web dashboard at /
+func startAPI() {
+	if apiListen == "" {
+		return
+	}
+	token := ""
+	if config.API.TokenEnv != "" {
+		token = os.Getenv(config.API.TokenEnv)
+		if token == "" {
+			fmt.Printf(tr("❌ REST API: %s is not set, not listening\n"), config.API.TokenEnv)
+			return
+		}
+	}
+	// Anyone reaching the API can pause and trigger syncing
+	if token == "" && !loopbackListen(apiListen) {
+		fmt.Printf(tr("❌ REST API: %s is reachable from other machines, set api.token_env or listen on 127.0.0.1\n"), apiListen)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", serveDashboard)
+	mux.HandleFunc("/api/status", apiGet(controlGetStatus))
+	mux.HandleFunc("/api/repos", apiGet(apiRepos))
+	mux.HandleFunc("/api/commits", apiGet(apiCommits))
+	mux.HandleFunc("/api/trigger", controlTrigger)
+	mux.HandleFunc("/api/pause", func(w http.ResponseWriter, r *http.Request) { controlPause(w, r, true) })
+	mux.HandleFunc("/api/resume", func(w http.ResponseWriter, r *http.Request) { controlPause(w, r, false) })
+	handler := &apiHandler{token: token, mux: mux}
+
+	controlListening = true
+	go func() {
+		if err := http.ListenAndServe(apiListen, handler); err != nil {
+			fmt.Printf(tr("❌ REST API stopped: %v\n"), err)
+		}
+	}()
+	fmt.Printf(tr("🔌 REST API: listening on %s\n"), apiListen)
+	fmt.Printf(tr("🖥️  Dashboard: %s\n"), dashboardURL(apiListen))
+}
+
+// apiHandler checks where requests come from and the bearer token before
+// passing them on. Without a token only local requests are let in.
+type apiHandler struct {
+	token string
+	mux   *http.ServeMux
+}
+
+func (h *apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" && !localRequest(r) {
+		http.Error(w, "only local requests are allowed without api.token_env", http.StatusForbidden)
+		return
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+		return
+	}
+	// The dashboard page holds no data, the browser has to load it to send the token
+	if h.token != "" && r.URL.Path != "/" {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(h.token)) != 1 {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// sameOrigin reports whether a request from a browser comes from a page
+// served by the API itself, like the dashboard. Other tools send no Origin.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// apiGet refuses anything but GET for a read-only endpoint
+func apiGet(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// apiRepos answers GET /api/repos: the repos and their sync state
+func apiRepos(w http.ResponseWriter, r *http.Request) {
+	repos := currentStatus().Repos
+	if repos == nil {
+		repos = []repoState{} // [] rather than null before the first cycle
+	}
+	replyJSON(w, repos)
+}
+
+// apiCommits answers GET /api/commits?n=20: the last n commits git-air
+// made since it started, newest first, optionally only those of ?repo=
+func apiCommits(w http.ResponseWriter, r *http.Request) {
+	n := defaultCommitsListed
+	if value := r.URL.Query().Get("n"); value != "" {
+		var err error
+		if n, err = strconv.Atoi(value); err != nil || n < 1 {
+			http.Error(w, "n must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	repo := ""
+	if path := r.URL.Query().Get("repo"); path != "" {
+		if repo = controlRepo(resolveControlPath(path)); repo == "" {
+			http.Error(w, "not a repository this git-air syncs", http.StatusNotFound)
+			return
+		}
+	}
+
+	commits := []commitRecord{}
+	control.Lock()
+	for i := len(control.commits) - 1; i >= 0 && len(commits) < n; i-- {
+		if repo == "" || control.commits[i].Repo == repo {
+			commits = append(commits, control.commits[i])
+		}
+	}
+	control.Unlock()
+	replyJSON(w, commits)
+}
@@ -0,0 +1,429 @@
+package daemon
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupConfig enables periodic git bundle snapshots of every repository
+type BackupConfig struct {
+	Dir             string   `json:"dir,omitempty"`              // e.g. an external disk or NAS mount
+	IntervalMinutes Minutes  `json:"interval_minutes,omitempty"` // default 60
+	RetentionDays   int      `json:"retention_days,omitempty"`   // start a full bundle and drop old chains after N days, 0 keeps everything
+	S3              S3Config `json:"s3,omitempty"`
+}
+
+// lastRefsFile lists the ref tips included in the previous bundle, so the
+// next one only contains what is new
+const lastRefsFile = "last-refs"
+
+// uploadedFile lists the bundles already copied to S3, so failed uploads are
+// retried on the next backup
+const uploadedFile = "uploaded"
+
+// bundleStampFormat names bundles so they sort in creation order
+const bundleStampFormat = "20060102-150405.000"
+
+// lastBackup remembers when each repo was last bundled in this run
+var lastBackup = map[string]time.Time{}
+
+// backupInterval returns how often each repo is bundled
+func (c BackupConfig) backupInterval() time.Duration {
+	if c.IntervalMinutes > 0 {
+		return time.Duration(c.IntervalMinutes)
+	}
+	return time.Hour
+}
+
+// enabled reports whether bundles are written at all
+func (c BackupConfig) enabled() bool {
+	return c.Dir != "" || c.S3.Bucket != ""
+}
+
+// retention returns how long bundle chains are kept, 0 means forever
+func (c BackupConfig) retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// validate checks the backup settings
+func (c BackupConfig) validate() error {
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("backup retention_days must not be negative")
+	}
+	if c.S3.Bucket != "" && c.S3.Endpoint == "" {
+		return fmt.Errorf("backup s3 endpoint is required with a bucket")
+	}
+	return nil
+}
+
+// runBackups bundles every repo whose backup interval has passed
+func runBackups(repos []string) {
+	if !config.Backup.enabled() {
+		return
+	}
+
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		dir := backupRepoDir(abs)
+
+		last, ok := lastBackup[abs]
+		if !ok {
+			// After a restart, continue from the previous run's schedule
+			if info, err := os.Stat(filepath.Join(dir, lastRefsFile)); err == nil {
+				last = info.ModTime()
+			}
+		}
+		if time.Since(last) < config.Backup.backupInterval() {
+			continue
+		}
+
+		if backupRepo(abs, dir) {
+			lastBackup[abs] = time.Now()
+		}
+	}
+}
+
+// backupRepo bundles a repository, applies retention and uploads new bundles.
+// Returns true if the repo is backed up.
+func backupRepo(repo, dir string) bool {
+	if !bundleRepo(repo, dir) {
+		return false
+	}
+	if config.Backup.RetentionDays > 0 {
+		for _, name := range expiredBundles(bundleNames(dir), config.Backup.retention()) {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+	if config.Backup.S3.Bucket != "" {
+		return uploadBundles(repo, dir)
+	}
+	return true
+}
+
+// backupRepoDir returns the bundle directory for a repository: its name plus
+// a hash of the full path, so equally named repos don't collide. Without a
+// backup dir, bundles for S3 are staged in the state directory.
+func backupRepoDir(abs string) string {
+	sum := sha1.Sum([]byte(abs))
+	root := filepath.Join(stateDir(), "bundles")
+	if config.Backup.Dir != "" {
+		root = expandHome(config.Backup.Dir)
+	}
+	return filepath.Join(root, filepath.Base(abs)+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// bundleRepo writes an incremental bundle of everything new since the last
+// bundle and verifies it. Returns true if the repo is backed up.
+func bundleRepo(repo, dir string) bool {
+	name := filepath.Base(repo)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Printf(tr("  ❌ Backup %s: %v\n"), name, err)
+		return false
+	}
+
+	tips := refTips(repo)
+	if len(tips) == 0 {
+		return true // Empty repository
+	}
+
+	// With retention, start a new full chain once the current one is too old,
+	// so older chains can be dropped as a whole
+	full := false
+	if config.Backup.RetentionDays > 0 {
+		if start, ok := chainStart(bundleNames(dir)); ok && time.Since(start) > config.Backup.retention() {
+			full = true
+		}
+	}
+	suffix := ".bundle"
+	if full {
+		suffix = "-full.bundle"
+	}
+
+	args := []string{"bundle", "create"}
+	// Names sort in creation order, which is the order to restore them in
+	stamp := time.Now()
+	file := filepath.Join(dir, stamp.Format(bundleStampFormat)+suffix)
+	for _, err := os.Stat(file); err == nil; _, err = os.Stat(file) {
+		stamp = stamp.Add(time.Millisecond)
+		file = filepath.Join(dir, stamp.Format(bundleStampFormat)+suffix)
+	}
+	args = append(args, file, "--all")
+
+	// Incremental: exclude what the previous bundle already has
+	if data, err := os.ReadFile(filepath.Join(dir, lastRefsFile)); err == nil {
+		var known []string
+		for _, tip := range strings.Fields(string(data)) {
+			if gitIn(repo, "cat-file", "-e", tip) {
+				known = append(known, tip)
+			}
+		}
+		if sameTips(known, tips) {
+			touch(filepath.Join(dir, lastRefsFile))
+			return true // Nothing new
+		}
+		if len(known) > 0 && !full {
+			args = append(args, "--not")
+			args = append(args, known...)
+		}
+	}
+
+	cmd := gitCommand(args...)
+	cmd.Dir = repo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(output), "empty bundle") {
+			touch(filepath.Join(dir, lastRefsFile))
+			return true
+		}
+		fmt.Printf(tr("  ❌ Backup %s: %s\n"), name, lastLine(string(output)))
+		os.Remove(file)
+		return false
+	}
+
+	if !gitIn(repo, "bundle", "verify", "-q", file) {
+		fmt.Printf(tr("  ❌ Backup %s: bundle verification failed, removed %s\n"), name, filepath.Base(file))
+		os.Remove(file)
+		raiseAlert(repo, "backup", fmt.Sprintf(tr("%s: bundle backup failed verification"), name))
+		return false
+	}
+	clearAlert(repo, "backup")
+
+	if err := os.WriteFile(filepath.Join(dir, lastRefsFile), []byte(strings.Join(tips, "\n")+"\n"), 0644); err != nil {
+		fmt.Printf(tr("  ⚠️  Backup %s: %v\n"), name, err)
+	}
+	fmt.Printf(tr("  💾 Backed up %s to %s\n"), name, file)
+	return true
+}
+
+// bundleNames returns the bundle file names in a directory, oldest first
+func bundleNames(dir string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.bundle"))
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = filepath.Base(match)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bundleTime parses the creation time from a bundle name
+func bundleTime(name string) (time.Time, bool) {
+	if len(name) < len(bundleStampFormat) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(bundleStampFormat, name[:len(bundleStampFormat)], time.Local)
+	return t, err == nil
+}
+
+// bundleChains splits sorted bundle names into chains. Each chain starts with
+// a complete bundle: the first one, or one named *-full.bundle.
+func bundleChains(names []string) [][]string {
+	var chains [][]string
+	for i, name := range names {
+		if i == 0 || strings.HasSuffix(name, "-full.bundle") {
+			chains = append(chains, nil)
+		}
+		chains[len(chains)-1] = append(chains[len(chains)-1], name)
+	}
+	return chains
+}
+
+// chainStart returns when the newest chain began
+func chainStart(names []string) (time.Time, bool) {
+	chains := bundleChains(names)
+	if len(chains) == 0 {
+		return time.Time{}, false
+	}
+	return bundleTime(chains[len(chains)-1][0])
+}
+
+// expiredBundles returns the bundles of superseded chains whose newest bundle
+// is older than the retention period. The newest chain is always kept, since
+// it is needed to restore the current state.
+func expiredBundles(names []string, retention time.Duration) []string {
+	chains := bundleChains(names)
+	cutoff := time.Now().Add(-retention)
+	var expired []string
+	for i := 0; i < len(chains)-1; i++ {
+		chain := chains[i]
+		if last, ok := bundleTime(chain[len(chain)-1]); ok && last.Before(cutoff) {
+			expired = append(expired, chain...)
+		}
+	}
+	return expired
+}
+
+// uploadBundles copies bundles not uploaded yet to S3 and applies retention
+// there. Returns true if everything was uploaded.
+func uploadBundles(repo, dir string) bool {
+	name := filepath.Base(repo)
+	client, err := newS3Client(config.Backup.S3)
+	if err != nil {
+		fmt.Printf(tr("  ❌ Backup upload %s: %v\n"), name, err)
+		raiseAlert(repo, "upload", fmt.Sprintf(tr("%s: bundle upload failed: %v"), name, err))
+		return false
+	}
+
+	uploaded := map[string]bool{}
+	if data, err := os.ReadFile(filepath.Join(dir, uploadedFile)); err == nil {
+		for _, line := range strings.Fields(string(data)) {
+			uploaded[line] = true
+		}
+	}
+
+	remoteDir := filepath.Base(dir) + "/"
+	local := bundleNames(dir)
+	var done []string
+	failed := false
+	for _, bundle := range local {
+		if !uploaded[bundle] {
+			if err := client.put(client.key(remoteDir+bundle), filepath.Join(dir, bundle)); err != nil {
+				fmt.Printf(tr("  ❌ Backup upload %s: %v\n"), name, err)
+				raiseAlert(repo, "upload", fmt.Sprintf(tr("%s: bundle upload failed: %v"), name, err))
+				failed = true
+				break
+			}
+			fmt.Printf(tr("  ☁️  Uploaded %s/%s to s3://%s\n"), name, bundle, config.Backup.S3.Bucket)
+		}
+		done = append(done, bundle)
+	}
+	// Only local bundles are listed, so entries pruned by retention drop out
+	if len(done) > 0 || len(uploaded) > 0 {
+		if err := os.WriteFile(filepath.Join(dir, uploadedFile), []byte(strings.Join(done, "\n")+"\n"), 0644); err != nil {
+			fmt.Printf(tr("  ⚠️  Backup upload %s: %v\n"), name, err)
+		}
+	}
+	if failed {
+		return false
+	}
+	clearAlert(repo, "upload")
+
+	if config.Backup.RetentionDays > 0 {
+		keys, err := client.list(client.key(remoteDir))
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  Backup retention %s: %v\n"), name, err)
+			return true
+		}
+		var remote []string
+		for _, key := range keys {
+			if bundle := strings.TrimPrefix(key, client.key(remoteDir)); strings.HasSuffix(bundle, ".bundle") && !strings.Contains(bundle, "/") {
+				remote = append(remote, bundle)
+			}
+		}
+		for _, bundle := range expiredBundles(remote, config.Backup.retention()) {
+			if err := client.remove(client.key(remoteDir + bundle)); err != nil {
+				fmt.Printf(tr("  ⚠️  Backup retention %s: %v\n"), name, err)
+				break
+			}
+		}
+	}
+	return true
+}
+
+// refTips returns the object names all refs point to
+func refTips(repo string) []string {
+	cmd := gitCommand("for-each-ref", "--format=%(objectname)")
+	cmd.Dir = repo
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return uniqueStrings(strings.Fields(string(output)))
+}
+
+// sameTips reports whether two tip lists contain the same objects
+func sameTips(a, b []string) bool {
+	a, b = uniqueStrings(a), uniqueStrings(b)
+	if len(a) != len(b) {
+		return false
+	}
+	set := map[string]bool{}
+	for _, tip := range a {
+		set[tip] = true
+	}
+	for _, tip := range b {
+		if !set[tip] {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueStrings drops duplicates, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// gitIn runs a git command in the given directory and returns success
+func gitIn(dir string, args ...string) bool {
+	cmd := gitCommand(args...)
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// touch updates a file's modification time to now
+func touch(path string) {
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
+
+// runBackup implements `git-air backup [repo...]`, bundling right away
+func runBackup(args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	dir := fs.String("dir", "", "Backup directory (default: backup.dir from the config)")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air backup [--dir <path>] [repo...]"))
+		fmt.Println(tr("\nWrites an incremental git bundle of each repo (default: all below"))
+		fmt.Println(tr("the current directory), verifies it and uploads it if backup.s3 is set."))
+	}
+	repos := parseInterspersed(fs, args)
+
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	if *dir != "" {
+		config.Backup.Dir = *dir
+	}
+	if !config.Backup.enabled() {
+		fmt.Fprintln(os.Stderr, tr("❌ Error: no backup target, set backup.dir or backup.s3 in the config or use --dir"))
+		return 2
+	}
+
+	if len(repos) == 0 {
+		found, err := findGitRepos(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error finding repositories: %v\n"), err)
+			return 1
+		}
+		repos = found
+	}
+
+	status := 0
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil || !backupRepo(abs, backupRepoDir(abs)) {
+			status = 1
+		}
+	}
+	return status
+}
@@ -0,0 +1,223 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// benchRuns is how often status runs per repo, the best run counting as warm
+const benchRuns = 3
+
+// benchResult is what git-air bench measured for one repository
+type benchResult struct {
+	Repo       string
+	Filesystem string // network file system name, "" for local
+	Files      int    // tracked files
+	ColdStatus time.Duration
+	WarmStatus time.Duration
+	Analysis   time.Duration // deciding what a cycle would do with the status
+	Outcome    string
+	Remotes    map[string]time.Duration
+	Err        string
+}
+
+// total is the time a cycle spends on the repo without committing or pushing
+func (r benchResult) total() time.Duration {
+	total := r.WarmStatus + r.Analysis
+	for _, took := range r.Remotes {
+		total += took
+	}
+	return total
+}
+
+// runBench implements `git-air bench [--network] [repo...]`: time discovery,
+// status and a dry-run cycle per repo to find what makes cycles slow
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	intervalMins := fs.String("interval", "0.5", "Check interval to compare the cycle with, in minutes or as a duration (4h)")
+	fs.StringVar(intervalMins, "i", "0.5", "Check interval to compare the cycle with, in minutes or as a duration (4h)")
+	network := fs.Bool("network", false, "Also time an ls-remote against every remote")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air bench [-i <time>] [--network] [repo...]"))
+		fmt.Println(tr("\nTimes repository discovery, git status per repo (first and warm run) and"))
+		fmt.Println(tr("the decisions of a cycle, without committing, pushing or pulling anything,"))
+		fmt.Println(tr("and prints a per-repo breakdown, slowest first. Without repos, all repos"))
+		fmt.Println(tr("below the current directory."))
+		fmt.Println(tr("\nOPTIONS:"))
+		fs.PrintDefaults()
+	}
+	repos := parseInterspersed(fs, args)
+	interval, err := parseInterval(*intervalMins)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+
+	var discovery time.Duration
+	if len(repos) == 0 {
+		// The cached scan is what the daemon does every cycle, the full walk
+		// what it does when the cache is old or missing
+		start := time.Now()
+		found, err := findGitRepos(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error finding repositories: %v\n"), err)
+			return 1
+		}
+		discovery = time.Since(start)
+		start = time.Now()
+		gitair.WalkDiscoverer{}.Discover(".")
+		walk := time.Since(start)
+		fmt.Printf(tr("🔍 Discovery: %d repositories, cached scan %s, full walk %s\n"), len(found), benchDuration(discovery), benchDuration(walk))
+		repos = found
+	}
+	if len(repos) == 0 {
+		fmt.Println(tr("No repositories found."))
+		return 0
+	}
+
+	results := make([]benchResult, 0, len(repos))
+	for _, repo := range repos {
+		results = append(results, benchRepo(repo, *network))
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].total() > results[j].total() })
+	cycle := discovery
+	for _, result := range results {
+		printBenchResult(result)
+		cycle += result.total()
+	}
+	fmt.Printf(tr("\n⏱️  Estimated cycle: %s for %d repositories (interval %s)\n"), benchDuration(cycle), len(results), benchDuration(interval))
+	if slowest := results[0]; len(results) > 1 {
+		fmt.Printf(tr("  Slowest: %s with %s (%d%% of the cycle)\n"), displayName(slowest.Repo), benchDuration(slowest.total()), int(100*slowest.total()/max(cycle, 1)))
+	}
+	if cycle > interval/2 {
+		fmt.Println(tr("  ⚠️  Cycles take more than half the interval; check the slowest repos, move"))
+		fmt.Println(tr("     them off network file systems or set watchman, or raise the interval"))
+	}
+	return 0
+}
+
+// benchRepo measures one repository. Nothing is staged or committed and
+// nothing that could prompt (such as deletion holds) runs.
+func benchRepo(repoPath string, network bool) benchResult {
+	result := benchResult{Repo: repoPath}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		result.Err = err.Error()
+		return result
+	}
+	defer leave()
+
+	settings := settingsFor(repoPath)
+	result.Filesystem = networkFilesystem(repoDir)
+	if output, err := gitOutput("ls-files", "-z"); err == nil {
+		result.Files = strings.Count(output, "\x00")
+	}
+
+	// The first run pays for cold caches, like the first cycle after a while
+	var status []fileChange
+	for i := 0; i < benchRuns; i++ {
+		start := time.Now()
+		status, err = gitStatus()
+		if err != nil {
+			result.Err = fmt.Sprintf(tr("git status failed: %v"), err)
+			return result
+		}
+		took := time.Since(start)
+		if i == 0 {
+			result.ColdStatus = took
+		}
+		if i == 0 || took < result.WarmStatus {
+			result.WarmStatus = took
+		}
+	}
+
+	start := time.Now()
+	result.Outcome = benchOutcome(settings, status)
+	result.Analysis = time.Since(start)
+
+	if network {
+		result.Remotes = map[string]time.Duration{}
+		for _, remote := range getRemotes() {
+			url, _ := gitOutput("remote", "get-url", remote)
+			start := time.Now()
+			problem := probeRemote(remote, strings.TrimSpace(url))
+			result.Remotes[remote] = time.Since(start)
+			if problem != "" {
+				result.Err = fmt.Sprintf("%s: %s", remote, problem)
+			}
+		}
+	}
+	return result
+}
+
+// benchOutcome runs the checks processRepo makes on a status and says what
+// the cycle would do
+func benchOutcome(settings RepoSettings, status []fileChange) string {
+	changes := changedFiles(settings, status)
+	if settings.ignoreLineEndings() {
+		changes = withoutPaths(changes, lineEndingOnly(changes))
+	}
+	if len(changes) == 0 {
+		return tr("clean")
+	}
+	if len(triggeringChanges(changes, settings)) == 0 {
+		return tr("only churn, no commit")
+	}
+	if busy := filesInFlux(changePaths(changes), settings.settleTime()); len(busy) > 0 && len(immediatePaths(changes, settings.Immediate)) == 0 {
+		return fmt.Sprintf(tr("would wait, %s still being written"), busy[0])
+	}
+	if dir, count := largestUntrackedDir(changes, settings.untrackedLimit()); dir != "" {
+		return fmt.Sprintf(tr("would pause, %d new untracked files in %s"), count, dir)
+	}
+	mode := ""
+	if isMonorepo(repoDir) {
+		mode = tr(" (monorepo)")
+	}
+	return fmt.Sprintf(tr("would commit %s%s"), describeCount(changePaths(changes)), mode)
+}
+
+// printBenchResult prints the breakdown of one repository
+func printBenchResult(r benchResult) {
+	where := tr("local")
+	if r.Filesystem != "" {
+		where = r.Filesystem
+	}
+	fmt.Printf(tr("\n📁 %s (%s, %d tracked files)\n"), displayName(r.Repo), where, r.Files)
+	fmt.Printf(tr("  status:   %s first run, %s warm\n"), benchDuration(r.ColdStatus), benchDuration(r.WarmStatus))
+	fmt.Printf(tr("  dry run:  %s, %s\n"), benchDuration(r.Analysis), r.Outcome)
+	remotes := make([]string, 0, len(r.Remotes))
+	for remote := range r.Remotes {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		fmt.Printf("  %-9s %s\n", remote+":", benchDuration(r.Remotes[remote]))
+	}
+	if r.Err != "" {
+		fmt.Printf("  ❌ %s\n", r.Err)
+	}
+}
+
+// benchDuration rounds a duration for display
+func benchDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(100 * time.Microsecond).String()
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// branchCleanupInterval is how often a repo's git-air branches are checked
+const branchCleanupInterval = 24 * time.Hour
+
+// lastBranchCleanup records when each repo's branches were last checked
+var lastBranchCleanup = map[string]time.Time{}
+
+// staleBranches lists the refs below prefix (e.g. refs/heads/git-air/) that
+// are merged into HEAD or whose tip is older than retention (0 keeps them)
+func staleBranches(prefix string, retention time.Duration) []string {
+	output, err := gitCommand("for-each-ref", "--format=%(refname) %(committerdate:unix)", prefix).Output()
+	if err != nil {
+		return nil
+	}
+	var stale []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		ref, date, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(date, 10, 64)
+		expired := retention > 0 && time.Since(time.Unix(seconds, 0)) > retention
+		if expired || runGit("merge-base", "--is-ancestor", ref, "HEAD") {
+			stale = append(stale, ref)
+		}
+	}
+	return stale
+}
+
+// cleanStaleBranches deletes the rescue and other git-air/ branches of the
+// current repo once they are merged or past branch_retention_days, at most
+// once a day. Branches on remotes are only deleted with clean_remote_branches.
+func cleanStaleBranches(repoPath string, settings RepoSettings) {
+	if time.Since(lastBranchCleanup[repoPath]) < branchCleanupInterval {
+		return
+	}
+	lastBranchCleanup[repoPath] = time.Now()
+	retention := settings.branchRetention()
+
+	var deleted []string
+	for _, ref := range staleBranches("refs/heads/git-air/", retention) {
+		name := strings.TrimPrefix(ref, "refs/heads/")
+		if runGit("branch", "-D", name) {
+			deleted = append(deleted, name)
+		}
+	}
+
+	// Commits replaced by squash_push_minutes, kept for recovery
+	for _, ref := range staleBranches(unsquashedRefPrefix, retention) {
+		if runGit("update-ref", "-d", ref) {
+			deleted = append(deleted, strings.TrimPrefix(ref, "refs/"))
+		}
+	}
+
+	if settings.CleanRemoteBranches != nil && *settings.CleanRemoteBranches {
+		for _, remote := range getRemotes() {
+			for _, ref := range staleBranches("refs/remotes/"+remote+"/git-air/", retention) {
+				name := strings.TrimPrefix(ref, "refs/remotes/"+remote+"/")
+				if output, err := gitNetwork("push", remote, "--delete", name); err != nil {
+					fmt.Printf(tr("  ⚠️  %s: could not delete %s on %s: %s\n"), displayName(repoPath), name, remote, lastLine(output))
+					continue
+				}
+				deleted = append(deleted, remote+"/"+name)
+			}
+		}
+	}
+
+	if len(deleted) > 0 {
+		fmt.Printf(tr("  🧹 %s: deleted stale branch(es) %s\n"), displayName(repoPath), describeCount(deleted))
+	}
+}
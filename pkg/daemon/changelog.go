@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git-air/pkg/ai"
+)
+
+// logCommit is one commit as read from git log
+type logCommit struct {
+	Hash    string
+	Date    string
+	Subject string
+}
+
+// runChangelog implements `git-air changelog <repo> --since <date>`
+func runChangelog(args []string) int {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	since := fs.String("since", "1 week ago", "Start of the period (any date git understands)")
+	until := fs.String("until", "", "End of the period (default: now)")
+	noAI := fs.Bool("no-ai", false, "Do not summarize auto-commits with the AI provider")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air changelog [--since <date>] [--until <date>] [--no-ai] <repo>"))
+		fmt.Println(tr("\nPrints a Markdown changelog of the period, one section per day."))
+		fmt.Println(tr("Runs of auto-commits are summarized (by the AI provider if configured)."))
+	}
+	positional := parseInterspersed(fs, args)
+
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	repoPath := positional[0]
+
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+		return 1
+	}
+	defer leave()
+
+	commits, err := commitsBetween(*since, *until)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error reading history: %v\n"), err)
+		return 1
+	}
+
+	fmt.Printf(tr("# Changelog: %s (since %s)\n"), filepath.Base(repoDir), *since)
+	if len(commits) == 0 {
+		fmt.Println(tr("\nNo commits in this period."))
+		return 0
+	}
+
+	settings := settingsFor(repoPath)
+	useAI := settings.aiConfig().Enabled() && !*noAI
+	for _, day := range groupByDay(commits) {
+		fmt.Printf("\n## %s\n\n", day[0].Date)
+
+		var auto []logCommit
+		for _, commit := range day {
+			if isAutoCommit(commit.Subject) {
+				auto = append(auto, commit)
+				continue
+			}
+			fmt.Printf("- %s (%s)\n", commit.Subject, commit.Hash[:7])
+		}
+		if len(auto) > 0 {
+			for _, line := range summarizeAutoCommits(auto, useAI, settings) {
+				fmt.Printf("- %s\n", line)
+			}
+		}
+	}
+	return 0
+}
+
+// commitsBetween lists commits in the period, oldest first
+func commitsBetween(since, until string) ([]logCommit, error) {
+	args := []string{"log", "--reverse", "--date=short", "--format=%H%x1f%ad%x1f%s", "--since=" + since}
+	if until != "" {
+		args = append(args, "--until="+until)
+	}
+	cmd := gitCommand(args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []logCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\x1f", 3)
+		if len(fields) == 3 {
+			commits = append(commits, logCommit{Hash: fields[0], Date: fields[1], Subject: fields[2]})
+		}
+	}
+	return commits, nil
+}
+
+// groupByDay splits commits (oldest first) into one slice per date
+func groupByDay(commits []logCommit) [][]logCommit {
+	var days [][]logCommit
+	for _, commit := range commits {
+		if n := len(days); n > 0 && days[n-1][0].Date == commit.Date {
+			days[n-1] = append(days[n-1], commit)
+		} else {
+			days = append(days, []logCommit{commit})
+		}
+	}
+	return days
+}
+
+// summarizeAutoCommits describes a day's auto-commits in a few changelog lines,
+// using the AI provider when enabled and a file-based summary otherwise.
+// Generated files are left out of the diff the AI sees.
+func summarizeAutoCommits(commits []logCommit, useAI bool, settings RepoSettings) []string {
+	var stats, patches strings.Builder
+	for _, commit := range commits {
+		if output, err := gitCommand("show", "--stat", "--format=", commit.Hash).Output(); err == nil {
+			stats.Write(output)
+		}
+		if patches.Len() < 12000 {
+			args := []string{"show", "-M", "-C", "--format=", commit.Hash}
+			if names, err := gitCommand("show", "--name-only", "-z", "--format=", commit.Hash).Output(); err == nil {
+				if pathspecs, _ := withoutGenerated(nulList(names), settings.Generated); len(pathspecs) > 0 {
+					args = append(append(args, "--"), pathspecs...)
+				}
+			}
+			if output, err := gitCommand(args...).Output(); err == nil {
+				patches.Write(output)
+			}
+		}
+	}
+
+	if useAI {
+		diff := patches.String()
+		if len(diff) > 12000 {
+			diff = diff[:12000] + "\n[diff truncated]"
+		}
+		system := "You write changelogs. Summarize the changes below as 1-4 short changelog " +
+			"bullet points in plain English, one per line, without leading dashes or commentary."
+		reply, err := ai.Complete(settings.aiConfig(), system, fmt.Sprintf("Diffstat:\n%s\nDiff:\n%s", stats.String(), diff))
+		if err == nil && reply == "" {
+			err = fmt.Errorf("empty reply")
+		}
+		if err == nil {
+			var lines []string
+			for _, line := range strings.Split(reply, "\n") {
+				if line = strings.TrimSpace(strings.TrimLeft(line, "-*• ")); line != "" {
+					lines = append(lines, line)
+				}
+			}
+			return lines
+		}
+		fmt.Fprintf(os.Stderr, tr("⚠️  AI summary unavailable: %v\n"), err)
+	}
+
+	// Fallback: name the files the auto-commits touched
+	files := map[string]bool{}
+	var order []string
+	for _, line := range strings.Split(stats.String(), "\n") {
+		if file, _, ok := strings.Cut(line, "|"); ok {
+			file = strings.TrimSpace(file)
+			if !files[file] {
+				files[file] = true
+				order = append(order, file)
+			}
+		}
+	}
+	if len(order) > 8 {
+		order = append(order[:8], fmt.Sprintf("and %d more", len(order)-8))
+	}
+	return []string{fmt.Sprintf("%d auto-commits updating %s", len(commits), strings.Join(order, ", "))}
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CI states as reported by ciStatus
+const (
+	ciSuccess = "success"
+	ciFailure = "failure"
+	ciPending = "pending"
+	ciNone    = "" // No pipeline for the commit
+)
+
+// ciStatus returns the combined CI state of a commit on a forge
+func ciStatus(repo forgeRepo, sha string) (string, error) {
+	switch repo.Forge.Type {
+	case "github":
+		return githubCIStatus(repo, sha)
+	case "gitlab":
+		var commit struct {
+			LastPipeline *struct {
+				Status string `json:"status"`
+			} `json:"last_pipeline"`
+		}
+		if err := repo.get(repo.projectID()+"/repository/commits/"+sha, &commit); err != nil {
+			return ciNone, err
+		}
+		if commit.LastPipeline == nil {
+			return ciNone, nil
+		}
+		switch commit.LastPipeline.Status {
+		case "success", "skipped", "manual":
+			return ciSuccess, nil
+		case "failed", "canceled":
+			return ciFailure, nil
+		default:
+			return ciPending, nil
+		}
+	case "gitea":
+		var status struct {
+			State      string `json:"state"`
+			TotalCount int    `json:"total_count"`
+		}
+		if err := repo.get(repo.ownerRepo()+"/commits/"+sha+"/status", &status); err != nil {
+			return ciNone, err
+		}
+		if status.TotalCount == 0 {
+			return ciNone, nil
+		}
+		return commitStatusState(status.State), nil
+	}
+	return ciNone, fmt.Errorf("unknown forge type %q", repo.Forge.Type)
+}
+
+// githubCIStatus combines legacy commit statuses and check runs
+func githubCIStatus(repo forgeRepo, sha string) (string, error) {
+	var status struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	if err := repo.get(repo.ownerRepo()+"/commits/"+sha+"/status", &status); err != nil {
+		return ciNone, err
+	}
+	var checks struct {
+		TotalCount int `json:"total_count"`
+		CheckRuns  []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := repo.get(repo.ownerRepo()+"/commits/"+sha+"/check-runs?per_page=100", &checks); err != nil {
+		return ciNone, err
+	}
+
+	states := []string{}
+	if status.TotalCount > 0 {
+		states = append(states, commitStatusState(status.State))
+	}
+	for _, run := range checks.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			states = append(states, ciPending)
+		case run.Conclusion == "failure" || run.Conclusion == "timed_out" || run.Conclusion == "cancelled" || run.Conclusion == "action_required":
+			states = append(states, ciFailure)
+		default:
+			states = append(states, ciSuccess)
+		}
+	}
+	return worstCIState(states), nil
+}
+
+// commitStatusState maps commit status API states (GitHub, Gitea) to CI states
+func commitStatusState(state string) string {
+	switch state {
+	case "success", "warning":
+		return ciSuccess
+	case "failure", "error":
+		return ciFailure
+	default:
+		return ciPending
+	}
+}
+
+// worstCIState returns failure over pending over success
+func worstCIState(states []string) string {
+	result := ciNone
+	for _, state := range states {
+		switch {
+		case state == ciFailure:
+			return ciFailure
+		case state == ciPending:
+			result = ciPending
+		case result == ciNone:
+			result = state
+		}
+	}
+	return result
+}
+
+// ciAllowsPull checks the CI state of the remote head before pulling it.
+// Red or still running pipelines delay the pull; commits without CI and
+// unreachable APIs don't block.
+func ciAllowsPull(repoPath, remote, branch string) bool {
+	url := gitConfigValue("remote." + remote + ".url")
+	repo, ok := remoteForge(url)
+	if !ok {
+		return true
+	}
+
+	sha, err := gitOutput("rev-parse", remote+"/"+branch)
+	if err != nil {
+		return true
+	}
+	sha = strings.TrimSpace(sha)
+	repoName := filepath.Base(repoPath)
+
+	state, err := ciStatus(repo, sha)
+	if err != nil {
+		fmt.Printf(tr("  ⚠️  %s: CI status of %s/%s unavailable (%v), pulling anyway\n"), repoName, remote, branch, err)
+		return true
+	}
+	switch state {
+	case ciFailure:
+		fmt.Printf(tr("  🔴 %s: CI failed for %s/%s at %s, not pulling\n"), repoName, remote, branch, sha[:7])
+		raiseAlert(repoPath, "ci", fmt.Sprintf(tr("%s: pull from %s delayed, CI failed for %s"), displayName(repoPath), remote, sha[:7]))
+		return false
+	case ciPending:
+		fmt.Printf(tr("  🟡 %s: CI still running for %s/%s, pulling later\n"), repoName, remote, branch)
+		return false
+	}
+	clearAlert(repoPath, "ci")
+	return true
+}
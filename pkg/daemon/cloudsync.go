@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cloudMarkers are files or directories that sync clients keep at the top
+// of the folders they manage. fileOnly markers don't count as directories:
+// ~/.dropbox is the Dropbox settings dir, not a synced folder.
+var cloudMarkers = []struct {
+	name, service string
+	fileOnly      bool
+}{
+	{".dropbox", "Dropbox", true},
+	{".dropbox.cache", "Dropbox", false},
+	{".stfolder", "Syncthing", false},
+}
+
+// cloudSyncWarned remembers the repos reported, so each is reported once
+var cloudSyncWarned = map[string]bool{}
+
+// cloudSyncService names the sync client managing a directory (Dropbox,
+// iCloud Drive, OneDrive, Google Drive, Syncthing), found through the marker
+// files of its sync root or the well-known folder names, or "" if none does
+func cloudSyncService(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		for _, marker := range cloudMarkers {
+			if info, err := os.Lstat(filepath.Join(dir, marker.name)); err == nil && !(marker.fileOnly && info.IsDir()) {
+				return marker.service
+			}
+		}
+		name, parent := filepath.Base(dir), filepath.Base(filepath.Dir(dir))
+		switch {
+		case name == "OneDrive" || strings.HasPrefix(name, "OneDrive - "):
+			return "OneDrive"
+		case name == "iCloudDrive" || name == "Mobile Documents" && parent == "Library":
+			return "iCloud Drive"
+		case name == "Google Drive" || name == "GoogleDrive":
+			return "Google Drive"
+		case parent == "CloudStorage":
+			// macOS File Provider roots: OneDrive-Personal, GoogleDrive-me@example.com, Dropbox
+			service, _, _ := strings.Cut(name, "-")
+			return service
+		}
+		if filepath.Dir(dir) == dir {
+			return ""
+		}
+	}
+}
+
+// excludeCloudSynced drops the repos inside a cloud-sync folder unless their
+// allow_cloud_sync setting is on: the sync client and git both rewrite files
+// in .git, and a half-synced object or index is a corrupted repository
+func excludeCloudSynced(repos []string) []string {
+	var kept []string
+	for _, repo := range repos {
+		service := cloudSyncService(repo)
+		if service == "" {
+			kept = append(kept, repo)
+			continue
+		}
+		allowed := settingsFor(repo).AllowCloudSync
+		if allowed != nil && *allowed {
+			if !cloudSyncWarned[repo] {
+				fmt.Printf(tr("  ☁️  %s is inside %s - syncing anyway (allow_cloud_sync), pause %s while git-air commits if you can\n"), displayName(repo), service, service)
+			}
+			cloudSyncWarned[repo] = true
+			kept = append(kept, repo)
+			continue
+		}
+		if !cloudSyncWarned[repo] {
+			fmt.Printf(tr("  ☁️  Skipping %s - it is inside %s, and two sync tools writing .git corrupt repositories\n"), displayName(repo), service)
+		}
+		cloudSyncWarned[repo] = true
+		raiseAlert(repo, "cloud-sync", fmt.Sprintf(tr("%s: skipped, the repository is inside %s. Move it out or set allow_cloud_sync for it"), displayName(repo), service))
+	}
+	return kept
+}
@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// autoCommitsOnly reports whether every commit in a revision range was
+// written by git-air
+func autoCommitsOnly(revisions string) bool {
+	output, err := gitCommand("log", "--format=%B%x00", revisions).Output()
+	if err != nil {
+		return false
+	}
+	for _, message := range strings.Split(string(output), "\x00") {
+		if message = strings.TrimSpace(message); message != "" && !isAutoCommit(message) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveCollision merges remote/branch into a diverged HEAD, settling
+// conflicting hunks for one side as the conflicts policy says. It only
+// acts when both sides hold nothing but git-air commits, so edits someone
+// made by hand are never overwritten. The merge is pushed right away.
+func resolveCollision(repoPath, remote, branch, policy string) bool {
+	upstream := remote + "/" + branch
+	base, err := gitOutput("merge-base", "HEAD", upstream)
+	if err != nil {
+		return false
+	}
+	base = strings.TrimSpace(base)
+	if !autoCommitsOnly(base+"..HEAD") || !autoCommitsOnly(base+".."+upstream) {
+		return false
+	}
+
+	runGit("merge", "--abort")
+	runGit("rebase", "--abort")
+	side := "theirs"
+	if policy == "prefer-local" {
+		side = "ours"
+	}
+	message := withTrailers("Merge "+upstream, []string{"Git-Air-Conflicts: " + policy})
+	if output, err := gitOutput("merge", "--no-edit", "-X", side, "-m", message, upstream); err != nil {
+		runGit("merge", "--abort") // e.g. a file deleted on one side, left to the rescue
+		fmt.Printf(tr("  ⚠️  %s: could not merge %s with %s: %s\n"), displayName(repoPath), upstream, policy, lastLine(output))
+		return false
+	}
+	fmt.Printf(tr("  🤝 %s: merged concurrent auto-commits from %s (%s)\n"), displayName(repoPath), upstream, policy)
+	pushToAllRemotes(repoPath) // The other machine gets the merge without waiting for the next commit
+	return true
+}
@@ -0,0 +1,314 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// lintRule is one commitlint rule: [level, "always"|"never", value].
+// Level 0 disables the rule, 1 is a warning, 2 an error.
+type lintRule struct {
+	Level int
+	When  string
+	Value interface{}
+}
+
+// lintRules maps commitlint rule names to their settings
+type lintRules map[string]lintRule
+
+// commitlintFiles are the JSON commitlint configs looked up in a repository
+var commitlintFiles = []string{".commitlintrc.json", ".commitlintrc"}
+
+// conventionalRules mirrors @commitlint/config-conventional
+var conventionalRules = lintRules{
+	"body-leading-blank":     {1, "always", nil},
+	"body-max-line-length":   {2, "always", 100.0},
+	"footer-leading-blank":   {1, "always", nil},
+	"footer-max-line-length": {2, "always", 100.0},
+	"header-max-length":      {2, "always", 100.0},
+	"subject-case":           {2, "never", []interface{}{"sentence-case", "start-case", "pascal-case", "upper-case"}},
+	"subject-empty":          {2, "never", nil},
+	"subject-full-stop":      {2, "never", "."},
+	"type-case":              {2, "always", "lower-case"},
+	"type-empty":             {2, "never", nil},
+	"type-enum": {2, "always", []interface{}{
+		"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+	}},
+}
+
+// headerPattern splits a conventional commit header into type, scope and subject
+var headerPattern = regexp.MustCompile(`^(\w*)(?:\(([^)]*)\))?!?: (.*)$`)
+
+// UnmarshalJSON reads the commitlint array form [level, when, value]
+func (r *lintRule) UnmarshalJSON(data []byte) error {
+	var parts []interface{}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("empty rule")
+	}
+	level, ok := parts[0].(float64)
+	if !ok {
+		return fmt.Errorf("rule level must be 0, 1 or 2")
+	}
+	r.Level = int(level)
+	r.When = "always"
+	if len(parts) > 1 {
+		if when, ok := parts[1].(string); ok {
+			r.When = when
+		}
+	}
+	if len(parts) > 2 {
+		r.Value = parts[2]
+	}
+	return nil
+}
+
+// loadCommitlint returns the rules for the current repo: "conventional" for
+// the built-in @commitlint/config-conventional, a path to a JSON commitlint
+// config, or "" to look for .commitlintrc.json. nil means no rules.
+func loadCommitlint(setting string) (lintRules, error) {
+	if setting == "conventional" {
+		return conventionalRules, nil
+	}
+
+	file := setting
+	if file == "" {
+		for _, name := range commitlintFiles {
+			if _, err := os.Stat(inRepo(name)); err == nil {
+				file = name
+				break
+			}
+		}
+		if file == "" {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(inRepo(expandHome(file)))
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Extends interface{}         `json:"extends"`
+		Rules   map[string]lintRule `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v (only JSON commitlint configs are supported)", file, err)
+	}
+
+	rules := lintRules{}
+	extends := fmt.Sprint(cfg.Extends)
+	if strings.Contains(extends, "config-conventional") {
+		for name, rule := range conventionalRules {
+			rules[name] = rule
+		}
+	}
+	for name, rule := range cfg.Rules {
+		rules[name] = rule
+	}
+	return rules, nil
+}
+
+// violations returns the error-level rules a commit message breaks
+func (rules lintRules) violations(message string) []string {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	header := lines[0]
+	var body []string
+	if len(lines) > 1 {
+		body = lines[1:]
+	}
+
+	typ, scope, subject := "", "", header
+	if match := headerPattern.FindStringSubmatch(header); match != nil {
+		typ, scope, subject = match[1], match[2], match[3]
+	}
+
+	var problems []string
+	get := func(name string) lintRule { return rules[name] }
+	check := func(name string, ok bool, describe func(rule lintRule) string) {
+		rule, found := rules[name]
+		if !found || rule.Level < 2 {
+			return
+		}
+		if rule.When == "never" {
+			ok = !ok
+		}
+		if !ok {
+			problems = append(problems, name+": "+describe(rule))
+		}
+	}
+
+	check("type-empty", typ == "", func(rule lintRule) string { return whenText(rule, "type must be empty", "type may not be empty") })
+	check("type-enum", typ == "" || inValues(get("type-enum"), typ), func(rule lintRule) string {
+		return whenText(rule, "type must be one of "+valuesText(rule.Value), "type must not be one of "+valuesText(rule.Value))
+	})
+	check("type-case", typ == "" || hasCase(typ, get("type-case")), func(rule lintRule) string {
+		return whenText(rule, "type must be "+valuesText(rule.Value), "type must not be "+valuesText(rule.Value))
+	})
+	check("scope-empty", scope == "", func(rule lintRule) string { return whenText(rule, "scope must be empty", "scope may not be empty") })
+	check("scope-enum", scope == "" || inValues(get("scope-enum"), scope), func(rule lintRule) string {
+		return whenText(rule, "scope must be one of "+valuesText(rule.Value), "scope must not be one of "+valuesText(rule.Value))
+	})
+	check("scope-case", scope == "" || hasCase(scope, get("scope-case")), func(rule lintRule) string {
+		return whenText(rule, "scope must be "+valuesText(rule.Value), "scope must not be "+valuesText(rule.Value))
+	})
+	check("subject-empty", subject == "", func(rule lintRule) string { return whenText(rule, "subject must be empty", "subject may not be empty") })
+	check("subject-case", subject == "" || hasCase(subject, get("subject-case")), func(rule lintRule) string {
+		return whenText(rule, "subject must be "+valuesText(rule.Value), "subject must not be "+valuesText(rule.Value))
+	})
+	check("subject-full-stop", strings.HasSuffix(subject, valueString(get("subject-full-stop"), ".")), func(rule lintRule) string {
+		return whenText(rule, "subject must end with "+valueString(rule, "."), "subject may not end with "+valueString(rule, "."))
+	})
+	check("header-max-length", runeLen(header) <= valueInt(get("header-max-length")), func(rule lintRule) string {
+		return fmt.Sprintf("header must not be longer than %d characters", valueInt(rule))
+	})
+	check("header-min-length", runeLen(header) >= valueInt(get("header-min-length")), func(rule lintRule) string {
+		return fmt.Sprintf("header must be at least %d characters", valueInt(rule))
+	})
+	check("subject-max-length", runeLen(subject) <= valueInt(get("subject-max-length")), func(rule lintRule) string {
+		return fmt.Sprintf("subject must not be longer than %d characters", valueInt(rule))
+	})
+	check("body-leading-blank", len(body) == 0 || body[0] == "", func(rule lintRule) string { return "body must have a leading blank line" })
+	check("body-max-line-length", maxLineLength(body) <= valueInt(get("body-max-line-length")), func(rule lintRule) string {
+		return fmt.Sprintf("body lines must not be longer than %d characters", valueInt(rule))
+	})
+	return problems
+}
+
+// whenText picks the description for "always" or "never" rules
+func whenText(rule lintRule, always, never string) string {
+	if rule.When == "never" {
+		return never
+	}
+	return always
+}
+
+// inValues reports whether value is listed in the rule's value array
+func inValues(rule lintRule, value string) bool {
+	for _, allowed := range ruleValues(rule) {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleValues returns a rule value as a list of strings
+func ruleValues(rule lintRule) []string {
+	switch v := rule.Value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+		return values
+	case []string:
+		return v
+	}
+	return nil
+}
+
+// valuesText renders a rule value for messages and prompts
+func valuesText(value interface{}) string {
+	return strings.Join(ruleValues(lintRule{Value: value}), ", ")
+}
+
+func valueString(rule lintRule, fallback string) string {
+	if s, ok := rule.Value.(string); ok {
+		return s
+	}
+	return fallback
+}
+
+func valueInt(rule lintRule) int {
+	if n, ok := rule.Value.(float64); ok {
+		return int(n)
+	}
+	return 0
+}
+
+// hasCase reports whether text matches any of the commitlint cases in the rule
+func hasCase(text string, rule lintRule) bool {
+	for _, c := range ruleValues(rule) {
+		if isCase(text, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCase implements the commitlint case names
+func isCase(text, c string) bool {
+	if text == "" {
+		return false
+	}
+	words := strings.Fields(text)
+	first := []rune(text)[0]
+	switch c {
+	case "lower-case", "lowercase":
+		return text == strings.ToLower(text)
+	case "upper-case", "uppercase":
+		return text == strings.ToUpper(text)
+	case "sentence-case", "sentencecase":
+		return unicode.IsUpper(first) && text[len(string(first)):] == strings.ToLower(text[len(string(first)):])
+	case "start-case", "startcase":
+		for _, word := range words {
+			if w := []rune(word); !unicode.IsUpper(w[0]) {
+				return false
+			}
+		}
+		return len(words) > 0
+	case "pascal-case", "pascalcase":
+		return len(words) == 1 && unicode.IsUpper(first) && !strings.ContainsAny(text, "-_")
+	case "camel-case", "camelcase":
+		return len(words) == 1 && unicode.IsLower(first) && !strings.ContainsAny(text, "-_")
+	case "kebab-case":
+		return text == strings.ToLower(text) && !strings.ContainsAny(text, " _")
+	case "snake-case":
+		return text == strings.ToLower(text) && !strings.ContainsAny(text, " -")
+	}
+	return false
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+func maxLineLength(lines []string) int {
+	longest := 0
+	for _, line := range lines {
+		if n := runeLen(line); n > longest {
+			longest = n
+		}
+	}
+	return longest
+}
+
+// promptText describes the error-level rules for the AI prompt
+func (rules lintRules) promptText() string {
+	var parts []string
+	if rule, ok := rules["type-enum"]; ok && rule.Level == 2 && rule.When == "always" {
+		parts = append(parts, "Use the Conventional Commits header format \"type(optional scope): subject\" with type one of: "+valuesText(rule.Value)+".")
+	}
+	if rule, ok := rules["scope-enum"]; ok && rule.Level == 2 && rule.When == "always" {
+		parts = append(parts, "If you use a scope, it must be one of: "+valuesText(rule.Value)+".")
+	}
+	if rule, ok := rules["header-max-length"]; ok && rule.Level == 2 {
+		parts = append(parts, fmt.Sprintf("The first line must be at most %d characters.", valueInt(rule)))
+	}
+	if rule, ok := rules["subject-case"]; ok && rule.Level == 2 {
+		parts = append(parts, "The subject "+whenText(rule, "must be one of: ", "must not be: ")+valuesText(rule.Value)+".")
+	}
+	if rule, ok := rules["subject-full-stop"]; ok && rule.Level == 2 && rule.When == "never" {
+		parts = append(parts, "Do not end the subject with a period.")
+	}
+	return strings.Join(parts, " ")
+}
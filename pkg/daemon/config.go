@@ -0,0 +1,1021 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config is the optional git-air configuration file. Top-level settings
+// apply to every repository; entries in Repos override them for the
+// repositories (or groups of repositories) they match.
+type Config struct {
+	RepoSettings
+	Repos   []RepoRule    `json:"repos,omitempty"`
+	Notify  NotifyConfig  `json:"notify,omitempty"`
+	AI      AIConfig      `json:"ai,omitempty"`
+	Adopt   []AdoptDir    `json:"adopt,omitempty"`
+	Digest  DigestConfig  `json:"digest,omitempty"`
+	Backup  BackupConfig  `json:"backup,omitempty"`
+	Serve   ServeConfig   `json:"serve,omitempty"`
+	Trigger TriggerConfig `json:"trigger,omitempty"`
+	API     APIConfig     `json:"api,omitempty"`
+	Jira    JiraConfig    `json:"jira,omitempty"`
+	Power   PowerConfig   `json:"power,omitempty"`
+
+	// Forges maps remote hosts to forge APIs, for self-hosted GitLab, Gitea
+	// or GitHub Enterprise and for tokens. github.com, gitlab.com and
+	// codeberg.org are known.
+	Forges map[string]Forge `json:"forges,omitempty"`
+
+	// LowPriority runs git-air, and so every git process, with reduced CPU
+	// and I/O priority, like --low-priority
+	LowPriority bool `json:"low_priority,omitempty"`
+
+	// JitterSeconds delays the first cycle, every sleep and every pull by a
+	// random 0 to this many seconds, so a fleet of machines doesn't push
+	// and pull in lockstep
+	JitterSeconds Seconds `json:"jitter_seconds,omitempty"`
+
+	// Stagger spreads the repos of a cycle evenly across the interval
+	// instead of checking them all at once and then sleeping
+	Stagger bool `json:"stagger,omitempty"`
+
+	// Monorepo is the default for -mr; the check interval is the top-level
+	// interval of RepoSettings
+	Monorepo bool `json:"monorepo,omitempty"`
+
+	// PIDFile and LogFile are where --daemon writes its PID and output
+	PIDFile string `json:"pid_file,omitempty"`
+	LogFile string `json:"log_file,omitempty"`
+
+	// Workers is the default for --workers
+	Workers int `json:"workers,omitempty"`
+
+	// Watch is the default for --watch
+	Watch bool `json:"watch,omitempty"`
+
+	// Exclude lists repos that are never synced, as globs matched like the
+	// match of repos rules
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Locale selects the language of messages ("de", "en"); empty follows
+	// LC_ALL, LC_MESSAGES or LANG
+	Locale string `json:"locale,omitempty"`
+}
+
+// RepoRule applies settings to repos whose path (relative to the scan root)
+// or directory name matches the glob in Match, e.g. "clients/*" or "notes".
+// Later rules win over earlier ones.
+type RepoRule struct {
+	Match string `json:"match"`
+	RepoSettings
+}
+
+// RepoSettings holds everything that can be set per repository.
+type RepoSettings struct {
+	Identity Identity `json:"identity,omitempty"`
+
+	// TransientPatterns replaces the default list of editor and OS junk
+	// files that are never staged. An empty list disables the filter.
+	TransientPatterns []string `json:"transient_patterns,omitempty"`
+
+	// SettleSeconds defers a repo while any changed file is younger than
+	// this (default 5), 0 disables the check
+	SettleSeconds *Seconds `json:"settle_seconds,omitempty"`
+
+	// UntrackedLimit pauses a repo when staging would add more new files
+	// than this (default 1000), 0 disables the check
+	UntrackedLimit *int `json:"untracked_limit,omitempty"`
+
+	// Template is applied to directories when they are adopted
+	Template *Template `json:"template,omitempty"`
+
+	// TagVersions creates and pushes an annotated tag (TagPrefix + version,
+	// default prefix "v") when a commit changes package.json, Cargo.toml or VERSION
+	TagVersions *bool   `json:"tag_versions,omitempty"`
+	TagPrefix   *string `json:"tag_prefix,omitempty"`
+
+	// TimeTrailer adds a Time-Spent trailer with the active time since the previous commit
+	TimeTrailer *bool `json:"time_trailer,omitempty"`
+
+	// HostTrailer stamps auto-commits with the machine that made them:
+	// "host" (default) adds Git-Air-Host, "full" also Git-Air-OS and
+	// Git-Air-User, "off" none
+	HostTrailer *string `json:"host_trailer,omitempty"`
+
+	// Encrypt commits matching files only as age ciphertext (<file>.age)
+	Encrypt *Encrypt `json:"encrypt,omitempty"`
+
+	// WaitForCI delays pulls while the forge reports a red or running
+	// pipeline for the remote head
+	WaitForCI *bool `json:"wait_for_ci,omitempty"`
+
+	// JiraIssue links commits to this issue instead of the one in the branch name
+	JiraIssue *string `json:"jira_issue,omitempty"`
+
+	// IssueLinks adds "Refs #42"-style lines for GitHub Issues or Linear
+	IssueLinks *IssueLinks `json:"issue_links,omitempty"`
+
+	// AIMessages has the AI provider write commit messages from the staged diff
+	AIMessages *bool `json:"ai_messages,omitempty"`
+
+	// AIProvider overrides the global ai settings for these repos, e.g. a
+	// local model for client work; fields left out keep the global values
+	// unless the provider differs
+	AIProvider *AIConfig `json:"ai_provider,omitempty"`
+
+	// Commitlint selects the rules commit messages must pass: "conventional",
+	// a path to a JSON commitlint config, or "off". By default AI messages
+	// use the repo's .commitlintrc.json if there is one.
+	Commitlint *string `json:"commitlint,omitempty"`
+
+	// SubjectLength caps generated subject lines (default 72) and BodyWidth
+	// wraps their bodies (default 72); 0 disables either
+	SubjectLength *int `json:"subject_length,omitempty"`
+	BodyWidth     *int `json:"body_width,omitempty"`
+
+	// IgnoreWhitespace keeps whitespace and line-ending changes from
+	// triggering a commit; they are committed along with other changes
+	IgnoreWhitespace *bool `json:"ignore_whitespace,omitempty"`
+
+	// IgnoreLineEndings (default true) leaves files alone whose only change
+	// is CRLF vs LF, e.g. from a core.autocrlf mismatch; they are neither
+	// committed nor count as changes
+	IgnoreLineEndings *bool `json:"ignore_line_endings,omitempty"`
+
+	// IgnoreDiffs keeps changes whose lines all match a pattern from
+	// triggering a commit; they are committed along with other changes
+	IgnoreDiffs []DiffRule `json:"ignore_diffs,omitempty"`
+
+	// Generated lists generated paths in addition to files marked
+	// linguist-generated. Their diffs are never sent to the AI provider, and
+	// with IgnoreGenerated their changes don't trigger commits either.
+	Generated       []string `json:"generated,omitempty"`
+	IgnoreGenerated *bool    `json:"ignore_generated,omitempty"`
+
+	// NeverCommit lists globs such as "drafts/**" whose changes are never
+	// staged, for private scratch files inside synced repos
+	NeverCommit []string `json:"never_commit,omitempty"`
+
+	// Policies hold back commits or pushes while their expression is true
+	Policies []Policy `json:"policies,omitempty"`
+
+	// TrackedOnly stages with git add -u: changes to tracked files are
+	// committed, new files only once someone adds them by hand
+	TrackedOnly *bool `json:"tracked_only,omitempty"`
+
+	// Untracked is the policy for new files: "add" (default) commits them,
+	// "ignore" leaves them alone like TrackedOnly, "report" also raises an
+	// alert listing them
+	Untracked *string `json:"untracked,omitempty"`
+
+	// Deletions is the policy for deleted files: "commit" (default),
+	// "delay" to commit them once they have stayed deleted for
+	// DeletionDelayMinutes (default 10), or "confirm" to wait for the user
+	Deletions            *string  `json:"deletions,omitempty"`
+	DeletionDelayMinutes *Minutes `json:"deletion_delay_minutes,omitempty"`
+
+	// Capture is "commit" (default) to commit changes as they settle,
+	// "queue" to only snapshot them for git-air review to commit in batches,
+	// "stash" to keep the snapshots as stash entries, or "snapshot" to
+	// chain them under refs/git-air/snapshots/ and push them; the last two
+	// never commit
+	Capture *string `json:"capture,omitempty"`
+
+	// Retention thins out stash and snapshot mode snapshots as they age
+	Retention *Retention `json:"retention,omitempty"`
+
+	// Watchman asks a running Watchman service which files changed and
+	// skips git status while nothing did, for trees too big to scan every cycle
+	Watchman *bool `json:"watchman,omitempty"`
+
+	// Pull is "auto" (default) to merge remote changes as they arrive, or
+	// "review" to only fetch and report them until git-air pull is run
+	Pull *string `json:"pull,omitempty"`
+
+	// AfterPull are commands run in the repo after a pull brought in commits,
+	// e.g. npm install when package-lock.json changed
+	AfterPull []PullHook `json:"after_pull,omitempty"`
+
+	// Immediate patterns (as in transient_patterns) mark files whose changes
+	// are synced right away: checked every few seconds between cycles, and
+	// committed without waiting for SettleSeconds
+	Immediate []string `json:"immediate,omitempty"`
+
+	// Priority orders the repos within a cycle, higher first (default 0)
+	Priority *int `json:"priority,omitempty"`
+
+	// DependsOn names the managed repos this one depends on (globs matched
+	// like Match). They are committed and pushed first, and pulled after it;
+	// its submodules count as dependencies anyway.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Conflicts settles pulls that collide with local auto-commits when both
+	// sides are git-air commits only: "prefer-remote" or "prefer-local" merge
+	// with conflicting hunks taken from that side, "manual" (default) rescues
+	// the local commits to a branch instead
+	Conflicts *string `json:"conflicts,omitempty"`
+
+	// BranchRetentionDays deletes git-air/ branches (diverged rescues) whose
+	// tip is older than this (default 90, 0 keeps them); merged ones always go.
+	// CleanRemoteBranches deletes them on the remotes too.
+	BranchRetentionDays *int  `json:"branch_retention_days,omitempty"`
+	CleanRemoteBranches *bool `json:"clean_remote_branches,omitempty"`
+
+	// MachineBranch commits to machines/<hostname> instead of the shared
+	// branch (SharedBranch, default "main"); git-air merge-machines combines
+	// the machine branches and updates the shared one
+	MachineBranch *bool   `json:"machine_branch,omitempty"`
+	SharedBranch  *string `json:"shared_branch,omitempty"`
+
+	// Branch is the branch pushed and pulled (default: the one checked
+	// out). While another branch is checked out, pushes and pulls are held
+	// and an alert says so; git-air never switches branches for it.
+	Branch *string `json:"branch,omitempty"`
+
+	// SquashPushMinutes holds pushes until the oldest unpushed commit is
+	// this old and then squashes the unpushed auto-commits into one, so the
+	// remote gets one commit per period while the local ones stay
+	// recoverable; 0 (default) pushes every commit
+	SquashPushMinutes *Minutes `json:"squash_push_minutes,omitempty"`
+
+	// Interval is how often the repo is checked. At the top level it is the
+	// check interval (-i wins); in repos rules and .gitair.yml it can only
+	// make a repo's checks less frequent than that.
+	Interval *Minutes `json:"interval,omitempty"`
+
+	// AutoCommit false leaves the repo's changes alone while pulls go on,
+	// for repos that should only receive
+	AutoCommit *bool `json:"auto_commit,omitempty"`
+
+	// MessageTemplate replaces the timestamp message of auto-commits, with
+	// {repo}, {files}, {date}, {time} and {host} filled in
+	MessageTemplate *string `json:"message_template,omitempty"`
+
+	// Notes records sync metadata per commit in refs/notes/git-air: "off"
+	// (default), "local" or "push" to also push and fetch the notes
+	Notes *string `json:"notes,omitempty"`
+
+	// RemoteLease has machines take refs/git-air/lock on the remote before
+	// committing and pushing, so two of them never commit at the same time
+	RemoteLease *bool `json:"remote_lease,omitempty"`
+
+	// NetworkSafeMode (default true) checks repos on NFS/SMB mounts only
+	// every few minutes, without fsmonitor, and looks for running git
+	// commands again right before staging
+	NetworkSafeMode *bool `json:"network_safe_mode,omitempty"`
+
+	// AllowCloudSync syncs repos inside Dropbox, iCloud Drive, OneDrive,
+	// Google Drive or Syncthing folders, which are skipped by default
+	AllowCloudSync *bool `json:"allow_cloud_sync,omitempty"`
+
+	// SigningUnavailable decides what happens when commit signing is on but
+	// the GPG or SSH agent is locked: "defer" (default) waits for the next
+	// cycle, "unsigned" commits without a signature
+	SigningUnavailable *string `json:"signing_unavailable,omitempty"`
+
+	// Proxy sends HTTP(S) remotes through an http:// or socks5:// proxy;
+	// RemoteProxies sets one per remote name, "" for a direct connection
+	Proxy         *string           `json:"proxy,omitempty"`
+	RemoteProxies map[string]string `json:"remote_proxies,omitempty"`
+}
+
+// Identity is the git author identity used for auto-commits
+type Identity struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// defaultTransientPatterns are editor temp/swap files and OS metadata files
+var defaultTransientPatterns = []string{"*.swp", "*.swo", "*~", ".#*", ".DS_Store", "Thumbs.db"}
+
+// config is the loaded configuration (zero value when no file is used)
+var config Config
+
+// configFileNames are looked up in the working directory, then in
+// ~/.config/git-air/; the first that exists is used
+var configFileNames = []string{"git-air.json", "git-air.yaml", "git-air.yml"}
+
+// loadConfig reads the config file. An explicit path must exist; without one
+// the default locations are tried and a missing file is not an error.
+func loadConfig(path string) (Config, string, error) {
+	var cfg Config
+
+	if path == "" {
+		path = findConfigFile()
+		if path == "" {
+			return cfg, "", nil
+		}
+	}
+
+	path = normalizePath(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, path, fmt.Errorf("reading config: %v", err)
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return cfg, path, fmt.Errorf("parsing %s: %v", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, path, fmt.Errorf("parsing %s: %v", path, err)
+	}
+
+	if cfg.Digest.Time != "" {
+		if _, err := time.Parse("15:04", cfg.Digest.Time); err != nil {
+			return cfg, path, fmt.Errorf("%s: digest time must be HH:MM, got %q", path, cfg.Digest.Time)
+		}
+	}
+	for host, forge := range cfg.Forges {
+		if err := forge.validate(); err != nil {
+			return cfg, path, fmt.Errorf("%s: forge %s: %v", path, host, err)
+		}
+	}
+	if err := cfg.Backup.validate(); err != nil {
+		return cfg, path, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := cfg.Power.validate(); err != nil {
+		return cfg, path, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := validateJitter(cfg.JitterSeconds); err != nil {
+		return cfg, path, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := validateLocale(cfg.Locale); err != nil {
+		return cfg, path, fmt.Errorf("%s: %v", path, err)
+	}
+	if cfg.Workers < 0 {
+		return cfg, path, fmt.Errorf("%s: workers must be at least 1, got %d", path, cfg.Workers)
+	}
+	for _, pattern := range cfg.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return cfg, path, fmt.Errorf("invalid exclude pattern %q in %s", pattern, path)
+		}
+	}
+	if err := cfg.RepoSettings.validate(); err != nil {
+		return cfg, path, fmt.Errorf("%s: %v", path, err)
+	}
+	for _, rule := range cfg.Repos {
+		if _, err := filepath.Match(rule.Match, ""); err != nil || rule.Match == "" {
+			return cfg, path, fmt.Errorf("invalid repo match pattern %q in %s", rule.Match, path)
+		}
+		if err := rule.RepoSettings.validate(); err != nil {
+			return cfg, path, fmt.Errorf("%s: repo %q: %v", path, rule.Match, err)
+		}
+	}
+
+	return cfg, path, nil
+}
+
+// loadConfigOrReport loads the config into the global config for subcommands,
+// printing the error and returning false when it cannot be used
+// LoadConfig loads the config at path ("" searches the default locations,
+// like -c) as the settings Syncer applies, for programs that sync through
+// the daemon without running Main
+func LoadConfig(path string) error {
+	cfg, _, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	configurePlatformGit()
+	config = cfg
+	setLocale(config.Locale)
+	return nil
+}
+
+func loadConfigOrReport(path string) bool {
+	cfg, _, err := loadConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return false
+	}
+	config = cfg
+	setLocale(config.Locale)
+	return true
+}
+
+// findConfigFile returns the first existing default config file, or ""
+func findConfigFile() string {
+	candidates := append([]string{}, configFileNames...)
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range configFileNames {
+			candidates = append(candidates, filepath.Join(home, ".config", "git-air", name))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// settingsFor returns the effective settings for a repository: the global
+// ones, then the matching repos rules, then the repo's .gitair.yml
+func settingsFor(repoPath string) RepoSettings {
+	settings := config.RepoSettings
+	repoPath = filepath.ToSlash(filepath.Clean(repoPath))
+
+	for _, rule := range config.Repos {
+		if ruleMatches(rule.Match, repoPath) {
+			settings.merge(rule.RepoSettings)
+		}
+	}
+	if local := repoConfig(repoPath); local != nil {
+		// A pulled file adds to never_commit and policies, it can't drop
+		// what this machine holds back
+		extra := *local
+		if extra.NeverCommit != nil {
+			extra.NeverCommit = append(append([]string(nil), settings.NeverCommit...), local.NeverCommit...)
+		}
+		if extra.Policies != nil {
+			extra.Policies = append(append([]Policy(nil), settings.Policies...), local.Policies...)
+		}
+		settings.merge(extra)
+	}
+	return settings
+}
+
+// excludeConfigured drops the repos matching an exclude pattern
+func excludeConfigured(repos []string) []string {
+	var kept []string
+	for _, repo := range repos {
+		excluded := false
+		for _, pattern := range config.Exclude {
+			if ruleMatches(pattern, filepath.ToSlash(filepath.Clean(repo))) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, repo)
+		}
+	}
+	return kept
+}
+
+// ruleMatches matches a rule pattern against the repo path or its base name
+func ruleMatches(pattern, repoPath string) bool {
+	if ok, _ := filepath.Match(pattern, repoPath); ok {
+		return true
+	}
+	ok, _ := filepath.Match(pattern, filepath.Base(repoPath))
+	return ok
+}
+
+// merge overrides s with every field that is set in o
+func (s *RepoSettings) merge(o RepoSettings) {
+	if o.Identity.Name != "" {
+		s.Identity.Name = o.Identity.Name
+	}
+	if o.Identity.Email != "" {
+		s.Identity.Email = o.Identity.Email
+	}
+	if o.TransientPatterns != nil {
+		s.TransientPatterns = o.TransientPatterns
+	}
+	if o.SettleSeconds != nil {
+		s.SettleSeconds = o.SettleSeconds
+	}
+	if o.UntrackedLimit != nil {
+		s.UntrackedLimit = o.UntrackedLimit
+	}
+	if o.Template != nil {
+		s.Template = o.Template
+	}
+	if o.TagVersions != nil {
+		s.TagVersions = o.TagVersions
+	}
+	if o.TagPrefix != nil {
+		s.TagPrefix = o.TagPrefix
+	}
+	if o.TimeTrailer != nil {
+		s.TimeTrailer = o.TimeTrailer
+	}
+	if o.HostTrailer != nil {
+		s.HostTrailer = o.HostTrailer
+	}
+	if o.Encrypt != nil {
+		s.Encrypt = o.Encrypt
+	}
+	if o.WaitForCI != nil {
+		s.WaitForCI = o.WaitForCI
+	}
+	if o.JiraIssue != nil {
+		s.JiraIssue = o.JiraIssue
+	}
+	if o.IssueLinks != nil {
+		s.IssueLinks = o.IssueLinks
+	}
+	if o.AIMessages != nil {
+		s.AIMessages = o.AIMessages
+	}
+	if o.AIProvider != nil {
+		s.AIProvider = o.AIProvider
+	}
+	if o.Commitlint != nil {
+		s.Commitlint = o.Commitlint
+	}
+	if o.SubjectLength != nil {
+		s.SubjectLength = o.SubjectLength
+	}
+	if o.BodyWidth != nil {
+		s.BodyWidth = o.BodyWidth
+	}
+	if o.IgnoreWhitespace != nil {
+		s.IgnoreWhitespace = o.IgnoreWhitespace
+	}
+	if o.IgnoreLineEndings != nil {
+		s.IgnoreLineEndings = o.IgnoreLineEndings
+	}
+	if o.IgnoreDiffs != nil {
+		s.IgnoreDiffs = o.IgnoreDiffs
+	}
+	if o.Generated != nil {
+		s.Generated = o.Generated
+	}
+	if o.IgnoreGenerated != nil {
+		s.IgnoreGenerated = o.IgnoreGenerated
+	}
+	if o.NeverCommit != nil {
+		s.NeverCommit = o.NeverCommit
+	}
+	if o.Policies != nil {
+		s.Policies = o.Policies
+	}
+	if o.TrackedOnly != nil {
+		s.TrackedOnly = o.TrackedOnly
+	}
+	if o.Untracked != nil {
+		s.Untracked = o.Untracked
+	}
+	if o.Deletions != nil {
+		s.Deletions = o.Deletions
+	}
+	if o.DeletionDelayMinutes != nil {
+		s.DeletionDelayMinutes = o.DeletionDelayMinutes
+	}
+	if o.Capture != nil {
+		s.Capture = o.Capture
+	}
+	if o.Retention != nil {
+		s.Retention = o.Retention
+	}
+	if o.Watchman != nil {
+		s.Watchman = o.Watchman
+	}
+	if o.Pull != nil {
+		s.Pull = o.Pull
+	}
+	if o.AfterPull != nil {
+		s.AfterPull = o.AfterPull
+	}
+	if o.Immediate != nil {
+		s.Immediate = o.Immediate
+	}
+	if o.Priority != nil {
+		s.Priority = o.Priority
+	}
+	if o.DependsOn != nil {
+		s.DependsOn = o.DependsOn
+	}
+	if o.Conflicts != nil {
+		s.Conflicts = o.Conflicts
+	}
+	if o.BranchRetentionDays != nil {
+		s.BranchRetentionDays = o.BranchRetentionDays
+	}
+	if o.CleanRemoteBranches != nil {
+		s.CleanRemoteBranches = o.CleanRemoteBranches
+	}
+	if o.MachineBranch != nil {
+		s.MachineBranch = o.MachineBranch
+	}
+	if o.SharedBranch != nil {
+		s.SharedBranch = o.SharedBranch
+	}
+	if o.Branch != nil {
+		s.Branch = o.Branch
+	}
+	if o.SquashPushMinutes != nil {
+		s.SquashPushMinutes = o.SquashPushMinutes
+	}
+	if o.Interval != nil {
+		s.Interval = o.Interval
+	}
+	if o.AutoCommit != nil {
+		s.AutoCommit = o.AutoCommit
+	}
+	if o.MessageTemplate != nil {
+		s.MessageTemplate = o.MessageTemplate
+	}
+	if o.Notes != nil {
+		s.Notes = o.Notes
+	}
+	if o.RemoteLease != nil {
+		s.RemoteLease = o.RemoteLease
+	}
+	if o.NetworkSafeMode != nil {
+		s.NetworkSafeMode = o.NetworkSafeMode
+	}
+	if o.AllowCloudSync != nil {
+		s.AllowCloudSync = o.AllowCloudSync
+	}
+	if o.SigningUnavailable != nil {
+		s.SigningUnavailable = o.SigningUnavailable
+	}
+	if o.Proxy != nil {
+		s.Proxy = o.Proxy
+	}
+	if o.RemoteProxies != nil {
+		s.RemoteProxies = o.RemoteProxies
+	}
+}
+
+// validate reports settings that cannot be used
+func (s RepoSettings) validate() error {
+	for _, pattern := range s.TransientPatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid transient pattern %q", pattern)
+		}
+	}
+	if s.SettleSeconds != nil && *s.SettleSeconds < 0 {
+		return fmt.Errorf("settle_seconds must not be negative")
+	}
+	if s.UntrackedLimit != nil && *s.UntrackedLimit < 0 {
+		return fmt.Errorf("untracked_limit must not be negative")
+	}
+	if s.SubjectLength != nil && *s.SubjectLength < 0 {
+		return fmt.Errorf("subject_length must not be negative")
+	}
+	if s.BodyWidth != nil && *s.BodyWidth < 0 {
+		return fmt.Errorf("body_width must not be negative")
+	}
+	if s.AIProvider != nil {
+		switch s.AIProvider.Provider {
+		case "", "openai", "ollama":
+		default:
+			return fmt.Errorf("ai_provider provider must be openai or ollama, got %q", s.AIProvider.Provider)
+		}
+	}
+	if s.SquashPushMinutes != nil && *s.SquashPushMinutes < 0 {
+		return fmt.Errorf("squash_push_minutes must not be negative")
+	}
+	for _, rule := range s.IgnoreDiffs {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	for _, policy := range s.Policies {
+		if err := policy.validate(); err != nil {
+			return err
+		}
+	}
+	for _, pattern := range s.Generated {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid generated pattern %q", pattern)
+		}
+	}
+	if s.Untracked != nil {
+		switch *s.Untracked {
+		case "add", "ignore", "report":
+		default:
+			return fmt.Errorf("untracked must be add, ignore or report, got %q", *s.Untracked)
+		}
+	}
+	if s.Deletions != nil {
+		switch *s.Deletions {
+		case "commit", "delay", "confirm":
+		default:
+			return fmt.Errorf("deletions must be commit, delay or confirm, got %q", *s.Deletions)
+		}
+	}
+	if s.Capture != nil {
+		switch *s.Capture {
+		case "commit", "queue", "stash", "snapshot":
+		default:
+			return fmt.Errorf("capture must be commit, queue, stash or snapshot, got %q", *s.Capture)
+		}
+	}
+	if s.Interval != nil && *s.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if s.Notes != nil {
+		switch *s.Notes {
+		case "off", "local", "push":
+		default:
+			return fmt.Errorf("notes must be off, local or push, got %q", *s.Notes)
+		}
+	}
+	if s.Retention != nil {
+		if err := s.Retention.validate(); err != nil {
+			return err
+		}
+	}
+	if s.HostTrailer != nil {
+		switch *s.HostTrailer {
+		case "host", "full", "off":
+		default:
+			return fmt.Errorf("host_trailer must be host, full or off, got %q", *s.HostTrailer)
+		}
+	}
+	if s.Conflicts != nil {
+		switch *s.Conflicts {
+		case "prefer-remote", "prefer-local", "manual":
+		default:
+			return fmt.Errorf("conflicts must be prefer-remote, prefer-local or manual, got %q", *s.Conflicts)
+		}
+	}
+	if s.Pull != nil && *s.Pull != "auto" && *s.Pull != "review" {
+		return fmt.Errorf("pull must be auto or review, got %q", *s.Pull)
+	}
+	if s.SigningUnavailable != nil && *s.SigningUnavailable != "defer" && *s.SigningUnavailable != "unsigned" {
+		return fmt.Errorf("signing_unavailable must be defer or unsigned, got %q", *s.SigningUnavailable)
+	}
+	if s.Proxy != nil {
+		if err := validateProxy(*s.Proxy); err != nil {
+			return err
+		}
+	}
+	for _, proxy := range s.RemoteProxies {
+		if err := validateProxy(proxy); err != nil {
+			return err
+		}
+	}
+	if s.SharedBranch != nil && *s.SharedBranch == "" {
+		return fmt.Errorf("shared_branch must not be empty")
+	}
+	if s.Branch != nil && *s.Branch == "" {
+		return fmt.Errorf("branch must not be empty")
+	}
+	if s.BranchRetentionDays != nil && *s.BranchRetentionDays < 0 {
+		return fmt.Errorf("branch_retention_days must not be negative")
+	}
+	if s.DeletionDelayMinutes != nil && *s.DeletionDelayMinutes < 0 {
+		return fmt.Errorf("deletion_delay_minutes must not be negative")
+	}
+	for _, pattern := range s.NeverCommit {
+		if _, err := filepath.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return fmt.Errorf("invalid never_commit pattern %q", pattern)
+		}
+	}
+	for _, pattern := range s.Immediate {
+		if _, err := filepath.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return fmt.Errorf("invalid immediate pattern %q", pattern)
+		}
+	}
+	for _, pattern := range s.DependsOn {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid depends_on pattern %q", pattern)
+		}
+	}
+	for _, hook := range s.AfterPull {
+		if err := hook.validate(); err != nil {
+			return err
+		}
+	}
+	if s.Encrypt != nil {
+		if err := s.Encrypt.validate(); err != nil {
+			return err
+		}
+	}
+	if s.IssueLinks != nil {
+		return s.IssueLinks.validate()
+	}
+	return nil
+}
+
+// transientPatterns returns the configured transient file patterns or the defaults
+func (s RepoSettings) transientPatterns() []string {
+	if s.TransientPatterns != nil {
+		return s.TransientPatterns
+	}
+	return defaultTransientPatterns
+}
+
+// unstagedPatterns returns the patterns of files that are never staged:
+// transient files and never_commit globs
+func (s RepoSettings) unstagedPatterns() []string {
+	return append(append([]string{}, s.transientPatterns()...), s.NeverCommit...)
+}
+
+// unstaged reports whether a changed file is never staged: it matches
+// unstagedPatterns or is the plaintext of an encrypted file
+func (s RepoSettings) unstaged(path string) bool {
+	return matchesAny(s.unstagedPatterns(), path) || (s.Encrypt != nil && encryptedPlaintext(s.Encrypt.Patterns, path))
+}
+
+// commitlintRules loads the commit message rules for the current repo, nil
+// if there are none. Without a setting, only AI messages look for a config.
+func (s RepoSettings) commitlintRules(ai bool) (lintRules, error) {
+	if s.Commitlint == nil {
+		if !ai {
+			return nil, nil
+		}
+		return loadCommitlint("")
+	}
+	if *s.Commitlint == "off" {
+		return nil, nil
+	}
+	return loadCommitlint(*s.Commitlint)
+}
+
+// subjectLength returns the maximum length of generated subject lines
+func (s RepoSettings) subjectLength() int {
+	if s.SubjectLength != nil {
+		return *s.SubjectLength
+	}
+	return 72
+}
+
+// bodyWidth returns the column generated message bodies are wrapped at
+func (s RepoSettings) bodyWidth() int {
+	if s.BodyWidth != nil {
+		return *s.BodyWidth
+	}
+	return 72
+}
+
+// settleTime returns how long changed files must be untouched before committing
+func (s RepoSettings) settleTime() time.Duration {
+	if s.SettleSeconds != nil {
+		return time.Duration(*s.SettleSeconds)
+	}
+	return 5 * time.Second
+}
+
+// untrackedLimit returns how many new files may be staged in one commit
+func (s RepoSettings) untrackedLimit() int {
+	if s.UntrackedLimit != nil {
+		return *s.UntrackedLimit
+	}
+	return 1000
+}
+
+// ignoreLineEndings reports whether line-ending-only changes are left alone
+func (s RepoSettings) ignoreLineEndings() bool {
+	return s.IgnoreLineEndings == nil || *s.IgnoreLineEndings
+}
+
+// signingPolicy returns the signing_unavailable setting, "defer" by default
+func (s RepoSettings) signingPolicy() string {
+	if s.SigningUnavailable != nil {
+		return *s.SigningUnavailable
+	}
+	return "defer"
+}
+
+// hostTrailer returns the host_trailer setting, "host" by default
+func (s RepoSettings) hostTrailer() string {
+	if s.HostTrailer != nil {
+		return *s.HostTrailer
+	}
+	return "host"
+}
+
+// conflictPolicy returns the conflicts setting, "manual" by default
+func (s RepoSettings) conflictPolicy() string {
+	if s.Conflicts != nil {
+		return *s.Conflicts
+	}
+	return "manual"
+}
+
+// branchRetention returns how long unmerged git-air/ branches are kept, 0 forever
+func (s RepoSettings) branchRetention() time.Duration {
+	days := 90
+	if s.BranchRetentionDays != nil {
+		days = *s.BranchRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// sharedBranch returns the branch the machine branches are merged into
+func (s RepoSettings) sharedBranch() string {
+	if s.SharedBranch != nil {
+		return *s.SharedBranch
+	}
+	return "main"
+}
+
+// priority returns the repo's place in the cycle order, higher first
+func (s RepoSettings) priority() int {
+	if s.Priority != nil {
+		return *s.Priority
+	}
+	return 0
+}
+
+// untrackedPolicy returns the untracked setting, "ignore" with tracked_only
+// and "add" by default
+func (s RepoSettings) untrackedPolicy() string {
+	if s.Untracked != nil {
+		return *s.Untracked
+	}
+	if s.TrackedOnly != nil && *s.TrackedOnly {
+		return "ignore"
+	}
+	return "add"
+}
+
+// pullMode returns the pull setting, "auto" by default
+func (s RepoSettings) pullMode() string {
+	if s.Pull != nil {
+		return *s.Pull
+	}
+	return "auto"
+}
+
+// deletionPolicy returns the deletions setting, "commit" by default
+func (s RepoSettings) deletionPolicy() string {
+	if s.Deletions != nil {
+		return *s.Deletions
+	}
+	return "commit"
+}
+
+// deletionDelay returns how long deletions are held with the "delay" policy
+func (s RepoSettings) deletionDelay() time.Duration {
+	if s.DeletionDelayMinutes != nil {
+		return time.Duration(*s.DeletionDelayMinutes)
+	}
+	return 10 * time.Minute
+}
+
+// captureMode returns the capture setting, "commit" by default
+func (s RepoSettings) captureMode() string {
+	if s.Capture != nil {
+		return *s.Capture
+	}
+	return "commit"
+}
+
+// aiConfig returns the AI provider for the repo: the global ai settings
+// with ai_provider applied
+func (s RepoSettings) aiConfig() AIConfig {
+	cfg := config.AI
+	o := s.AIProvider
+	if o == nil {
+		return cfg
+	}
+	if o.Provider != "" && o.Provider != cfg.Provider {
+		return *o // Another provider's model and endpoint would not fit
+	}
+	if o.Model != "" {
+		cfg.Model = o.Model
+	}
+	if o.Endpoint != "" {
+		cfg.Endpoint = o.Endpoint
+	}
+	if o.APIKeyEnv != "" {
+		cfg.APIKeyEnv = o.APIKeyEnv
+	}
+	return cfg
+}
+
+// aiMessages reports whether the AI provider writes the repo's commit messages
+func (s RepoSettings) aiMessages() bool {
+	return s.AIMessages != nil && *s.AIMessages && s.aiConfig().Enabled()
+}
+
+// squashPushInterval returns how long pushes wait to be squashed, 0 if they don't
+func (s RepoSettings) squashPushInterval() time.Duration {
+	if s.SquashPushMinutes != nil {
+		return time.Duration(*s.SquashPushMinutes)
+	}
+	return 0
+}
+
+// autoCommit reports whether changes are committed, true by default
+func (s RepoSettings) autoCommit() bool {
+	return s.AutoCommit == nil || *s.AutoCommit
+}
+
+// repoInterval returns how often the repo is checked, 0 for every cycle
+func (s RepoSettings) repoInterval() time.Duration {
+	if s.Interval != nil {
+		return time.Duration(*s.Interval)
+	}
+	return 0
+}
+
+// notesMode returns the notes setting, "off" by default
+func (s RepoSettings) notesMode() string {
+	if s.Notes != nil {
+		return *s.Notes
+	}
+	return "off"
+}
+
+// tagPrefix returns the prefix for version tags
+func (s RepoSettings) tagPrefix() string {
+	if s.TagPrefix != nil {
+		return *s.TagPrefix
+	}
+	return "v"
+}
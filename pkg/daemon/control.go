@@ -0,0 +1,580 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// repoState is what the control socket reports about a repo
+type repoState struct {
+	Path       string     `json:"path"`
+	Paused     bool       `json:"paused,omitempty"`
+	Pending    int        `json:"pending,omitempty"`   // Changes seen and not committed yet
+	Conflicts  []string   `json:"conflicts,omitempty"` // Unmerged paths among them
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+	LastPush   *time.Time `json:"last_push,omitempty"`
+	LastPull   *time.Time `json:"last_pull,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	Alerts     []string   `json:"alerts,omitempty"`
+}
+
+// controlStatus is the reply to GET /status on the control socket
+type controlStatus struct {
+	PID       int         `json:"pid"`
+	Root      string      `json:"root"`
+	Started   time.Time   `json:"started"`
+	Paused    bool        `json:"paused"`
+	Cycle     int         `json:"cycle"`
+	NextCycle *time.Time  `json:"next_cycle,omitempty"` // Unset while a cycle runs
+	Repos     []repoState `json:"repos"`
+}
+
+// control is the state the socket's handlers read, kept by the main loop
+// and the event stream. Everything else the main goroutine owns alone.
+var control struct {
+	sync.Mutex
+	root        string // The directory git-air was started in
+	cycle       int
+	nextCycle   time.Time
+	repos       []string // Absolute paths
+	state       map[string]*repoState
+	pausedRepos map[string]bool
+	commits     []commitRecord // Newest last, at most recentCommitsKept
+}
+
+// recentCommitsKept is how many of its commits git-air remembers for the
+// REST API
+const recentCommitsKept = 100
+
+// commitRecord is a commit git-air made, as GET /api/commits lists it
+type commitRecord struct {
+	Repo    string    `json:"repo"`
+	Hash    string    `json:"hash,omitempty"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+	Files   int       `json:"files,omitempty"`
+	Added   int       `json:"added,omitempty"`
+	Deleted int       `json:"deleted,omitempty"`
+}
+
+// controlListening is set once the control socket or the REST API accepts
+// connections; requests for single repos then reach sleepHandlingTriggers
+var controlListening bool
+
+// controlSocketPath is the socket of the instance with the PID, next to its
+// instance record
+func controlSocketPath(pid int) string {
+	return filepath.Join(instancesDir(), fmt.Sprintf("%d.sock", pid))
+}
+
+// startControl listens on this instance's control socket, for git-air
+// status, trigger, pause and resume from other terminals. Unix domain
+// sockets work on Windows 10 and later too.
+func startControl() {
+	events.Subscribe(trackRepoState)
+	control.root, _ = os.Getwd() // Where the repo paths start, for the handlers
+	socket := controlSocketPath(os.Getpid())
+	if err := os.MkdirAll(filepath.Dir(socket), 0755); err != nil {
+		fmt.Printf(tr("⚠️  Control socket: %v\n"), err)
+		return
+	}
+	os.Remove(socket) // Left behind by a crashed instance with our PID
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		fmt.Printf(tr("⚠️  Control socket: %v\n"), err)
+		return
+	}
+	os.Chmod(socket, 0600)
+	onStop = append(onStop, func() { os.Remove(socket) })
+	controlListening = true
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", controlGetStatus)
+	mux.HandleFunc("/trigger", controlTrigger)
+	mux.HandleFunc("/pause", func(w http.ResponseWriter, r *http.Request) { controlPause(w, r, true) })
+	mux.HandleFunc("/resume", func(w http.ResponseWriter, r *http.Request) { controlPause(w, r, false) })
+	go http.Serve(listener, mux)
+}
+
+// noteCycle records the repos and the cycle for the socket; next is when
+// the next cycle starts, zero while this one runs
+func noteCycle(repos []string, cycle int, next time.Time) {
+	// Alerts are keyed by the repo path as discovered
+	byRepo := map[string][]string{}
+	for key, message := range alerts {
+		repo, _, _ := strings.Cut(key, "\x00")
+		if abs, err := filepath.Abs(repo); err == nil {
+			byRepo[abs] = append(byRepo[abs], message)
+		}
+	}
+
+	control.Lock()
+	defer control.Unlock()
+	control.cycle, control.nextCycle = cycle, next
+	control.repos = control.repos[:0]
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		control.repos = append(control.repos, abs)
+		state := repoStateFor(abs)
+		state.Alerts = byRepo[abs]
+		sort.Strings(state.Alerts)
+	}
+}
+
+// repoStateFor returns the state of a repo, creating it. control must be locked.
+func repoStateFor(repo string) *repoState {
+	if control.state == nil {
+		control.state = map[string]*repoState{}
+	}
+	state, ok := control.state[repo]
+	if !ok {
+		state = &repoState{Path: repo}
+		control.state[repo] = state
+	}
+	return state
+}
+
+// trackRepoState is subscribed to the event stream for the socket's status
+func trackRepoState(event gitair.Event) {
+	hash := ""
+	if event.Type == gitair.Committed {
+		if output, err := gitCommand("-C", event.Repo, "rev-parse", "HEAD").Output(); err == nil {
+			hash = strings.TrimSpace(string(output))
+		}
+	}
+
+	control.Lock()
+	defer control.Unlock()
+	state := repoStateFor(event.Repo)
+	at := event.Time
+	switch event.Type {
+	case gitair.RepoDirty:
+		state.Pending, state.Conflicts = len(event.Changes), nil
+		for _, change := range event.Changes {
+			if unmerged(change.Status) {
+				state.Conflicts = append(state.Conflicts, change.Path)
+			}
+		}
+	case gitair.Committed:
+		state.Pending, state.Conflicts, state.LastCommit, state.LastError = 0, nil, &at, ""
+		control.commits = append(control.commits, commitRecord{Repo: event.Repo, Hash: hash, Time: at,
+			Message: event.Message, Files: event.Files, Added: event.Added, Deleted: event.Deleted})
+		if len(control.commits) > recentCommitsKept {
+			control.commits = control.commits[len(control.commits)-recentCommitsKept:]
+		}
+	case gitair.Pushed:
+		state.LastPush = &at
+	case gitair.PullMerged:
+		state.LastPull = &at
+	case gitair.Error:
+		state.LastError = event.Op + ": " + lastLine(event.Err)
+	}
+}
+
+// trackConflicts records the paths with merge conflicts of the current repo
+// while a git operation keeps git-air out of it (busy), and forgets them
+// once it is done
+func trackConflicts(busy string) {
+	var conflicts []string
+	if busy != "" {
+		output, _ := gitCommand("diff", "--name-only", "-z", "--diff-filter=U").Output()
+		conflicts = strings.FieldsFunc(string(output), func(r rune) bool { return r == 0 })
+	}
+	control.Lock()
+	defer control.Unlock()
+	repoStateFor(getCurrentDir()).Conflicts = conflicts
+}
+
+// unmerged reports whether a git status code is that of a path with merge
+// conflicts
+func unmerged(status string) bool {
+	switch status {
+	case "DD", "AU", "UD", "UA", "DU", "AA", "UU":
+		return true
+	}
+	return false
+}
+
+// repoPaused reports whether a repo was paused through the socket
+func repoPaused(repoPath string) bool {
+	abs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return false
+	}
+	control.Lock()
+	defer control.Unlock()
+	return control.pausedRepos[abs]
+}
+
+// controlRepo returns the repo of this instance containing path, the
+// innermost one for nested repos, or "" if there is none
+func controlRepo(path string) string {
+	control.Lock()
+	defer control.Unlock()
+	match := ""
+	for _, repo := range control.repos {
+		if nestedPath(path, repo) && len(repo) > len(match) {
+			match = repo
+		}
+	}
+	return match
+}
+
+// controlRequest is the body of POST /trigger, /pause and /resume: a path
+// naming a repo, absolute or relative to control.root, or "" for the whole
+// instance
+type controlRequest struct {
+	Path string `json:"path,omitempty"`
+}
+
+// readControlRequest decodes a POST body, answering bad requests itself
+func readControlRequest(w http.ResponseWriter, r *http.Request) (controlRequest, bool) {
+	var req controlRequest
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return req, false
+	}
+	// Browsers only send JSON after a CORS preflight, which nothing here
+	// answers, so pages on other sites can't post forms or text here
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "expected Content-Type: application/json", http.StatusUnsupportedMediaType)
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `expected {"path": "<path>"}`, http.StatusBadRequest)
+		return req, false
+	}
+	if req.Path != "" {
+		req.Path = resolveControlPath(req.Path)
+	}
+	return req, true
+}
+
+// resolveControlPath makes a path relative to control.root absolute, for
+// REST API clients that don't know where git-air runs
+func resolveControlPath(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(control.root, path)
+}
+
+// replyJSON sends a JSON reply
+func replyJSON(w http.ResponseWriter, reply interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// controlGetStatus answers GET /status
+func controlGetStatus(w http.ResponseWriter, r *http.Request) {
+	replyJSON(w, currentStatus())
+}
+
+// currentStatus snapshots the state for GET /status
+func currentStatus() controlStatus {
+	status := controlStatus{PID: os.Getpid(), Started: instanceStarted, Paused: paused.Load()}
+
+	control.Lock()
+	status.Root, status.Cycle = control.root, control.cycle
+	if !control.nextCycle.IsZero() {
+		next := control.nextCycle
+		status.NextCycle = &next
+	}
+	for _, repo := range control.repos {
+		state := *repoStateFor(repo)
+		state.Paused = control.pausedRepos[repo]
+		status.Repos = append(status.Repos, state)
+	}
+	control.Unlock()
+	return status
+}
+
+// controlTrigger answers POST /trigger: syncs the repo of the path right
+// away, or starts a cycle for the whole instance
+func controlTrigger(w http.ResponseWriter, r *http.Request) {
+	req, ok := readControlRequest(w, r)
+	if !ok {
+		return
+	}
+	if req.Path == "" {
+		wake()
+		replyJSON(w, map[string]string{"triggered": "all"})
+		return
+	}
+	dir := req.Path
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		dir = filepath.Dir(dir) // A file, or one that was just deleted
+	}
+	root := repoRoot(dir)
+	if root == "" || controlRepo(req.Path) == "" {
+		http.Error(w, "not inside a repository this git-air syncs", http.StatusNotFound)
+		return
+	}
+	select {
+	case triggered <- syncRequest{root: root, trigger: "control"}:
+	default: // A cycle is running long; it checks every repo anyway
+	}
+	replyJSON(w, map[string]string{"triggered": root})
+}
+
+// controlPause answers POST /pause and /resume: pauses or resumes the repo
+// containing the path, or the whole instance for its root or ""
+func controlPause(w http.ResponseWriter, r *http.Request, pause bool) {
+	req, ok := readControlRequest(w, r)
+	if !ok {
+		return
+	}
+	repo := ""
+	if req.Path != "" {
+		repo = controlRepo(req.Path)
+	}
+
+	if repo == "" || repo == control.root {
+		paused.Store(pause)
+		if !pause {
+			control.Lock()
+			control.pausedRepos = nil // Resuming everything includes the single repos
+			control.Unlock()
+		}
+		if pause {
+			fmt.Println(tr("\n⏸️  Paused by git-air pause: no commits, pushes or pulls until git-air resume"))
+		} else {
+			fmt.Println(tr("\n▶️  Resumed by git-air resume"))
+		}
+		wake()
+		replyJSON(w, map[string]string{"paused": fmt.Sprint(pause), "repo": "all"})
+		return
+	}
+
+	control.Lock()
+	if control.pausedRepos == nil {
+		control.pausedRepos = map[string]bool{}
+	}
+	if pause {
+		control.pausedRepos[repo] = true
+	} else {
+		delete(control.pausedRepos, repo)
+	}
+	control.Unlock()
+	if pause {
+		fmt.Printf(tr("\n⏸️  %s: paused by git-air pause until git-air resume\n"), repo)
+	} else {
+		fmt.Printf(tr("\n▶️  %s: resumed by git-air resume\n"), repo)
+	}
+	replyJSON(w, map[string]string{"paused": fmt.Sprint(pause), "repo": repo})
+}
+
+// callControl sends a request to the control socket of the instance with
+// the PID and decodes the JSON reply
+func callControl(pid int, method, path string, body, reply interface{}) error {
+	socket := controlSocketPath(pid)
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}},
+	}
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, "http://git-air"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var message bytes.Buffer
+		message.ReadFrom(resp.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(message.String()))
+	}
+	return json.NewDecoder(resp.Body).Decode(reply)
+}
+
+// coveringInstances returns the running instances syncing dir, reporting
+// when there are none
+func coveringInstances(dir string) []instanceRecord {
+	var covering []instanceRecord
+	for _, instance := range liveInstances() {
+		if instanceCovers(instance, dir) {
+			covering = append(covering, instance)
+		}
+	}
+	if len(covering) == 0 {
+		fmt.Fprintf(os.Stderr, tr("❌ No running git-air syncs %s\n"), dir)
+	}
+	return covering
+}
+
+// triggerInstances asks the instances syncing the files to sync them now,
+// or, without files, the ones syncing the current directory for a cycle
+func triggerInstances(files []string) int {
+	status := 0
+	if len(files) == 0 {
+		dir, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			return 1
+		}
+		instances := coveringInstances(dir)
+		if len(instances) == 0 {
+			return 1
+		}
+		for _, instance := range instances {
+			var reply map[string]string
+			if err := callControl(instance.PID, http.MethodPost, "/trigger", controlRequest{}, &reply); err != nil {
+				fmt.Fprintf(os.Stderr, tr("❌ pid %d: %v\n"), instance.PID, err)
+				status = 1
+				continue
+			}
+			fmt.Printf(tr("🔄 Cycle started in git-air (pid %d, syncing %s)\n"), instance.PID, instance.Root)
+		}
+		return status
+	}
+
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			status = 1
+			continue
+		}
+		instances := coveringInstances(abs)
+		if len(instances) == 0 {
+			status = 1
+			continue
+		}
+		var reply map[string]string
+		if err := callControl(instances[0].PID, http.MethodPost, "/trigger", controlRequest{Path: abs}, &reply); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			status = 1
+			continue
+		}
+		fmt.Printf(tr("🔄 %s: sync requested (pid %d)\n"), displayName(reply["triggered"]), instances[0].PID)
+	}
+	return status
+}
+
+// runStatus implements `git-air status [dir]`: shows what the running
+// git-air syncing the directory is doing, through its control socket
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the status as JSON")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air status [--json] [dir]"))
+		fmt.Println(tr("\nShows the running git-air that syncs the directory (default: the current"))
+		fmt.Println(tr("one): its cycle, and per repository whether it is paused, has pending"))
+		fmt.Println(tr("changes or problems, and when it was last committed, pushed and pulled."))
+	}
+	dirs := parseInterspersed(fs, args)
+	if len(dirs) > 1 {
+		fs.Usage()
+		return 2
+	}
+	dir := "."
+	if len(dirs) == 1 {
+		dir = dirs[0]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return 1
+	}
+	instances := coveringInstances(dir)
+	if len(instances) == 0 {
+		return 1
+	}
+
+	var statuses []controlStatus
+	code := 0
+	for _, instance := range instances {
+		var status controlStatus
+		if err := callControl(instance.PID, http.MethodGet, "/status", nil, &status); err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ pid %d: no control socket (%v)\n"), instance.PID, err)
+			code = 1
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	if *asJSON {
+		data, _ := json.MarshalIndent(statuses, "", "  ")
+		fmt.Println(string(data))
+		return code
+	}
+	for _, status := range statuses {
+		printStatus(status)
+	}
+	return code
+}
+
+// printStatus prints an instance's status for git-air status
+func printStatus(status controlStatus) {
+	fmt.Printf(tr("🚀 git-air (pid %d) in %s, running for %s\n"), status.PID, status.Root, formatInterval(time.Since(status.Started).Round(time.Second)))
+	switch {
+	case status.Paused:
+		fmt.Println(tr("   ⏸️  Paused until git-air resume"))
+	case status.NextCycle == nil:
+		fmt.Printf(tr("   🔄 Check cycle #%d running\n"), status.Cycle)
+	default:
+		fmt.Printf(tr("   💤 Cycle #%d done, next in %s\n"), status.Cycle, formatInterval(time.Until(*status.NextCycle).Round(time.Second)))
+	}
+	for _, repo := range status.Repos {
+		name := repo.Path
+		if rel, err := filepath.Rel(status.Root, repo.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		state := tr("✓ in sync")
+		switch {
+		case repo.Paused:
+			state = tr("⏸️  paused")
+		case len(repo.Conflicts) > 0:
+			state = fmt.Sprintf(tr("⚔️  %d file(s) with merge conflicts"), len(repo.Conflicts))
+		case repo.LastError != "":
+			state = "❌ " + repo.LastError
+		case len(repo.Alerts) > 0:
+			state = "⚠️  " + repo.Alerts[0]
+		case repo.Pending > 0:
+			state = fmt.Sprintf(tr("📝 %d pending change(s)"), repo.Pending)
+		}
+		fmt.Printf("  📁 %s: %s\n", name, state)
+		var times []string
+		for _, last := range []struct {
+			label string
+			at    *time.Time
+		}{{tr("commit"), repo.LastCommit}, {tr("push"), repo.LastPush}, {tr("pull"), repo.LastPull}} {
+			if last.at != nil {
+				times = append(times, fmt.Sprintf(tr("%s %s ago"), last.label, formatInterval(time.Since(*last.at).Round(time.Second))))
+			}
+		}
+		if len(times) > 0 {
+			fmt.Printf("     %s\n", strings.Join(times, ", "))
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package daemon
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// credentialCheckInterval is how often the daemon repeats the preflight
+	credentialCheckInterval = time.Hour
+
+	// probeTimeout bounds one remote probe, for hosts that accept the
+	// connection and then hang
+	probeTimeout = 30 * time.Second
+)
+
+// lastCredentialCheck is when the daemon last probed the remotes, zero before the first time
+var lastCredentialCheck time.Time
+
+// blockedRemote is a remote the preflight could not authenticate to
+type blockedRemote struct {
+	repo, remote, url, problem string
+}
+
+// sshRemote reports whether a remote URL is reached through SSH: ssh:// URLs
+// and scp-like user@host:path, but not local paths
+func sshRemote(url string) bool {
+	if strings.HasPrefix(url, "ssh://") || strings.HasPrefix(url, "git+ssh://") {
+		return true
+	}
+	if strings.Contains(url, "://") {
+		return false
+	}
+	colon := strings.Index(url, ":")
+	return colon > 1 && !strings.Contains(url[:colon], "/")
+}
+
+// probeEnv makes git fail instead of asking for anything: no terminal
+// prompts, no askpass programs, and SSH in batch mode
+func probeEnv() []string {
+	ssh := os.Getenv("GIT_SSH_COMMAND")
+	if ssh == "" {
+		ssh = gitConfigValue("core.sshCommand")
+	}
+	if ssh == "" {
+		ssh = "ssh"
+	}
+	return append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ASKPASS=true",
+		"SSH_ASKPASS_REQUIRE=never",
+		"GIT_SSH_COMMAND="+ssh+" -o BatchMode=yes -o ConnectTimeout=15",
+	)
+}
+
+// sshAgentState describes why the SSH agent can't help, "" if it has keys
+func sshAgentState() string {
+	err := exec.Command("ssh-add", "-l").Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if exitErr.ExitCode() == 1 {
+			return tr("the SSH agent has no keys loaded")
+		}
+		return tr("no SSH agent is running")
+	}
+	if err != nil {
+		return tr("ssh-add is not available")
+	}
+	return ""
+}
+
+// probeRemote checks that a remote of the current repo can be read without
+// any prompt, and returns what went wrong or ""
+func probeRemote(remote, url string) string {
+	ctx, cancel := context.WithTimeout(gitContext, probeTimeout)
+	defer cancel()
+
+	netSlots <- struct{}{}
+	cmd := exec.CommandContext(ctx, "git", append(proxyOptions(), "ls-remote", "--heads", remote)...)
+	cmd.Dir = repoDir
+	cmd.Env = probeEnv()
+	output, err := cmd.CombinedOutput()
+	<-netSlots
+	if err == nil {
+		return ""
+	}
+	if ctx.Err() != nil {
+		return fmt.Sprintf(tr("no answer within %s"), probeTimeout)
+	}
+
+	if hostKey := hostKeyFailure(string(output)); hostKey != "" {
+		return hostKey
+	}
+
+	// The first line names the cause, git's closing lines only say that it failed
+	problem := lastLine(strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0])
+	if sshRemote(url) && strings.Contains(string(output), "Permission denied") {
+		if agent := sshAgentState(); agent != "" {
+			problem += " - " + agent
+		}
+	} else if strings.Contains(problem, "Username") || strings.Contains(problem, "Authentication failed") {
+		problem += tr(" - no cached credentials, run a git fetch by hand once or set up a credential helper")
+	}
+	return problem
+}
+
+// checkRepoCredentials probes every remote of a repo and raises or clears a
+// "credentials:<remote>" alert for each
+func checkRepoCredentials(repoPath string) []blockedRemote {
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		return nil
+	}
+	defer leave()
+
+	var blocked []blockedRemote
+	for _, remote := range getRemotes() {
+		url, _ := gitOutput("remote", "get-url", remote)
+		url = strings.TrimSpace(url)
+		if problem := probeRemote(remote, url); problem != "" {
+			blocked = append(blocked, blockedRemote{repo: repoPath, remote: remote, url: url, problem: problem})
+			raiseAlert(repoPath, "credentials:"+remote, fmt.Sprintf(tr("%s: %s (%s) would block: %s"), displayName(repoPath), remote, url, problem))
+			continue
+		}
+		clearAlert(repoPath, "credentials:"+remote)
+	}
+	return blocked
+}
+
+// checkCredentials runs the preflight for all repos at startup and then every
+// credentialCheckInterval, so a missing key shows up before a push fails
+func checkCredentials(repos []string) {
+	if networkHeld != "" || time.Since(lastCredentialCheck) < credentialCheckInterval {
+		return
+	}
+	lastCredentialCheck = time.Now()
+
+	fmt.Println(tr("🔑 Checking remote credentials..."))
+	var blocked []blockedRemote
+	for _, repo := range repos {
+		blocked = append(blocked, checkRepoCredentials(repo)...)
+	}
+	if len(blocked) == 0 {
+		fmt.Println(tr("  ✓ All remotes are reachable without prompts"))
+		return
+	}
+	for _, b := range blocked {
+		fmt.Printf(tr("  ❌ %s: %s (%s) would block: %s\n"), displayName(b.repo), b.remote, b.url, b.problem)
+	}
+}
+
+// runDoctor implements `git-air doctor [repo...]`: the credential preflight on demand
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air doctor [repo...]"))
+		fmt.Println(tr("\nChecks that every remote can be fetched without a password prompt: SSH"))
+		fmt.Println(tr("keys in the agent, HTTPS credentials in a credential helper. Without repos,"))
+		fmt.Println(tr("all repos below the current directory. Exits with 1 if a remote would block."))
+	}
+	repos := parseInterspersed(fs, args)
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	if len(repos) == 0 {
+		found, err := findGitRepos(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error finding repositories: %v\n"), err)
+			return 1
+		}
+		repos = found
+	}
+
+	status := 0
+	for _, repo := range repos {
+		blocked := checkRepoCredentials(repo)
+		if len(blocked) == 0 {
+			fmt.Printf("✓ %s\n", repo)
+			continue
+		}
+		status = 1
+		for _, b := range blocked {
+			fmt.Printf(tr("❌ %s: %s (%s) would block: %s\n"), repo, b.remote, b.url, b.problem)
+		}
+	}
+	return status
+}
@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cryptTool describes a transparent encryption filter and how its ciphertext starts
+type cryptTool struct {
+	name  string
+	magic string
+}
+
+// cryptFilters maps .gitattributes filter names to the tools that use them
+var cryptFilters = map[string]cryptTool{
+	"git-crypt": {name: "git-crypt", magic: "\x00GITCRYPT\x00"},
+	"crypt":     {name: "transcrypt", magic: "U2FsdGVkX1"}, // base64 of OpenSSL's "Salted__"
+}
+
+// filterAttributes returns the paths that have a filter attribute (e.g.
+// git-crypt or lfs), mapped to the filter name
+func filterAttributes(paths []string) map[string]string {
+	result := map[string]string{}
+	if len(paths) == 0 {
+		return result
+	}
+
+	cmd := gitCommand("check-attr", "-z", "--stdin", "filter")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return result
+	}
+
+	// Output is path NUL attribute NUL value NUL, repeated
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if value := fields[i+2]; value != "unspecified" && value != "unset" && value != "set" {
+			result[fields[i]] = value
+		}
+	}
+	return result
+}
+
+// encryptedPaths returns the paths whose filter attribute belongs to an
+// encryption tool, mapped to the filter name
+func encryptedPaths(paths []string) map[string]string {
+	result := map[string]string{}
+	for path, filter := range filterAttributes(paths) {
+		if _, ok := cryptFilters[filter]; ok {
+			result[path] = filter
+		}
+	}
+	return result
+}
+
+// cryptUnlocked reports whether an encryption filter is configured in this
+// clone. Locked or freshly cloned repos have no clean filter, so git would
+// store the files as plaintext.
+func cryptUnlocked(filter string) bool {
+	if gitConfigValue("filter."+filter+".clean") == "" {
+		return false
+	}
+	if filter == "git-crypt" {
+		gitDir, err := gitCommand("rev-parse", "--absolute-git-dir").Output()
+		if err != nil {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(strings.TrimSpace(string(gitDir)), "git-crypt", "keys")); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// lockedCryptTool returns the name of the first encryption tool among the
+// filters that is not unlocked, or "" if all are usable
+func lockedCryptTool(encrypted map[string]string) string {
+	for _, filter := range encrypted {
+		if !cryptUnlocked(filter) {
+			return cryptFilters[filter].name
+		}
+	}
+	return ""
+}
+
+// plaintextBlobs returns the paths that should be encrypted but are stored
+// as plaintext at rev ("" for the index, or a commit such as HEAD)
+func plaintextBlobs(rev string, encrypted map[string]string) []string {
+	var plain []string
+	for path, filter := range encrypted {
+		cmd := gitCommand("cat-file", "blob", rev+":"+path)
+		blob, err := cmd.Output()
+		if err != nil {
+			continue // Deleted or not part of rev
+		}
+		if !bytes.HasPrefix(blob, []byte(cryptFilters[filter].magic)) {
+			plain = append(plain, path)
+		}
+	}
+	return plain
+}
+
+// trackedFiles lists every file at rev
+func trackedFiles(rev string) []string {
+	output, err := gitCommand("ls-tree", "-r", "-z", "--name-only", rev).Output()
+	if err != nil {
+		return nil
+	}
+	return strings.FieldsFunc(string(output), func(r rune) bool { return r == 0 })
+}
+
+// checkPlaintextHead refuses to push a HEAD that contains plaintext of files
+// that are supposed to be encrypted, e.g. from a manual commit in a locked clone
+func checkPlaintextHead(repoPath string) bool {
+	plain := plaintextBlobs("HEAD", encryptedPaths(trackedFiles("HEAD")))
+	if len(plain) == 0 {
+		clearAlert(repoPath, "plaintext")
+		return true
+	}
+	fmt.Printf(tr("  🔓 Not pushing %s: %s committed unencrypted\n"), filepath.Base(repoPath), describeCount(plain))
+	raiseAlert(repoPath, "plaintext", fmt.Sprintf(tr("%s: push blocked, %s committed unencrypted - unlock the repo and amend the commit"), displayName(repoPath), describeCount(plain)))
+	return false
+}
+
+// describeCount renders "a" or "a (and N more)"
+func describeCount(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return fmt.Sprintf(tr("%s (and %d more)"), items[0], len(items)-1)
+}
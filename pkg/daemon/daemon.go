@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// daemonEnv marks the detached process --daemon starts, which runs in the
+// foreground of its own session instead of detaching again
+const daemonEnv = "GIT_AIR_DAEMON"
+
+// daemonStopTimeout is how long stop and restart wait for the daemon to
+// finish the repo it is syncing and exit
+const daemonStopTimeout = 30 * time.Second
+
+// daemonRecord is kept next to the PID file, so restart can start the
+// daemon again the way it was started
+type daemonRecord struct {
+	Dir  string   `json:"dir"`
+	Args []string `json:"args"`
+	Log  string   `json:"log"`
+}
+
+// pidFilePath returns the PID file: --pid-file, else pid_file from the
+// config, else git-air.pid in the state dir
+func pidFilePath(flagValue string) string {
+	return daemonPath(flagValue, config.PIDFile, "git-air.pid")
+}
+
+// logFilePath returns the daemon's log: --log-file, else log_file from the
+// config, else git-air.log in the state dir
+func logFilePath(flagValue string) string {
+	return daemonPath(flagValue, config.LogFile, "git-air.log")
+}
+
+// daemonPath resolves a daemon file setting to an absolute path
+func daemonPath(flagValue, configValue, name string) string {
+	path := filepath.Join(stateDir(), name)
+	if flagValue != "" {
+		path = flagValue
+	} else if configValue != "" {
+		path = configValue
+	}
+	if abs, err := filepath.Abs(expandHome(path)); err == nil {
+		path = abs
+	}
+	return path
+}
+
+// daemonArgs returns the command line without --daemon, for the detached process
+func daemonArgs(args []string) []string {
+	var kept []string
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "daemon" {
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept
+}
+
+// startDaemon starts git-air again with record's arguments, detached from
+// the terminal and writing to the log, and records its PID
+func startDaemon(record daemonRecord, pidFile string) int {
+	if pid, ok := runningDaemon(pidFile); ok {
+		fmt.Fprintf(os.Stderr, tr("❌ git-air is already running (pid %d, %s)\n"), pid, pidFile)
+		return 1
+	}
+	for _, path := range []string{pidFile, record.Log} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			return 1
+		}
+	}
+	logFile, err := os.OpenFile(record.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return 1
+	}
+	defer logFile.Close()
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return 1
+	}
+
+	fmt.Fprintf(logFile, "\n=== git-air started %s in %s ===\n", time.Now().Format("2006-01-02 15:04:05"), record.Dir)
+	cmd := exec.Command(executable, record.Args...)
+	cmd.Dir = record.Dir
+	cmd.Env = append(os.Environ(), daemonEnv+"=1")
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	cmd.SysProcAttr = detachedProcess()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Could not start git-air: %v\n"), err)
+		return 1
+	}
+	pid := cmd.Process.Pid
+	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d\n", pid)), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		cmd.Process.Kill()
+		return 1
+	}
+
+	// A bad config or flag ends the process at once; say so here, not only in the log
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case <-exited:
+		os.Remove(pidFile)
+		fmt.Fprintf(os.Stderr, tr("❌ git-air exited right after starting, see %s\n"), record.Log)
+		return 1
+	case <-time.After(time.Second):
+	}
+	if data, err := json.MarshalIndent(record, "", "  "); err == nil {
+		os.WriteFile(pidFile+".json", data, 0644)
+	}
+
+	fmt.Printf(tr("🚀 git-air is running in the background (pid %d)\n"), pid)
+	fmt.Printf(tr("   Log: %s\n"), record.Log)
+	fmt.Println(tr("   Stop it with git-air stop"))
+	return 0
+}
+
+// removePIDFileOnExit deletes the PID file when the detached daemon is told
+// to stop, so no stale PID is left behind
+func removePIDFileOnExit(pidFile string) {
+	onStop = append(onStop, func() {
+		if pid, err := readPIDFile(pidFile); err == nil && pid == os.Getpid() {
+			os.Remove(pidFile)
+		}
+	})
+}
+
+// readPIDFile returns the PID a PID file holds
+func readPIDFile(pidFile string) (int, error) {
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// runningDaemon returns the PID in the PID file if that process is alive
+func runningDaemon(pidFile string) (int, bool) {
+	pid, err := readPIDFile(pidFile)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, processAlive(pid)
+}
+
+// stopDaemon ends the daemon in the PID file and waits for it to exit
+func stopDaemon(pidFile string) error {
+	pid, ok := runningDaemon(pidFile)
+	if !ok {
+		return fmt.Errorf(tr("git-air is not running (no live process in %s)"), pidFile)
+	}
+	if err := terminateProcess(pid); err != nil {
+		return fmt.Errorf(tr("could not stop pid %d: %v"), pid, err)
+	}
+	for deadline := time.Now().Add(daemonStopTimeout); processAlive(pid); {
+		if time.Now().After(deadline) {
+			return fmt.Errorf(tr("pid %d did not exit within %s"), pid, formatInterval(daemonStopTimeout))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	os.Remove(pidFile)
+	fmt.Printf(tr("✓ Stopped git-air (pid %d)\n"), pid)
+	return nil
+}
+
+// daemonFlags adds the flags stop and restart share
+func daemonFlags(fs *flag.FlagSet) (cfgPath, pidFile *string) {
+	cfgPath = fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	pidFile = fs.String("pid-file", "", "PID file of the daemon")
+	return cfgPath, pidFile
+}
+
+// runStop implements `git-air stop`: stops the daemon started with --daemon
+func runStop(args []string) int {
+	fs := flag.NewFlagSet("stop", flag.ExitOnError)
+	cfgPath, pidFile := daemonFlags(fs)
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air stop [--pid-file <file>]"))
+		fmt.Println(tr("\nStops the git-air started with --daemon, using its PID file (default:"))
+		fmt.Println(tr("pid_file from the config, else git-air.pid in the state directory)."))
+	}
+	if len(parseInterspersed(fs, args)) > 0 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	if err := stopDaemon(pidFilePath(*pidFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runRestart implements `git-air restart`: stops the daemon if it runs and
+// starts it again in the same directory with the same options
+func runRestart(args []string) int {
+	fs := flag.NewFlagSet("restart", flag.ExitOnError)
+	cfgPath, pidFile := daemonFlags(fs)
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air restart [--pid-file <file>]"))
+		fmt.Println(tr("\nStops the git-air started with --daemon and starts it again in the same"))
+		fmt.Println(tr("directory with the same options, e.g. after changing the config."))
+	}
+	if len(parseInterspersed(fs, args)) > 0 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+
+	path := pidFilePath(*pidFile)
+	var record daemonRecord
+	data, err := os.ReadFile(path + ".json")
+	if err == nil {
+		err = json.Unmarshal(data, &record)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ No record of a git-air started with --daemon next to %s\n"), path)
+		return 1
+	}
+	if _, running := runningDaemon(path); running {
+		if err := stopDaemon(path); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+	}
+	return startDaemon(record, path)
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// detachedProcess starts the daemon in a session of its own, so closing
+// the terminal doesn't stop it
+func detachedProcess() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether a process with the PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// terminateProcess asks a process to exit
+func terminateProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	detachedProcessFlag            = 0x00000008
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+// detachedProcess starts the daemon without a console, so closing the
+// terminal doesn't stop it
+func detachedProcess() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcessFlag | syscall.CREATE_NEW_PROCESS_GROUP,
+		HideWindow:    true,
+	}
+}
+
+// processAlive reports whether a process with the PID is still running
+func processAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(handle)
+	var code uint32
+	return syscall.GetExitCodeProcess(handle, &code) == nil && code == stillActive
+}
+
+// terminateProcess ends a process; Windows has no signal to ask politely
+func terminateProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	_ "embed"
+	"net"
+	"net/http"
+)
+
+// dashboardPage is the web dashboard served at / next to the REST API. It
+// is static: the data comes from /api/status, the buttons post to
+// /api/trigger, /api/pause and /api/resume.
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// dashboardPolicy lets the page run its own script and talk to its own API,
+// nothing else, and keeps other sites from framing it to click its buttons
+const dashboardPolicy = "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; " +
+	"connect-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+// serveDashboard answers GET / with the dashboard page. The page needs no
+// token; it asks for one when the API does. Its buttons post JSON to the
+// API, which only takes them from the page's own origin.
+func serveDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Security-Policy", dashboardPolicy)
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Write(dashboardPage)
+}
+
+// dashboardURL is where the dashboard of the API on addr opens in a
+// browser on this machine
+func dashboardURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr + "/"
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}
@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// deletionsSeen remembers when a deleted file was first noticed, per repo
+var deletionsSeen = map[string]map[string]time.Time{}
+
+// declinedDeletions remembers deletions the user answered "no" to, so they are
+// not asked about again every cycle
+var declinedDeletions = map[string]string{}
+
+// heldDeletions returns the deleted files that must not be committed yet under
+// the repo's deletions policy: "delay" holds them for the grace period,
+// "confirm" until the user agrees. Files that reappear are forgotten.
+func heldDeletions(repoPath string, changes []fileChange, settings RepoSettings) []string {
+	var deleted []string
+	for _, change := range changes {
+		if change.Status[1] == 'D' {
+			deleted = append(deleted, change.Path)
+		}
+	}
+
+	seen := deletionsSeen[repoPath]
+	if seen == nil {
+		seen = map[string]time.Time{}
+		deletionsSeen[repoPath] = seen
+	}
+	current := map[string]bool{}
+	for _, path := range deleted {
+		current[path] = true
+		if _, ok := seen[path]; !ok {
+			seen[path] = time.Now()
+		}
+	}
+	for path := range seen {
+		if !current[path] {
+			delete(seen, path)
+		}
+	}
+	if len(deleted) == 0 {
+		clearAlert(repoPath, "deletions")
+		return nil
+	}
+
+	switch settings.deletionPolicy() {
+	case "delay":
+		var held []string
+		for _, path := range deleted {
+			if time.Since(seen[path]) < settings.deletionDelay() {
+				held = append(held, path)
+			}
+		}
+		if len(held) > 0 {
+			fmt.Printf(tr("🗑️  %s: holding %d deletion(s) for up to %s: %s\n"), filepath.Base(repoPath), len(held), formatSpent(settings.deletionDelay()), describeCount(held))
+		}
+		return held
+	case "confirm":
+		if confirmMode {
+			return nil // The commit prompt shows them
+		}
+		if dryRun {
+			fmt.Printf(tr("🗑️  %s: %d deletion(s) would wait for confirmation: %s\n"), filepath.Base(repoPath), len(deleted), describeCount(deleted))
+			return deleted
+		}
+		if confirmDeletions(repoPath, deleted) {
+			clearAlert(repoPath, "deletions")
+			return nil
+		}
+		raiseAlert(repoPath, "deletions", fmt.Sprintf(tr("%s: %d deletion(s) waiting for confirmation: %s - commit them yourself or restore them with git restore"), displayName(repoPath), len(deleted), describeCount(deleted)))
+		return deleted
+	}
+	return nil
+}
+
+// confirmDeletions asks whether to commit the deletions when running in a
+// terminal. A "no" stands until the set of deleted files changes.
+func confirmDeletions(repoPath string, deleted []string) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	key := strings.Join(deleted, "\x00")
+	if declinedDeletions[repoPath] == key {
+		return false
+	}
+
+	for _, path := range deleted {
+		fmt.Printf("  🗑️  %s\n", path)
+	}
+	fmt.Printf(tr("  🙋 Commit %d deletion(s) in %s? [y]es / [n]o: "), len(deleted), displayName(repoPath))
+	answer, err := stdin.ReadString('\n')
+	if err != nil {
+		fmt.Println()
+		return false
+	}
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "y" || answer == "yes" {
+		delete(declinedDeletions, repoPath)
+		return true
+	}
+	declinedDeletions[repoPath] = key
+	return false
+}
+
+// withoutPaths drops the changes to the given paths
+func withoutPaths(changes []fileChange, paths []string) []fileChange {
+	drop := map[string]bool{}
+	for _, path := range paths {
+		drop[path] = true
+	}
+	var result []fileChange
+	for _, change := range changes {
+		if !drop[change.Path] {
+			result = append(result, change)
+		}
+	}
+	return result
+}
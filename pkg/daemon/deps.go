@@ -0,0 +1,169 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// dependencies maps each repo to the managed repos it depends on, as resolved
+// by the last syncOrder
+var dependencies = map[string][]string{}
+
+// dependencyDirs holds the absolute path of each dependency, since the
+// checks run from inside the dependent repo
+var dependencyDirs = map[string]string{}
+
+// cycleNotice is the last dependency cycle reported, so it is printed once
+var cycleNotice string
+
+// resolveDependencies finds the managed repos each repo depends on: those
+// matching its depends_on patterns (like repos rules, against the path or
+// directory name) and its submodules. Other repos nested inside it are
+// independent; a push of theirs that fails must not hold up the parent's.
+func resolveDependencies(repos []string) map[string][]string {
+	deps := map[string][]string{}
+	for _, repo := range repos {
+		patterns := settingsFor(repo).DependsOn
+		submodules := submodulePaths(repo)
+		for _, other := range repos {
+			if other == repo {
+				continue
+			}
+			rel, err := filepath.Rel(filepath.Clean(repo), filepath.Clean(other))
+			nested := err == nil && submodules[filepath.ToSlash(rel)]
+			matched := false
+			for _, pattern := range patterns {
+				if ruleMatches(pattern, filepath.ToSlash(filepath.Clean(other))) {
+					matched = true
+					break
+				}
+			}
+			if nested || matched {
+				deps[repo] = append(deps[repo], other)
+				if abs, err := filepath.Abs(other); err == nil {
+					dependencyDirs[other] = abs
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// submodulePaths returns the submodule paths a repo's .gitmodules declares,
+// relative to the repo with forward slashes
+func submodulePaths(repo string) map[string]bool {
+	paths := map[string]bool{}
+	output, err := gitCommand("config", "-f", filepath.Join(repo, ".gitmodules"), "--get-regexp", `^submodule\..*\.path$`).Output()
+	if err != nil {
+		return paths // No .gitmodules, or no submodules in it
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if _, path, ok := strings.Cut(line, " "); ok {
+			paths[filepath.ToSlash(filepath.Clean(path))] = true
+		}
+	}
+	return paths
+}
+
+// holdForDependency reports whether a repo's push waits for a dependency
+// with unpushed commits, raising an alert while it does: a dependency that
+// can't push would otherwise hold the repo back without a word
+func holdForDependency(repoPath string) string {
+	dep := unpushedDependency(repoPath)
+	if dep == "" {
+		clearAlert(repoPath, "dependency")
+		return ""
+	}
+	raiseAlert(repoPath, "dependency", fmt.Sprintf(tr("%s: push held back until %s has pushed its commits"), displayName(repoPath), displayName(dep)))
+	return dep
+}
+
+// syncOrder sorts repos so every dependency comes before the repos that
+// depend on it, or after them with dependentsFirst (pulls update the
+// superproject before its submodules). Among repos that are free to go,
+// the given order (priority) decides. Repos in a cycle keep the given order.
+func syncOrder(repos []string, dependentsFirst bool) []string {
+	dependencies = resolveDependencies(repos)
+
+	// waitsFor[repo] lists the repos that must be done before it
+	waitsFor := map[string][]string{}
+	for repo, deps := range dependencies {
+		for _, dep := range deps {
+			if dependentsFirst {
+				waitsFor[dep] = append(waitsFor[dep], repo)
+			} else {
+				waitsFor[repo] = append(waitsFor[repo], dep)
+			}
+		}
+	}
+
+	done := map[string]bool{}
+	var ordered []string
+	for len(ordered) < len(repos) {
+		progress := false
+		for _, repo := range repos {
+			if done[repo] || !allDone(waitsFor[repo], done) {
+				continue
+			}
+			done[repo] = true
+			ordered = append(ordered, repo)
+			progress = true
+			break // Rescan so a higher-priority repo freed by this one goes next
+		}
+		if !progress {
+			var stuck []string
+			for _, repo := range repos {
+				if !done[repo] {
+					stuck = append(stuck, repo)
+					ordered = append(ordered, repo)
+				}
+			}
+			reportCycle(stuck)
+			return ordered
+		}
+	}
+	reportCycle(nil)
+	return ordered
+}
+
+// allDone reports whether every repo in the list is done
+func allDone(repos []string, done map[string]bool) bool {
+	for _, repo := range repos {
+		if !done[repo] {
+			return false
+		}
+	}
+	return true
+}
+
+// reportCycle warns once about repos whose depends_on form a cycle
+func reportCycle(stuck []string) {
+	notice := strings.Join(stuck, ", ")
+	if notice != "" && notice != cycleNotice {
+		fmt.Printf(tr("  ⚠️  depends_on forms a cycle between %s, syncing them in priority order\n"), notice)
+	}
+	cycleNotice = notice
+}
+
+// unpushedDependency returns the first dependency of a repo whose HEAD has
+// commits no remote-tracking branch has, so the repo's push can wait until they are out
+func unpushedDependency(repoPath string) string {
+	for _, dep := range dependencies[repoPath] {
+		if _, deferred := deferredPushes[dep]; deferred {
+			return dep
+		}
+		dir := dependencyDirs[dep]
+		if dir == "" {
+			continue
+		}
+		if remotes, err := gitCommand("-C", dir, "remote").Output(); err != nil || len(remotes) == 0 {
+			continue // Local-only repos have nothing to push
+		}
+		output, err := gitCommand("-C", dir, "rev-list", "--count", "HEAD", "--not", "--remotes").Output()
+		if err == nil && strings.TrimSpace(string(output)) != "0" {
+			return dep
+		}
+	}
+	return ""
+}
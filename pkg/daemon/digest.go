@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DigestConfig enables the daily activity summary
+type DigestConfig struct {
+	Time string `json:"time,omitempty"` // "HH:MM" local time to send the day's digest, empty disables
+}
+
+// digestMarker remembers the last day a digest went out, so restarts don't resend it
+const digestMarker = "digest-last"
+
+// checkDigest sends today's digest through the notification channels once the
+// configured time has passed
+func checkDigest() {
+	if config.Digest.Time == "" {
+		return
+	}
+	at, err := time.Parse("15:04", config.Digest.Time)
+	if err != nil {
+		return // Rejected when the config is loaded
+	}
+
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if now.Before(dayStart.Add(time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute)) {
+		return
+	}
+
+	marker := filepath.Join(stateDir(), digestMarker)
+	today := dayStart.Format("2006-01-02")
+	if last, err := os.ReadFile(marker); err == nil && strings.TrimSpace(string(last)) == today {
+		return
+	}
+
+	events, err := readEvents(dayStart, now)
+	if err != nil {
+		fmt.Printf(tr("  ⚠️  Digest: reading history failed: %v\n"), err)
+		return
+	}
+
+	text := digestText(events)
+	fmt.Printf(tr("\n📰 Daily digest %s:\n%s\n"), today, text)
+	notify(tr("Git Air daily digest ")+today, text, "")
+	os.MkdirAll(stateDir(), 0755)
+	os.WriteFile(marker, []byte(today+"\n"), 0644)
+}
+
+// digestText renders the summary of a day's events
+func digestText(events []Event) string {
+	if len(events) == 0 {
+		return "No activity today."
+	}
+
+	repos, total := summarizeEvents(events)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, tr("%d commits in %d repos (+%d/-%d lines), %d pushes, %d pulls, %d failures\n"),
+		total.Commits, len(repos), total.Added, total.Deleted, total.Pushes, total.Pulls, total.failures())
+	for _, r := range repos {
+		fmt.Fprintf(&b, tr("• %s: %d commits (+%d/-%d), %d pushes, %d pulls"), filepath.Base(r.Repo), r.Commits, r.Added, r.Deleted, r.Pushes, r.Pulls)
+		if r.failures() > 0 {
+			fmt.Fprintf(&b, tr(", %d failures"), r.failures())
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
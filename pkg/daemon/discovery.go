@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+const (
+	// rediscoverInterval is how often the main loop looks for new repositories
+	rediscoverInterval = 5 * time.Minute
+
+	// fullScanAge forces a complete walk once the cache is this old, in case
+	// a directory changed without its mtime changing
+	fullScanAge = 24 * time.Hour
+)
+
+// discoveryCache is the persisted result of the last scan of a root: every
+// directory walked with its mtime, subdirectories and whether it holds a .git
+type discoveryCache struct {
+	Root    string                   `json:"root"`
+	Scanned time.Time                `json:"scanned"`
+	Dirs    map[string]discoveredDir `json:"dirs"`
+}
+
+type discoveredDir struct {
+	ModTime int64    `json:"mtime"`
+	Subdirs []string `json:"subdirs,omitempty"`
+	Repo    bool     `json:"repo,omitempty"`
+}
+
+// findGitRepos finds all .git directories below root. Directories whose
+// mtime is unchanged since the last scan are not read again, only stat'ed,
+// so repeated scans of large trees are cheap.
+func findGitRepos(root string) ([]string, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	cache := loadDiscoveryCache(root)
+	if time.Since(cache.Scanned) > fullScanAge {
+		cache.Dirs = nil
+	}
+	// Scanned is when the tree was last walked in full, which only a walk
+	// without a cache does
+	fresh := discoveryCache{Root: cache.Root, Scanned: cache.Scanned, Dirs: map[string]discoveredDir{}}
+	if cache.Dirs == nil {
+		fresh.Scanned = time.Now()
+	}
+
+	// Cache keys are relative to root, so "." and its absolute path share them
+	var repos []string
+	var scan func(rel string)
+	scan = func(rel string) {
+		dir := filepath.Join(root, rel)
+		info, err := os.Lstat(longPath(dir))
+		if err != nil || !info.IsDir() {
+			return // Skip errors
+		}
+
+		entry, ok := cache.Dirs[rel]
+		if !ok || entry.ModTime != info.ModTime().UnixNano() {
+			entry = readDiscoveredDir(dir, info)
+		}
+		fresh.Dirs[rel] = entry
+
+		if entry.Repo {
+			repos = append(repos, dir)
+		}
+		for _, name := range entry.Subdirs {
+			scan(filepath.Join(rel, name))
+		}
+	}
+	scan(".")
+
+	saveDiscoveryCache(fresh)
+	return repos, nil
+}
+
+// readDiscoveredDir lists a directory's subdirectories, skipping .git and
+// common dependency dirs
+func readDiscoveredDir(dir string, info os.FileInfo) discoveredDir {
+	entry := discoveredDir{ModTime: info.ModTime().UnixNano()}
+	items, err := os.ReadDir(longPath(dir))
+	if err != nil {
+		return entry
+	}
+	for _, item := range items {
+		if !item.IsDir() {
+			continue
+		}
+		switch name := item.Name(); {
+		case name == ".git":
+			entry.Repo = true
+		case !gitair.Skipped(name):
+			entry.Subdirs = append(entry.Subdirs, name)
+		}
+	}
+	return entry
+}
+
+// discoveryCacheFile is the cache path for a scan root
+func discoveryCacheFile(root string) string {
+	return filepath.Join(stateDir(), "discovery", sha256Hex([]byte(root))[:16]+".json")
+}
+
+// loadDiscoveryCache reads the cache for root, empty if there is none
+func loadDiscoveryCache(root string) discoveryCache {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	cache := discoveryCache{Root: abs}
+	data, err := os.ReadFile(discoveryCacheFile(abs))
+	if err != nil {
+		return cache
+	}
+	var stored discoveryCache
+	if json.Unmarshal(data, &stored) != nil || stored.Root != abs {
+		return cache
+	}
+	return stored
+}
+
+// saveDiscoveryCache writes the cache; failing to do so only costs speed
+func saveDiscoveryCache(cache discoveryCache) {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	file := discoveryCacheFile(cache.Root)
+	if os.MkdirAll(filepath.Dir(file), 0755) != nil {
+		return
+	}
+	tmp := file + ".tmp"
+	if os.WriteFile(tmp, data, 0644) == nil {
+		os.Rename(tmp, file)
+	}
+}
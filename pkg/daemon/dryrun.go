@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dryRun shows what a cycle would commit and push without touching the
+// repos (--dry-run): nothing is staged, committed, pushed, pulled or
+// written into .git, and git-air exits after one cycle
+var dryRun bool
+
+// dryRunListLimit is how many changed files a dry run lists per repo
+const dryRunListLimit = 20
+
+// showDryRunCommit prints the commit processRepo would make. Returns true,
+// as the repo has changes.
+func showDryRunCommit(repoName string, changes []fileChange, message, capture string) bool {
+	if capture != "commit" {
+		fmt.Printf(tr("  🧪 Would capture %d file(s) (capture: %s)\n"), len(changes), capture)
+		return true
+	}
+	fmt.Printf(tr("  🧪 Would commit %d file(s):\n"), len(changes))
+	for i, change := range changes {
+		if i == dryRunListLimit {
+			fmt.Printf(tr("     ... and %d more\n"), len(changes)-i)
+			break
+		}
+		fmt.Printf("     %s %s\n", strings.TrimSpace(change.Status), change.Path)
+	}
+	fmt.Printf(tr("  🧪 Message: %s\n"), strings.ReplaceAll(message, "\n", "\n              "))
+	return true
+}
+
+// showDryRunPush prints where pushToAllRemotes would push
+func showDryRunPush(remotes []string, branch string) {
+	for _, remote := range remotes {
+		url := gitConfigValue("remote." + remote + ".url")
+		fmt.Printf(tr("  🧪 Would push %s to %s (%s)\n"), branch, remote, url)
+	}
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Seconds is a duration setting given as a number of seconds or as a Go
+// duration string such as "90s" or "2m30s"
+type Seconds time.Duration
+
+// Minutes is a duration setting given as a number of minutes (fractions
+// allowed) or as a Go duration string such as "45s" or "4h"
+type Minutes time.Duration
+
+// UnmarshalJSON reads a number of seconds or a duration string
+func (s *Seconds) UnmarshalJSON(data []byte) error {
+	d, err := durationJSON(data, time.Second)
+	*s = Seconds(d)
+	return err
+}
+
+// UnmarshalJSON reads a number of minutes or a duration string
+func (m *Minutes) UnmarshalJSON(data []byte) error {
+	d, err := durationJSON(data, time.Minute)
+	*m = Minutes(d)
+	return err
+}
+
+// durationJSON reads a JSON number in unit or a JSON string for parseDuration
+func durationJSON(data []byte, unit time.Duration) (time.Duration, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return parseDuration(text, unit)
+	}
+	var number float64
+	if err := json.Unmarshal(data, &number); err != nil {
+		return 0, fmt.Errorf("want a number or a duration such as \"45s\" or \"4h\", got %s", data)
+	}
+	return time.Duration(number * float64(unit)), nil
+}
+
+// parseDuration reads a Go duration ("45s", "2m30s", "4h") or a bare number
+// in unit
+func parseDuration(text string, unit time.Duration) (time.Duration, error) {
+	if number, err := strconv.ParseFloat(text, 64); err == nil {
+		return time.Duration(number * float64(unit)), nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number or a duration such as 45s, 2m30s or 4h", text)
+	}
+	return d, nil
+}
+
+// formatInterval renders a duration without zero units: 30s, 2m30s, 4h
+func formatInterval(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
@@ -0,0 +1,267 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Encrypt keeps matching files encrypted with age in the repository. The
+// plaintext stays in the working tree (never staged), `<file>.age` is committed.
+type Encrypt struct {
+	Patterns   []string `json:"patterns"`           // e.g. "secrets/*.yaml", same matching as transient_patterns
+	Recipients []string `json:"recipients"`         // age or SSH public keys that can decrypt
+	Identity   string   `json:"identity,omitempty"` // age identity file used to decrypt pulled changes
+}
+
+// ciphertextInclude follows the plaintext patterns in .git/info/exclude, so
+// a pattern like secrets/* doesn't ignore the .age files as well
+const ciphertextInclude = "!*.age"
+
+// sealStateFile remembers the hashes of each plaintext and ciphertext pair at
+// the last encryption or decryption. age output differs on every run, so this
+// is how unchanged files are told apart from edited ones.
+const sealStateFile = "git-air-sealed.json"
+
+// sealState is the last synchronized content of one encrypted file
+type sealState struct {
+	Plain  string `json:"plain"`
+	Cipher string `json:"cipher"`
+}
+
+// validate checks the encryption rules
+func (e *Encrypt) validate() error {
+	for _, pattern := range e.Patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid encrypt pattern %q", pattern)
+		}
+	}
+	if len(e.Patterns) > 0 && len(e.Recipients) == 0 {
+		return fmt.Errorf("encrypt needs at least one recipient")
+	}
+	return nil
+}
+
+// syncEncrypted brings plaintext and ciphertext in line in the current repo:
+// edited plaintext is encrypted to <file>.age, pulled ciphertext is decrypted.
+// If both changed, the file is left alone and an alert raised.
+func syncEncrypted(repoPath string, enc *Encrypt) {
+	if enc == nil || len(enc.Patterns) == 0 {
+		return
+	}
+	repoName := filepath.Base(repoPath)
+
+	gitDir, err := gitCommand("rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return
+	}
+	stateFile := filepath.Join(strings.TrimSpace(string(gitDir)), sealStateFile)
+	state := map[string]sealState{}
+	if data, err := os.ReadFile(stateFile); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	excludePlaintext(strings.TrimSpace(string(gitDir)), enc.Patterns)
+	untrackPlaintext(repoPath, enc.Patterns)
+
+	var conflicts []string
+	changed := false
+	for _, path := range encryptedCandidates(enc.Patterns) {
+		cipherPath := path + ".age"
+		plain, plainErr := os.ReadFile(inRepo(path))
+		cipher, cipherErr := os.ReadFile(inRepo(cipherPath))
+		last := state[path]
+		plainChanged := plainErr == nil && sha256Hex(plain) != last.Plain
+		cipherChanged := cipherErr == nil && sha256Hex(cipher) != last.Cipher
+
+		switch {
+		case plainChanged && cipherChanged:
+			// Both sides are new to us, e.g. a fresh clone that already has the plaintext
+			if decrypted, err := ageDecrypt(enc.Identity, cipherPath); err == nil && bytes.Equal(decrypted, plain) {
+				state[path] = sealState{Plain: sha256Hex(plain), Cipher: sha256Hex(cipher)}
+				changed = true
+				continue
+			}
+			conflicts = append(conflicts, path)
+		case plainChanged:
+			cmd := exec.Command("age", append(ageRecipientArgs(enc.Recipients), "-o", cipherPath, path)...)
+			cmd.Dir = repoDir
+			if output, err := cmd.CombinedOutput(); err != nil {
+				fmt.Printf(tr("  ❌ Encrypting %s in %s failed: %s\n"), path, repoName, lastLine(string(output)))
+				raiseAlert(repoPath, "encrypt", fmt.Sprintf(tr("%s: encrypting %s failed"), displayName(repoPath), path))
+				return
+			}
+			// Let the settle check judge the edit, not the encryption that just happened
+			if info, err := os.Stat(inRepo(path)); err == nil {
+				os.Chtimes(inRepo(cipherPath), info.ModTime(), info.ModTime())
+			}
+			sealed, _ := os.ReadFile(inRepo(cipherPath))
+			state[path] = sealState{Plain: sha256Hex(plain), Cipher: sha256Hex(sealed)}
+			changed = true
+		case cipherChanged:
+			decrypted, err := ageDecrypt(enc.Identity, cipherPath)
+			if err != nil {
+				fmt.Printf(tr("  ❌ Decrypting %s in %s failed: %v\n"), cipherPath, repoName, err)
+				raiseAlert(repoPath, "encrypt", fmt.Sprintf(tr("%s: decrypting %s failed: %v"), displayName(repoPath), cipherPath, err))
+				return
+			}
+			if err := os.WriteFile(inRepo(path), decrypted, 0600); err != nil {
+				fmt.Printf(tr("  ❌ Writing %s in %s failed: %v\n"), path, repoName, err)
+				return
+			}
+			fmt.Printf(tr("  🔑 Decrypted %s in %s\n"), cipherPath, repoName)
+			state[path] = sealState{Plain: sha256Hex(decrypted), Cipher: sha256Hex(cipher)}
+			changed = true
+		}
+	}
+
+	if changed {
+		if data, err := json.MarshalIndent(state, "", "  "); err == nil {
+			os.WriteFile(stateFile, data, 0600)
+		}
+	}
+	if len(conflicts) > 0 {
+		fmt.Printf(tr("  ⚠️  %s: %s changed locally and upstream, resolve by hand\n"), repoName, describeCount(conflicts))
+		raiseAlert(repoPath, "encrypt", fmt.Sprintf(tr("%s: %s changed locally and upstream - merge into the plaintext and delete the .age file to keep it, or delete the plaintext to take upstream"), displayName(repoPath), describeCount(conflicts)))
+		return
+	}
+	clearAlert(repoPath, "encrypt")
+}
+
+// encryptedCandidates lists the plaintext paths to keep in sync: files that
+// match a pattern, plus the plaintext side of matching *.age files
+func encryptedCandidates(patterns []string) []string {
+	seen := map[string]bool{}
+	var paths []string
+	root := inRepo(".")
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, ".git")); err == nil && path != root {
+				return filepath.SkipDir // Submodules and nested repos have their own settings
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		plain := filepath.ToSlash(strings.TrimSuffix(rel, ".age"))
+		if matchesAny(patterns, plain) && !seen[plain] {
+			seen[plain] = true
+			paths = append(paths, plain)
+		}
+		return nil
+	})
+	return paths
+}
+
+// excludePlaintext adds the patterns to .git/info/exclude, so manual git use
+// doesn't commit the plaintext either. They end with a !*.age line that
+// takes the ciphertext back in.
+func excludePlaintext(gitDir string, patterns []string) {
+	file := filepath.Join(gitDir, "info", "exclude")
+	data, _ := os.ReadFile(file)
+	existing := map[string]bool{}
+	last := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			existing[line] = true
+			last = line
+		}
+	}
+
+	var missing []string
+	for _, pattern := range patterns {
+		if !existing[pattern] {
+			missing = append(missing, pattern)
+		}
+	}
+	if len(missing) == 0 && last == ciphertextInclude {
+		return
+	}
+	missing = append(missing, ciphertextInclude)
+
+	text := string(data)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += "# git-air: plaintext of age-encrypted files\n" + strings.Join(missing, "\n") + "\n"
+	os.MkdirAll(filepath.Dir(file), 0755)
+	os.WriteFile(file, []byte(text), 0644)
+}
+
+// untrackPlaintext removes plaintext committed before encryption was set up
+// from the index, which the exclude file can't do, so the next commit
+// deletes it from the repository while the file stays on disk. Its history
+// still holds it, which the alert says.
+func untrackPlaintext(repoPath string, patterns []string) {
+	tracked := trackedPlaintext(patterns)
+	if len(tracked) == 0 {
+		return
+	}
+
+	output, err := gitCommand(append([]string{"rm", "--cached", "--quiet", "--"}, tracked...)...).CombinedOutput()
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: untracking the plaintext of %s failed: %s\n"), filepath.Base(repoPath), describeCount(tracked), lastLine(string(output)))
+		raiseAlert(repoPath, "plaintext-tracked", fmt.Sprintf(tr("%s: %s to be encrypted still tracked as plaintext, untracking failed - run git rm --cached on them"), displayName(repoPath), describeCount(tracked)))
+		return
+	}
+	fmt.Printf(tr("  🔒 %s: untracked the plaintext of %s, only the .age file is committed from now on\n"), filepath.Base(repoPath), describeCount(tracked))
+	raiseAlert(repoPath, "plaintext-tracked", fmt.Sprintf(tr("%s: %s had been committed as plaintext and is now untracked - it is still in the history, so rotate those secrets"), displayName(repoPath), describeCount(tracked)))
+}
+
+// trackedPlaintext lists the plaintext of encrypted files in the index
+func trackedPlaintext(patterns []string) []string {
+	output, err := gitCommand("ls-files", "-z").Output()
+	if err != nil {
+		return nil
+	}
+	var tracked []string
+	for _, path := range nulList(output) {
+		if encryptedPlaintext(patterns, path) {
+			tracked = append(tracked, path)
+		}
+	}
+	return tracked
+}
+
+// encryptedPlaintext reports whether a path is the plaintext of an encrypted
+// file. Its .age file is committed even where a pattern such as secrets/*
+// matches it as well.
+func encryptedPlaintext(patterns []string, path string) bool {
+	return !strings.HasSuffix(path, ".age") && matchesAny(patterns, path)
+}
+
+// ageRecipientArgs turns recipients into age command-line arguments
+func ageRecipientArgs(recipients []string) []string {
+	args := []string{"--encrypt"}
+	for _, recipient := range recipients {
+		args = append(args, "-r", recipient)
+	}
+	return args
+}
+
+// ageDecrypt returns the plaintext of an age file
+func ageDecrypt(identity, file string) ([]byte, error) {
+	if identity == "" {
+		return nil, fmt.Errorf("no encrypt identity configured")
+	}
+	cmd := exec.Command("age", "--decrypt", "-i", expandHome(identity), file)
+	cmd.Dir = repoDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := lastLine(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"git-air/pkg/gitair"
+)
+
+// events is the engine's event stream. The state store and the console
+// subscribe to it here; the trigger endpoint streams it at /events.
+var events = &gitair.Bus{}
+
+func init() {
+	events.Subscribe(storeEvent)
+	events.Subscribe(printEvent)
+}
+
+// Events is the daemon's event stream, for programs that embed it; Syncer
+// publishes what it does here
+func Events() *gitair.Bus {
+	return events
+}
+
+// publish sends an event about the current repo
+func publish(event gitair.Event) {
+	if event.Repo == "" {
+		event.Repo = getCurrentDir()
+	}
+	events.Publish(event)
+}
+
+// printEvent is the console's view of the engine: commits, pushes, pulls
+// and their failures are only printed from here
+func printEvent(event gitair.Event) {
+	name := filepath.Base(event.Repo)
+	switch event.Type {
+	case gitair.Committed:
+		fmt.Printf(tr("  ✓ Committed changes in %s\n"), name)
+	case gitair.Pushed:
+		fmt.Printf(tr("  🚀 %s: pushed to %s\n"), name, event.Remote)
+	case gitair.UpToDate:
+		fmt.Printf(tr("  📥 %s: %s is up to date\n"), name, event.Remote)
+	case gitair.PullMerged:
+		fmt.Printf(tr("  📡 %s: pulled updates from %s\n"), name, event.Remote)
+	case gitair.Diverged:
+		fmt.Printf(tr("  ⚠️  %s: diverged from %s\n"), name, event.Remote)
+	case gitair.Error:
+		switch event.Op {
+		case "commit":
+			fmt.Printf(tr("  ⚠️  Commit failed in %s: %s\n"), name, event.Err)
+		case "push":
+			fmt.Printf(tr("  ❌ %s: push to %s failed: %s\n"), name, event.Remote, event.Err)
+		case "pull":
+			fmt.Printf(tr("  ❌ %s: pull from %s failed: %s\n"), name, event.Remote, event.Err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Forge describes the API of a git hosting service that remotes point at
+type Forge struct {
+	Type     string `json:"type,omitempty"`      // "github", "gitlab" or "gitea" (also Forgejo)
+	API      string `json:"api,omitempty"`       // API base URL, default derived from the host
+	TokenEnv string `json:"token_env,omitempty"` // env var holding an API token
+}
+
+// knownForges are used for hosts not listed in the config
+var knownForges = map[string]Forge{
+	"github.com":   {Type: "github", TokenEnv: "GITHUB_TOKEN"},
+	"gitlab.com":   {Type: "gitlab", TokenEnv: "GITLAB_TOKEN"},
+	"codeberg.org": {Type: "gitea", TokenEnv: "CODEBERG_TOKEN"},
+}
+
+// forgeRepo is a repository on a forge
+type forgeRepo struct {
+	Host  string
+	Path  string // owner/name, or group/subgroup/name on GitLab
+	Forge Forge
+}
+
+// validate checks a configured forge
+func (f Forge) validate() error {
+	switch f.Type {
+	case "github", "gitlab", "gitea":
+		return nil
+	default:
+		return fmt.Errorf("forge type must be github, gitlab or gitea, got %q", f.Type)
+	}
+}
+
+// remoteForge resolves a remote URL to its forge repository. ok is false for
+// local paths and hosts that are not a known or configured forge.
+func remoteForge(remoteURL string) (forgeRepo, bool) {
+	host, path := splitRemoteURL(remoteURL)
+	if host == "" || path == "" {
+		return forgeRepo{}, false
+	}
+	forge, ok := config.Forges[host]
+	if !ok {
+		forge, ok = knownForges[host]
+	}
+	if !ok {
+		return forgeRepo{}, false
+	}
+	if forge.API == "" {
+		switch forge.Type {
+		case "github":
+			forge.API = "https://api.github.com"
+			if host != "github.com" {
+				forge.API = "https://" + host + "/api/v3" // GitHub Enterprise
+			}
+		case "gitlab":
+			forge.API = "https://" + host + "/api/v4"
+		case "gitea":
+			forge.API = "https://" + host + "/api/v1"
+		}
+	}
+	return forgeRepo{Host: host, Path: path, Forge: forge}, true
+}
+
+// splitRemoteURL returns host and repository path of https://, ssh:// and
+// scp-like (git@host:owner/repo.git) remote URLs
+func splitRemoteURL(remoteURL string) (string, string) {
+	remoteURL = strings.TrimSpace(remoteURL)
+	var host, path string
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil || u.Scheme == "file" {
+			return "", ""
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remoteURL, ":"); at > 0 && !strings.Contains(remoteURL[:at], "/") {
+		host, path = remoteURL[:at], remoteURL[at+1:]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+	} else {
+		return "", "" // Local path
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return strings.ToLower(host), path
+}
+
+// get calls a forge API endpoint and decodes the JSON response
+func (r forgeRepo) get(endpoint string, result interface{}) error {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(r.Forge.API, "/")+endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if r.Forge.TokenEnv != "" {
+		if token := os.Getenv(r.Forge.TokenEnv); token != "" {
+			switch r.Forge.Type {
+			case "github":
+				req.Header.Set("Authorization", "Bearer "+token)
+			case "gitlab":
+				req.Header.Set("PRIVATE-TOKEN", token)
+			case "gitea":
+				req.Header.Set("Authorization", "token "+token)
+			}
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := http.Client{Timeout: 20 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", r.Host, resp.Status)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("%s: unexpected response: %v", r.Host, err)
+	}
+	return nil
+}
+
+// ownerRepo returns the path for /repos/{owner}/{repo} style endpoints
+func (r forgeRepo) ownerRepo() string {
+	return "/repos/" + r.Path
+}
+
+// projectID returns the URL-encoded project path GitLab uses as an ID
+func (r forgeRepo) projectID() string {
+	return "/projects/" + url.PathEscape(r.Path)
+}
@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"strings"
+)
+
+// generatedFiles returns the paths that are generated: marked
+// linguist-generated in .gitattributes or matching one of the patterns
+func generatedFiles(paths []string, patterns []string) map[string]bool {
+	generated := map[string]bool{}
+	if len(paths) == 0 {
+		return generated
+	}
+
+	cmd := gitCommand("check-attr", "-z", "--stdin", "linguist-generated")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	if output, err := cmd.Output(); err == nil {
+		// Output is path NUL attribute NUL value NUL, repeated
+		fields := strings.Split(string(output), "\x00")
+		for i := 0; i+2 < len(fields); i += 3 {
+			if value := fields[i+2]; value == "set" || value == "true" {
+				generated[fields[i]] = true
+			}
+		}
+	}
+	for _, path := range paths {
+		if matchesAny(patterns, path) {
+			generated[path] = true
+		}
+	}
+	return generated
+}
+
+// withoutGenerated returns pathspecs for a diff of the paths minus the
+// generated ones, and the generated paths that were left out
+func withoutGenerated(paths []string, patterns []string) ([]string, []string) {
+	generated := generatedFiles(paths, patterns)
+	if len(generated) == 0 {
+		return nil, nil
+	}
+	pathspecs := []string{"."}
+	var skipped []string
+	for _, path := range paths {
+		if generated[path] {
+			pathspecs = append(pathspecs, ":(exclude,literal)"+path)
+			skipped = append(skipped, path)
+		}
+	}
+	return pathspecs, skipped
+}
+
+// nulList splits NUL-separated git output
+func nulList(output []byte) []string {
+	return strings.FieldsFunc(string(output), func(r rune) bool { return r == 0 })
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// repoLockFile is the advisory lock git-air holds while working on a repo.
+// Scripts can take it too (flock .git/git-air.lock git ...) to keep git-air out.
+const repoLockFile = "git-air.lock"
+
+// gitOperations are the files git keeps in the git dir while a command or a
+// multi-step operation is under way
+var gitOperations = []struct{ path, what string }{
+	{"index.lock", "a git command is running"},
+	{"MERGE_HEAD", "a merge is in progress"},
+	{"rebase-merge", "a rebase is in progress"},
+	{"rebase-apply", "a rebase is in progress"},
+	{"CHERRY_PICK_HEAD", "a cherry-pick is in progress"},
+	{"REVERT_HEAD", "a revert is in progress"},
+	{"BISECT_LOG", "a bisect is in progress"},
+}
+
+// claimRepo takes the advisory lock of the current repo
+// unless someone else holds it or a git operation is in progress. It returns
+// the function releasing the lock, or why the repo has to wait.
+func claimRepo(repoPath string) (func(), string) {
+	output, err := gitCommand("rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return func() {}, "" // Not our problem here, the git commands report it
+	}
+	gitDir := strings.TrimSpace(string(output))
+
+	if busy := gitInProgress(repoPath, gitDir); busy != "" {
+		return nil, busy
+	}
+	unlock, err := lockFile(filepath.Join(gitDir, repoLockFile))
+	if err != nil {
+		return nil, "locked by another git-air or a script"
+	}
+	return unlock, ""
+}
+
+// gitBusy describes the git operation under way in the current repo, or ""
+func gitBusy(repoPath string) string {
+	output, err := gitCommand("rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return gitInProgress(repoPath, strings.TrimSpace(string(output)))
+}
+
+// gitInProgress describes the git operation under way in gitDir, or "". An
+// index.lock that stays around for long is reported, as git itself fails
+// until it is removed.
+func gitInProgress(repoPath, gitDir string) string {
+	for _, op := range gitOperations {
+		info, err := os.Stat(filepath.Join(gitDir, op.path))
+		if err != nil {
+			continue
+		}
+		if op.path == "index.lock" && time.Since(info.ModTime()) > 10*time.Minute {
+			raiseAlert(repoPath, "index-lock", fmt.Sprintf(tr("%s: .git/index.lock is %s old - delete it if no git command is running"), displayName(repoPath), formatSpent(time.Since(info.ModTime()))))
+		}
+		return op.what
+	}
+	clearAlert(repoPath, "index-lock")
+	return ""
+}
@@ -0,0 +1,212 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// Event is one thing git-air did to a repository, as kept in the state store
+type Event struct {
+	Time    time.Time `json:"time"`
+	Repo    string    `json:"repo"`             // absolute repository path
+	Kind    string    `json:"kind"`             // "commit", "push" or "pull"
+	OK      bool      `json:"ok"`               // false for failed operations
+	Remote  string    `json:"remote,omitempty"` // push and pull only
+	Files   int       `json:"files,omitempty"`  // commit only
+	Added   int       `json:"added,omitempty"`
+	Deleted int       `json:"deleted,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// historyFile is the append-only event log inside the state directory
+const historyFile = "history.jsonl"
+
+// stateDir returns the directory for git-air's persistent state
+// ($XDG_STATE_HOME/git-air, default ~/.local/state/git-air)
+func stateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "git-air")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "git-air")
+	}
+	return filepath.Join(home, ".local", "state", "git-air")
+}
+
+// recordEvent appends an event for the current repository.
+// The state store is best effort: failing to write it never stops syncing.
+func recordEvent(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Repo == "" {
+		event.Repo = getCurrentDir()
+	}
+
+	dir := stateDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(filepath.Join(dir, historyFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	f.Write(append(line, '\n'))
+}
+
+// readEvents returns the recorded events with from <= time < to
+func readEvents(from, to time.Time) ([]Event, error) {
+	f, err := os.Open(filepath.Join(stateDir(), historyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if json.Unmarshal(scanner.Bytes(), &event) != nil {
+			continue // Skip lines from a crash mid-write
+		}
+		if !event.Time.Before(from) && event.Time.Before(to) {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// storeEvent records the engine events the state store keeps: commits,
+// pushes, merged pulls and their failures
+func storeEvent(event gitair.Event) {
+	stored := Event{Time: event.Time, Repo: event.Repo, OK: true, Remote: event.Remote, Files: event.Files, Added: event.Added, Deleted: event.Deleted}
+	switch event.Type {
+	case gitair.Committed:
+		stored.Kind = "commit"
+	case gitair.Pushed:
+		stored.Kind = "push"
+	case gitair.PullMerged:
+		stored.Kind = "pull"
+	case gitair.Error:
+		stored.Kind, stored.OK, stored.Error = event.Op, false, event.Err
+	default:
+		return
+	}
+	recordEvent(stored)
+}
+
+// commitEvent describes the HEAD commit, just made with message
+func commitEvent(message string) gitair.Event {
+	event := gitair.Event{Type: gitair.Committed, Message: message}
+	cmd := gitCommand("show", "--numstat", "--format=", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return event
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		event.Files++
+		// Binary files show "-" instead of line counts
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			event.Added += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			event.Deleted += n
+		}
+	}
+	return event
+}
+
+// errorEvent describes a failed operation with git's last output line
+func errorEvent(op, remote, output string) gitair.Event {
+	return gitair.Event{Type: gitair.Error, Op: op, Remote: remote, Err: lastLine(output)}
+}
+
+// RepoStats sums up one repository's events over a period
+type RepoStats struct {
+	Repo           string `json:"repo,omitempty"`
+	Commits        int    `json:"commits"`
+	Files          int    `json:"files_changed"`
+	Added          int    `json:"lines_added"`
+	Deleted        int    `json:"lines_deleted"`
+	Pushes         int    `json:"pushes"`
+	PushFailures   int    `json:"push_failures"`
+	Pulls          int    `json:"pulls"`
+	PullFailures   int    `json:"pull_failures"`
+	CommitFailures int    `json:"commit_failures"`
+	LastError      string `json:"last_error,omitempty"`
+}
+
+// failures returns the number of failed operations
+func (s RepoStats) failures() int {
+	return s.PushFailures + s.PullFailures + s.CommitFailures
+}
+
+// add counts one event
+func (s *RepoStats) add(event Event) {
+	if !event.OK {
+		s.LastError = event.Error
+	}
+	switch {
+	case event.Kind == "commit" && event.OK:
+		s.Commits++
+		s.Files += event.Files
+		s.Added += event.Added
+		s.Deleted += event.Deleted
+	case event.Kind == "commit":
+		s.CommitFailures++
+	case event.Kind == "push" && event.OK:
+		s.Pushes++
+	case event.Kind == "push":
+		s.PushFailures++
+	case event.Kind == "pull" && event.OK:
+		s.Pulls++
+	case event.Kind == "pull":
+		s.PullFailures++
+	}
+}
+
+// summarizeEvents groups events into per-repository stats sorted by path,
+// plus the totals over all repositories
+func summarizeEvents(events []Event) ([]RepoStats, RepoStats) {
+	perRepo := map[string]*RepoStats{}
+	var total RepoStats
+	for _, event := range events {
+		stats := perRepo[event.Repo]
+		if stats == nil {
+			stats = &RepoStats{Repo: event.Repo}
+			perRepo[event.Repo] = stats
+		}
+		stats.add(event)
+		total.add(event)
+	}
+
+	result := make([]RepoStats, 0, len(perRepo))
+	for _, stats := range perRepo {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Repo < result[j].Repo })
+	return result, total
+}
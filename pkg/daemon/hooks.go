@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PullHook is a command run in a repo after a pull brought in changes, e.g.
+// {"command": "npm install", "paths": ["package-lock.json"]}
+type PullHook struct {
+	Command string   `json:"command"`
+	Paths   []string `json:"paths,omitempty"` // run only when a pulled file matches one, empty runs always
+}
+
+// validate checks the command and path patterns
+func (h PullHook) validate() error {
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("after_pull hook needs a command")
+	}
+	for _, pattern := range h.Paths {
+		if _, err := filepath.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return fmt.Errorf("invalid after_pull pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// runPullHooks runs the after_pull hooks whose paths match the files changed
+// between before and HEAD. They run with sh (cmd.exe on Windows) in the repo, with GIT_AIR_REPO,
+// GIT_AIR_REMOTE, GIT_AIR_BEFORE and GIT_AIR_AFTER set.
+func runPullHooks(repoPath, remote, before string, hooks []PullHook) {
+	if len(hooks) == 0 {
+		return
+	}
+	output, err := gitCommand("diff", "--name-only", "-z", before, "HEAD").Output()
+	if err != nil {
+		return
+	}
+	changed := nulList(output)
+	after, _ := gitOutput("rev-parse", "HEAD")
+
+	for _, hook := range hooks {
+		if len(hook.Paths) > 0 && !anyMatches(hook.Paths, changed) {
+			continue
+		}
+		fmt.Printf(tr("  🪝 Running %s..."), hook.Command)
+		cmd := shellCommand(hook.Command)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(),
+			"GIT_AIR_REPO="+repoPath,
+			"GIT_AIR_REMOTE="+remote,
+			"GIT_AIR_BEFORE="+before,
+			"GIT_AIR_AFTER="+strings.TrimSpace(after),
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			fmt.Printf(tr(" ❌ failed\n"))
+			raiseAlert(repoPath, "hook", fmt.Sprintf(tr("%s: after_pull command %q failed: %s"), displayName(repoPath), hook.Command, lastLine(string(output))))
+			continue
+		}
+		fmt.Printf(" ✓\n")
+		clearAlert(repoPath, "hook")
+	}
+}
+
+// anyMatches reports whether one of the paths matches one of the patterns
+func anyMatches(patterns, paths []string) bool {
+	for _, path := range paths {
+		if matchesAny(patterns, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// hostKeyErrors are the SSH messages of an unknown or changed host key
+var hostKeyErrors = []string{
+	"Host key verification failed",
+	"REMOTE HOST IDENTIFICATION HAS CHANGED",
+	"No matching host key",
+	"authenticity of host",
+	"host key is known for",
+}
+
+// hostKeyFailure returns SSH's own report of a host key problem in git
+// output, without git's closing lines, or "" if the output has none
+func hostKeyFailure(output string) string {
+	found := false
+	for _, marker := range hostKeyErrors {
+		if strings.Contains(output, marker) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "fatal: Could not read from remote") || strings.HasPrefix(line, "Please make sure you have the correct access rights") || line == "and the repository exists." {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// sshTarget returns the host and port of an SSH remote URL, port "" for the default
+func sshTarget(remoteURL string) (string, string) {
+	if !sshRemote(remoteURL) {
+		return "", ""
+	}
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", ""
+		}
+		return u.Hostname(), u.Port()
+	}
+	host, _ := splitRemoteURL(remoteURL)
+	return host, ""
+}
+
+// checkHostKey raises a "hostkey:<remote>" alert with SSH's message
+// verbatim when a network command to remote failed on the host key, and
+// clears it once one succeeds
+func checkHostKey(repoPath, remote, output string, failed bool) {
+	if !failed {
+		clearAlert(repoPath, "hostkey:"+remote)
+		return
+	}
+	problem := hostKeyFailure(output)
+	if problem == "" {
+		return
+	}
+	fmt.Printf(tr("  🔐 %s: SSH host key check failed for %s:\n"), displayName(repoPath), remote)
+	for _, line := range strings.Split(problem, "\n") {
+		fmt.Printf("      %s\n", line)
+	}
+	hint := "verify the key and add it with git-air known-hosts"
+	if strings.Contains(problem, "HAS CHANGED") {
+		hint = "the key changed - make sure this is expected before removing the old one with ssh-keygen -R"
+	}
+	raiseAlert(repoPath, "hostkey:"+remote, fmt.Sprintf(tr("%s: SSH host key check failed for %s (%s):\n%s"), displayName(repoPath), remote, hint, problem))
+}
+
+// knownHostsFile is the user's OpenSSH known_hosts
+func knownHostsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// knownHost formats host and port the way known_hosts and ssh-keygen -F do
+func knownHost(host, port string) string {
+	if port == "" || port == "22" {
+		return host
+	}
+	return "[" + host + "]:" + port
+}
+
+// runKnownHosts implements `git-air known-hosts [repo...]`: add the host
+// keys of SSH remotes that are not in known_hosts yet, each only after the
+// user has compared the fingerprints and typed the host name
+func runKnownHosts(args []string) int {
+	fs := flag.NewFlagSet("known-hosts", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air known-hosts [repo...]"))
+		fmt.Println(tr("\nFetches the host keys of SSH remotes missing from ~/.ssh/known_hosts with"))
+		fmt.Println(tr("ssh-keyscan and shows their fingerprints. A key is only added after you type"))
+		fmt.Println(tr("the host name, so compare the fingerprints with the ones your server or"))
+		fmt.Println(tr("forge publishes first. Changed keys are never replaced."))
+	}
+	repos := parseInterspersed(fs, args)
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintln(os.Stderr, tr("❌ Error: known-hosts needs an interactive terminal"))
+		return 1
+	}
+	if len(repos) == 0 {
+		found, err := findGitRepos(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error finding repositories: %v\n"), err)
+			return 1
+		}
+		repos = found
+	}
+	file := knownHostsFile()
+	if file == "" {
+		fmt.Fprintln(os.Stderr, tr("❌ Error: no home directory for ~/.ssh/known_hosts"))
+		return 1
+	}
+
+	// Every SSH host once, however many remotes use it
+	targets := map[string][2]string{}
+	for _, repo := range repos {
+		output, err := gitCommand("-C", repo, "config", "--get-regexp", `^remote\..*\.(url|pushurl)$`).Output()
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			if host, port := sshTarget(fields[1]); host != "" {
+				targets[knownHost(host, port)] = [2]string{host, port}
+			}
+		}
+	}
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	status := 0
+	for _, name := range names {
+		if exec.Command("ssh-keygen", "-F", name, "-f", file).Run() == nil {
+			fmt.Printf(tr("✓ %s is already known\n"), name)
+			continue
+		}
+		if !trustHost(file, name, targets[name][0], targets[name][1]) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// trustHost scans a host's keys, shows their fingerprints and appends them
+// to known_hosts if the user confirms by typing the host name
+func trustHost(file, name, host, port string) bool {
+	args := []string{"-T", "10"}
+	if port != "" {
+		args = append(args, "-p", port)
+	}
+	keys, err := exec.Command("ssh-keyscan", append(args, host)...).Output()
+	if err != nil || len(strings.TrimSpace(string(keys))) == 0 {
+		fmt.Printf(tr("❌ %s: ssh-keyscan returned no keys\n"), name)
+		return false
+	}
+	fingerprint := exec.Command("ssh-keygen", "-l", "-f", "-")
+	fingerprint.Stdin = strings.NewReader(string(keys))
+	prints, err := fingerprint.Output()
+	if err != nil {
+		fmt.Printf(tr("❌ %s: could not compute fingerprints: %v\n"), name, err)
+		return false
+	}
+
+	fmt.Printf(tr("🔐 %s is not in %s. Its keys:\n"), name, file)
+	for _, line := range strings.Split(strings.TrimSpace(string(prints)), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	fmt.Printf(tr("  Type %s to trust these keys, anything else skips it: "), host)
+	answer, err := stdin.ReadString('\n')
+	if err != nil || strings.TrimSpace(answer) != host {
+		fmt.Printf(tr("  ⏭️  Skipped %s\n"), name)
+		return false
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return false
+	}
+	out, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return false
+	}
+	defer out.Close()
+	if _, err := out.Write(keys); err != nil {
+		fmt.Printf("❌ %s: %v\n", name, err)
+		return false
+	}
+	fmt.Printf(tr("  ✓ Added %s to %s\n"), name, file)
+	return true
+}
@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// catalogs holds the translations of user-facing messages per language. The
+// English format string is the key, so a message without a translation is
+// shown in English, and translations must keep the format verbs in order.
+var catalogs = map[string]map[string]string{
+	"de": messagesDE,
+}
+
+// translations is the catalog of the selected language, nil for English
+var translations map[string]string
+
+// tr returns the format string in the selected language
+func tr(format string) string {
+	if translated, ok := translations[format]; ok {
+		return translated
+	}
+	return format
+}
+
+// setLocale selects the language of messages: locale from the config, or
+// when empty the first of LC_ALL, LC_MESSAGES and LANG that is set.
+// Languages without a catalog fall back to English.
+func setLocale(locale string) {
+	if locale == "" {
+		for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if locale = os.Getenv(name); locale != "" {
+				break
+			}
+		}
+	}
+	translations = catalogs[localeLanguage(locale)]
+}
+
+// localeLanguage reduces a locale such as "de_DE.UTF-8" to its language
+func localeLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ToLower(locale), ".")
+	language, _, _ = strings.Cut(language, "@")
+	language, _, _ = strings.Cut(language, "_")
+	language, _, _ = strings.Cut(language, "-")
+	return language
+}
+
+// validateLocale checks that the configured locale has messages
+func validateLocale(locale string) error {
+	if locale == "" || localeLanguage(locale) == "en" || catalogs[localeLanguage(locale)] != nil {
+		return nil
+	}
+	languages := []string{"en"}
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return fmt.Errorf("locale must be one of %s, got %q", strings.Join(languages, ", "), locale)
+}
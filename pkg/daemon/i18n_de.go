@@ -0,0 +1,606 @@
+package daemon
+
+// messagesDE is the German catalog; help lines keep the column layout of
+// the English ones
+var messagesDE = map[string]string{
+	// Help screen
+	"🚀 Git Air - Automatic Git synchronization service": "🚀 Git Air - Automatische Git-Synchronisation",
+	"\nUSAGE:":                   "\nAUFRUF:",
+	"  git-air [options]":        "  git-air [Optionen]",
+	"  git-air <command> [args]": "  git-air <Befehl> [Argumente]",
+	"\nCOMMANDS:":                "\nBEFEHLE:",
+	"  suggest-ignore <repo>   Propose .gitignore entries for untracked files":       "  suggest-ignore <repo>   .gitignore-Einträge für ungetrackte Dateien",
+	"                          (--ai to ask the configured AI provider too)":         "                          vorschlagen (--ai fragt auch den KI-Anbieter)",
+	"  adopt <dir>             Turn a plain directory into a managed repo":           "  adopt <dir>             Ein einfaches Verzeichnis zum verwalteten Repo",
+	"                          (--remote <url> to add and push to origin)":           "                          machen (--remote <url> legt origin an und pusht)",
+	"  changelog <repo>        Markdown changelog of a period (--since <date>),":     "  changelog <repo>        Markdown-Changelog eines Zeitraums (--since",
+	"                          auto-commits summarized by the AI provider":           "                          <Datum>), Auto-Commits vom KI-Anbieter zusammengefasst",
+	"  report                  Export activity and errors from the state store":      "  report                  Aktivität und Fehler aus dem Zustandsspeicher",
+	"                          (--from, --to, --format csv|json, --events)":          "                          exportieren (--from, --to, --format csv|json, --events)",
+	"  stats [repo...]         Auto vs manual commits, commit size, busiest":         "  stats [repo...]         Auto- und manuelle Commits, Commit-Größe, aktivste",
+	"                          hours and push success rate (--days N)":               "                          Stunden und Push-Erfolgsquote (--days N)",
+	"  activity [repo...]      Commit heatmap and daily line totals from the":        "  activity [repo...]      Commit-Heatmap und Zeilen pro Tag aus dem",
+	"                          state store (--weeks N, --days N, --html <file>)":     "                          Zustandsspeicher (--weeks N, --days N, --html <Datei>)",
+	"  backup [repo...]        Write verified incremental git bundles now and":       "  backup [repo...]        Jetzt geprüfte inkrementelle Git-Bundles schreiben",
+	"                          upload them if backup.s3 is configured":               "                          und hochladen, wenn backup.s3 gesetzt ist",
+	"                          (--dir <path>, default backup.dir from config)":       "                          (--dir <Pfad>, Standard: backup.dir aus der Konfiguration)",
+	"  pull <repo...>          Pull now, also repos in review mode":                  "  pull <repo...>          Jetzt pullen, auch Repos im review-Modus",
+	"  review <repo...>        Commit the snapshots queued in capture queue mode":    "  review <repo...>        Die im capture-queue-Modus gesammelten Snapshots",
+	"                          in batches, with your own messages":                   "                          gebündelt mit eigenen Nachrichten committen",
+	"  restore <repo> --at <time> [path...]":                                         "  restore <repo> --at <Zeit> [Pfad...]",
+	"                          Restore files as they were at a time, from commits":   "                          Dateien so wiederherstellen, wie sie zu einer Zeit",
+	"                          and snapshots (--to <dir> writes them elsewhere)":     "                          waren (--to <dir> schreibt sie woandershin)",
+	"  merge-machines <repo>   Merge the machines/* branches and update the":         "  merge-machines <repo>   Die machines/*-Branches zusammenführen und den",
+	"                          shared branch (machine_branch mode)":                  "                          gemeinsamen Branch aktualisieren (machine_branch)",
+	"  doctor [repo...]        Check that every remote works without a password":     "  doctor [repo...]        Prüfen, dass jedes Remote ohne Passwortabfrage",
+	"                          prompt (SSH agent keys, cached HTTPS credentials)":    "                          funktioniert (SSH-Agent, gespeicherte HTTPS-Zugangsdaten)",
+	"  bench [repo...]         Time discovery, git status and a dry-run cycle":       "  bench [repo...]         Suche, git status und einen Probezyklus pro Repo",
+	"                          per repo to find slow repos (--network)":              "                          messen, um langsame Repos zu finden (--network)",
+	"  known-hosts [repo...]   Add missing SSH host keys of the remotes after":       "  known-hosts [repo...]   Fehlende SSH-Hostschlüssel der Remotes eintragen,",
+	"                          you compared and confirmed their fingerprints":        "                          nachdem du ihre Fingerabdrücke verglichen hast",
+	"  tray [options]          Run with a system tray icon: status, pause,":          "  tray [Optionen]         Mit Symbol in der Taskleiste: Status, Pause,",
+	"                          sync now and recent events (takes the options below)": "                          Jetzt synchronisieren, letzte Ereignisse (Optionen wie unten)",
+	"  metered [on|off|auto]   Show or set metered mode: commit only, pushes":        "  metered [on|off|auto]   Getakteten Modus zeigen oder setzen: nur",
+	"                          wait for an unmetered connection":                     "                          committen, Pushes warten auf ungetaktetes Netz",
+	"\nOPTIONS:": "\nOPTIONEN:",
+	"  -h, --help              Show this help screen":                                     "  -h, --help              Diese Hilfe anzeigen",
+	"  -i, --interval <time>   Check interval in minutes or as a duration":                "  -i, --interval <Zeit>   Prüfintervall in Minuten oder als Dauer",
+	"                          Examples: 0.5, 1, 10, 45s, 2m30s, 4h":                      "                          Beispiele: 0.5, 1, 10, 45s, 2m30s, 4h",
+	"                          Default: 0.5 (30 seconds)":                                 "                          Standard: 0.5 (30 Sekunden)",
+	"  -mr, --monorepo         Force monorepo mode":                                       "  -mr, --monorepo         Monorepo-Modus erzwingen",
+	"                          (auto-detects if not set)":                                 "                          (sonst automatisch erkannt)",
+	"  -c, --config <file>     Config file, JSON or YAML (default: ./git-air.json":        "  -c, --config <Datei>    Konfigurationsdatei, JSON oder YAML (Standard:",
+	"                          or .yaml, then the same in ~/.config/git-air/)":            "                          ./git-air.json oder .yaml, dann in ~/.config/git-air/)",
+	"  --confirm               Ask before each commit: approve, edit":                     "  --confirm               Vor jedem Commit fragen: bestätigen, Nachricht",
+	"                          the message, or skip the repo this cycle":                  "                          bearbeiten oder das Repo diesen Zyklus überspringen",
+	"  --low-priority          Run git with reduced CPU and I/O priority":                 "  --low-priority          Git mit niedriger CPU- und I/O-Priorität ausführen",
+	"                          (also low_priority in the config)":                         "                          (auch low_priority in der Konfiguration)",
+	"  --max-parallel-net <n>  Maximum simultaneous pushes, fetches and":                  "  --max-parallel-net <n>  Höchstzahl gleichzeitiger Pushes, Fetches und",
+	"                          pulls (default 4)":                                         "                          Pulls (Standard: 4)",
+	"  --workers <n>           Repos whose status is checked and whose remotes":           "  --workers <n>           Repos, deren Status gleichzeitig geprüft und deren",
+	"                          are fetched at the same time (default 1)":                  "                          Remotes gleichzeitig geholt werden (Standard: 1)",
+	"🔍 %d of %d repos to check (status by %d workers in %s)\n":                            "🔍 %d von %d Repos zu prüfen (Status von %d Workern in %s)\n",
+	"  📡 Fetched %d remote(s) of %d repos with %d workers in %s (%d failed)\n":            "  📡 %d Remote(s) von %d Repos mit %d Workern in %s geholt (%d fehlgeschlagen)\n",
+	"  --watch                 Sync repos as soon as files change,":                       "  --watch                 Repos synchronisieren, sobald sich Dateien ändern,",
+	"  --dry-run               Show which repos would be committed, with which":           "  --dry-run               Zeigen, welche Repos mit welcher Nachricht committet",
+	"                          message, and pushed where; changes nothing":                "                          und wohin gepusht würden; ändert nichts",
+	"🧪 Dry run: showing what one cycle would do, nothing is changed":                      "🧪 Probelauf: zeigt, was ein Zyklus tun würde, nichts wird geändert",
+	"\n🧪 Dry run: no repository was changed":                                              "\n🧪 Probelauf: kein Repository wurde geändert",
+	"📝 %s%s: Changes found (dry run)\n":                                                   "📝 %s%s: Änderungen gefunden (Probelauf)\n",
+	"⏳ %s: %s still being written, would wait for it to settle\n":                         "⏳ %s: %s wird noch geschrieben, würde warten, bis die Datei fertig ist\n",
+	" (or one written by the AI provider)":                                                " (oder eine vom KI-Anbieter geschriebene)",
+	"  🧪 Would capture %d file(s) (capture: %s)\n":                                        "  🧪 Würde %d Datei(en) erfassen (capture: %s)\n",
+	"  🧪 Would commit %d file(s):\n":                                                      "  🧪 Würde %d Datei(en) committen:\n",
+	"     ... and %d more\n":                                                              "     ... und %d weitere\n",
+	"  🧪 Message: %s\n":                                                                   "  🧪 Nachricht: %s\n",
+	"  🧪 Would push %s to %s (%s)\n":                                                      "  🧪 Würde %s nach %s pushen (%s)\n",
+	"  🧪 Would update the submodules %s from their remotes\n":                             "  🧪 Würde die Submodule %s von ihren Remotes aktualisieren\n",
+	"  --once                  Run one cycle (commit, push, pull) and exit:":              "  --once                  Einen Zyklus (Commit, Push, Pull) ausführen und beenden:",
+	"                          0 in sync, 1 something failed, 2 needs attention":          "                          0 synchron, 1 Fehler, 2 braucht Aufmerksamkeit",
+	"\n⏹️  Stopping after the current repository (press Ctrl-C again to quit at once)":    "\n⏹️  Halte nach dem aktuellen Repository an (erneut Strg-C zum sofortigen Beenden)",
+	"\n⏹️  Quitting, git commands still running are interrupted":                          "\n⏹️  Beende, laufende git-Befehle werden abgebrochen",
+	"\n⏹️  Stopped after %d cycle(s): %d commit(s), %d push(es), %d pull(s), %d failed\n": "\n⏹️  Angehalten nach %d Durchlauf/Durchläufen: %d Commit(s), %d Push(es), %d Pull(s), %d fehlgeschlagen\n",
+	"\n❌ %d operation(s) failed\n":                                                        "\n❌ %d Vorgang/Vorgänge fehlgeschlagen\n",
+	"\n✓ All repositories in sync":                                                        "\n✓ Alle Repositories synchron",
+	"  --daemon                Run in the background; output goes to the log":             "  --daemon                Im Hintergrund laufen; Ausgaben gehen ins Log",
+	"  --pid-file <file>       PID file of --daemon (default: git-air.pid in":             "  --pid-file <Datei>      PID-Datei von --daemon (Standard: git-air.pid in",
+	"                          ~/.local/state/git-air, or pid_file)":                      "                          ~/.local/state/git-air, oder pid_file)",
+	"  --log-file <file>       Log of --daemon (default: git-air.log next to":             "  --log-file <Datei>      Log von --daemon (Standard: git-air.log neben",
+	"                          the PID file's default, or log_file)":                      "                          der Standard-PID-Datei, oder log_file)",
+	"  --api <addr>            Serve a JSON REST API on the address, e.g.":                "  --api <Adresse>         Eine JSON-REST-API auf der Adresse anbieten, z. B.",
+	"                          127.0.0.1:8090: status, trigger, pause and":                "                          127.0.0.1:8090: Status, Auslösen, Pausieren und",
+	"                          recent commits (api.token_env for other hosts)":            "                          letzte Commits (api.token_env für andere Rechner)",
+	"  --force                 Run even if another git-air syncs this directory":          "  --force                 Auch laufen, wenn ein anderes git-air dieses Verzeichnis synchronisiert",
+	"another git-air (pid %d) already syncs %s":                                           "ein anderes git-air (PID %d) synchronisiert %s bereits",
+	"another git-air already syncs %s":                                                    "ein anderes git-air synchronisiert %s bereits",
+	"   Stop it first, or start with --force to run anyway":                               "   Beende es zuerst oder starte mit --force, um trotzdem zu laufen",
+	"  stop                    Stop the git-air started with --daemon":                    "  stop                    Das mit --daemon gestartete git-air beenden",
+	"  status [dir]            Show what the running git-air syncing the":                 "  status [dir]            Zeigen, was das laufende git-air für das",
+	"                          directory is doing, per repo (--json)":                     "                          Verzeichnis tut, je Repo (--json)",
+	"  trigger [file...]       Sync the repos of files just saved in an editor,":          "  trigger [file...]       Die Repos gerade im Editor gespeicherter Dateien",
+	"                          or start a cycle now without files":                        "                          synchronisieren, ohne Dateien sofort einen Durchlauf",
+	"  pause [dir]             Suspend the running git-air syncing the directory,":        "  pause [dir]             Das laufende git-air für das Verzeichnis anhalten,",
+	"                          or only the repo the directory is in":                      "                          oder nur das Repo, in dem das Verzeichnis liegt",
+	"   ⏸️  Paused until git-air resume":                                                  "   ⏸️  Angehalten bis git-air resume",
+	"   💤 Cycle #%d done, next in %s\n":                                                   "   💤 Durchlauf #%d fertig, nächster in %s\n",
+	"   🔄 Check cycle #%d running\n":                                                      "   🔄 Prüfdurchlauf #%d läuft\n",
+	"%s %s ago":                                                                           "%s vor %s",
+	"\n⏸️  %s: paused by git-air pause until git-air resume\n":                            "\n⏸️  %s: angehalten durch git-air pause bis git-air resume\n",
+	"\n▶️  %s: resumed by git-air resume\n":                                               "\n▶️  %s: fortgesetzt durch git-air resume\n",
+	"commit":                   "Commit",
+	"pull":                     "Pull",
+	"push":                     "Push",
+	"⏸️  paused":               "⏸️  angehalten",
+	"⚠️  Control socket: %v\n": "⚠️  Steuer-Socket: %v\n",
+	"❌ REST API: %s is not set, not listening\n":                                                  "❌ REST-API: %s ist nicht gesetzt, lausche nicht\n",
+	"❌ REST API: %s is reachable from other machines, set api.token_env or listen on 127.0.0.1\n": "❌ REST-API: %s ist von anderen Rechnern erreichbar, setze api.token_env oder lausche auf 127.0.0.1\n",
+	"⚔️  %d file(s) with merge conflicts":                                                         "⚔️  %d Datei(en) mit Merge-Konflikten",
+	"❌ REST API stopped: %v\n":                                                                    "❌ REST-API beendet: %v\n",
+	"🔌 REST API: listening on %s\n":                                                               "🔌 REST-API: lausche auf %s\n",
+	"🖥️  Dashboard: %s\n":                                                                         "🖥️  Weboberfläche: %s\n",
+	"✓ in sync":                                                                                   "✓ synchron",
+	"❌ pid %d: no control socket (%v)\n":                                                          "❌ PID %d: kein Steuer-Socket (%v)\n",
+	"📝 %d pending change(s)":                                                                      "📝 %d ausstehende Änderung(en)",
+	"🔄 %s: sync requested (pid %d)\n":                                                             "🔄 %s: Synchronisierung angefordert (PID %d)\n",
+	"🔄 Cycle started in git-air (pid %d, syncing %s)\n":                                           "🔄 Durchlauf in git-air gestartet (PID %d, synchronisiert %s)\n",
+	"🚀 git-air (pid %d) in %s, running for %s\n":                                                  "🚀 git-air (PID %d) in %s, läuft seit %s\n",
+	"⏸️  Paused %s in git-air (pid %d)\n":                                                         "⏸️  %s in git-air angehalten (PID %d)\n",
+	"▶️  Resumed %s in git-air (pid %d)\n":                                                        "▶️  %s in git-air fortgesetzt (PID %d)\n",
+	"  ⏸️  %s: paused, push kept for later\n":                                                     "  ⏸️  %s: pausiert, Push wird aufgehoben\n",
+	"  ⏳ %s: %s, push kept for later\n":                                                           "  ⏳ %s: %s, Push wird aufgehoben\n",
+	"⏸️  %s: paused, skipping\n":                                                                  "⏸️  %s: angehalten, übersprungen\n",
+	"🔄 %s: sync requested with git-air trigger\n":                                                 "🔄 %s: Synchronisierung mit git-air trigger angefordert\n",
+	"  resume [dir]            Let it sync again":                                                 "  resume [dir]            Es wieder synchronisieren lassen",
+	"\n⏸️  Paused by git-air pause: no commits, pushes or pulls until git-air resume":             "\n⏸️  Angehalten durch git-air pause: keine Commits, Pushes oder Pulls bis git-air resume",
+	"\n▶️  Resumed by git-air resume":                                                             "\n▶️  Fortgesetzt durch git-air resume",
+	"❌ pid %d: %v\n":                                                                              "❌ PID %d: %v\n",
+	"⏸️  Paused git-air (pid %d, syncing %s)\n":                                                   "⏸️  git-air angehalten (PID %d, synchronisiert %s)\n",
+	"▶️  Resumed git-air (pid %d, syncing %s)\n":                                                  "▶️  git-air fortgesetzt (PID %d, synchronisiert %s)\n",
+	"❌ No running git-air syncs %s\n":                                                             "❌ Kein laufendes git-air synchronisiert %s\n",
+	"pausing from the command line needs signals, which Windows doesn't have - use Pause in the tray menu": "Anhalten über die Kommandozeile braucht Signale, die Windows nicht hat - Pause im Tray-Menü verwenden",
+	"  restart                 Restart it with the same directory and options":                             "  restart                 Es mit demselben Verzeichnis und denselben Optionen neu starten",
+	"❌ git-air is already running (pid %d, %s)\n":                                                          "❌ git-air läuft bereits (PID %d, %s)\n",
+	"❌ Could not start git-air: %v\n":                                                                      "❌ git-air konnte nicht gestartet werden: %v\n",
+	"❌ git-air exited right after starting, see %s\n":                                                      "❌ git-air hat sich direkt nach dem Start beendet, siehe %s\n",
+	"🚀 git-air is running in the background (pid %d)\n":                                                    "🚀 git-air läuft im Hintergrund (PID %d)\n",
+	"   Log: %s\n":                                                         "   Protokoll: %s\n",
+	"   Stop it with git-air stop":                                         "   Beenden mit git-air stop",
+	"git-air is not running (no live process in %s)":                       "git-air läuft nicht (kein laufender Prozess in %s)",
+	"could not stop pid %d: %v":                                            "PID %d konnte nicht beendet werden: %v",
+	"pid %d did not exit within %s":                                        "PID %d hat sich nicht innerhalb von %s beendet",
+	"✓ Stopped git-air (pid %d)\n":                                         "✓ git-air beendet (PID %d)\n",
+	"❌ No record of a git-air started with --daemon next to %s\n":          "❌ Kein mit --daemon gestartetes git-air neben %s vermerkt\n",
+	"                          checking every interval only as a fallback": "                          das Intervall dient nur noch als Rückfallebene",
+	"\nEXAMPLES:": "\nBEISPIELE:",
+	"  git-air                 # Run with default 30 second interval":   "  git-air                 # Mit dem Standardintervall von 30 Sekunden",
+	"  git-air -i 1            # Check every 1 minute":                  "  git-air -i 1            # Jede Minute prüfen",
+	"  git-air -i 5 -mr        # Check every 5 minutes, force monorepo": "  git-air -i 5 -mr        # Alle 5 Minuten prüfen, Monorepo erzwingen",
+	"  git-air --interval 10   # Check every 10 minutes":                "  git-air --interval 10   # Alle 10 Minuten prüfen",
+	"  git-air -i 4h           # Check every 4 hours":                   "  git-air -i 4h           # Alle 4 Stunden prüfen",
+	"  git-air --confirm       # Review every commit before it is made": "  git-air --confirm       # Jeden Commit vorher prüfen",
+	"\nDESCRIPTION:": "\nBESCHREIBUNG:",
+	"  Automatically discovers and synchronizes all Git repositories": "  Findet und synchronisiert automatisch alle Git-Repositories",
+	"  in the current directory and subdirectories.":                  "  im aktuellen Verzeichnis und seinen Unterverzeichnissen.",
+	"\n  Features:": "\n  Funktionen:",
+	"  • Auto-commits changes with timestamp":           "  • Committet Änderungen automatisch mit Zeitstempel",
+	"  • Pushes to ALL configured remotes":              "  • Pusht zu ALLEN eingerichteten Remotes",
+	"  • Pulls updates for inter-project communication": "  • Pullt Updates für die Kommunikation zwischen Projekten",
+	"  • Handles monorepos with submodules":             "  • Unterstützt Monorepos mit Submodulen",
+
+	// Daemon status and notifications
+	"🚀 Git Air - Auto sync all Git repos":                                          "🚀 Git Air - Alle Git-Repos automatisch synchronisieren",
+	"📡 Inter-project communication via Git synchronization":                        "📡 Kommunikation zwischen Projekten über Git-Synchronisation",
+	"📚 Supports monorepos and multi-repos":                                         "📚 Unterstützt Monorepos und mehrere Repos",
+	"⏱️  Check interval: %s\n":                                                     "⏱️  Prüfintervall: %s\n",
+	"⚠️  Note: %s\n":                                                               "⚠️  Hinweis: %s\n",
+	"🔧 Monorepo mode: FORCED":                                                      "🔧 Monorepo-Modus: ERZWUNGEN",
+	"🔧 Monorepo mode: AUTO-DETECT":                                                 "🔧 Monorepo-Modus: AUTOMATISCH",
+	"⚙️  Config: %s\n":                                                             "⚙️  Konfiguration: %s\n",
+	"🙋 Confirm mode: ON (each commit needs approval)":                              "🙋 Bestätigungsmodus: AN (jeder Commit muss bestätigt werden)",
+	"👀 Watch mode: ON (syncing as files change, full check every %s)\n":            "👀 Überwachungsmodus: AN (Sync bei Dateiänderungen, volle Prüfung alle %s)\n",
+	"⚠️  Watch mode: %v, checking every interval instead\n":                        "⚠️  Überwachungsmodus: %v, stattdessen Prüfung in jedem Intervall\n",
+	"  ⚠️  %s: not watched, checking every interval: %v\n":                         "  ⚠️  %s: nicht überwacht, Prüfung in jedem Intervall: %v\n",
+	"  👀 %s: watching %d directories\n":                                            "  👀 %s: %d Verzeichnisse überwacht\n",
+	"❌ Watch mode stopped: %v, checking every interval instead\n":                  "❌ Überwachungsmodus beendet: %v, stattdessen Prüfung in jedem Intervall\n",
+	"  ⚠️  Watch mode: %s not watched: %v\n":                                       "  ⚠️  Überwachungsmodus: %s nicht überwacht: %v\n",
+	"  ⚠️  Watch mode: %v\n":                                                       "  ⚠️  Überwachungsmodus: %v\n",
+	"👀 %s: files changed, syncing now\n":                                           "👀 %s: Dateien geändert, synchronisiere jetzt\n",
+	"⚠️  Low priority: could not be set: %v\n":                                     "⚠️  Niedrige Priorität konnte nicht gesetzt werden: %v\n",
+	"🐢 Low priority: ON (git runs niced)":                                          "🐢 Niedrige Priorität: AN (git läuft mit nice)",
+	"⚠️  No Git repositories found in current directory":                           "⚠️  Keine Git-Repositories im aktuellen Verzeichnis gefunden",
+	"💡 Make sure you're in a directory containing Git repositories":                "💡 Starte Git Air in einem Verzeichnis, das Git-Repositories enthält",
+	"Found %d Git repositories\n":                                                  "%d Git-Repositories gefunden\n",
+	"🎲 Starting in %.0f seconds (jitter)\n\n":                                      "🎲 Start in %.0f Sekunden (Jitter)\n\n",
+	"🔄 Check cycle #%d\n":                                                          "🔄 Prüfzyklus #%d\n",
+	"  ✓ No changes detected":                                                      "  ✓ Keine Änderungen gefunden",
+	"\n📡 Checking for inter-project updates...":                                    "\n📡 Suche nach Updates anderer Projekte...",
+	"\n💤 Sleeping for %s...\n\n":                                                   "\n💤 Pause für %s...\n\n",
+	"  📁 New repository: %s\n":                                                     "  📁 Neues Repository: %s\n",
+	"  📁 Repository gone: %s\n":                                                    "  📁 Repository entfernt: %s\n",
+	"  ❌ Error changing to %s: %v\n":                                               "  ❌ Fehler beim Wechsel nach %s: %v\n",
+	"⏳ %s: %s, deferring to next cycle\n":                                          "⏳ %s: %s, auf den nächsten Zyklus verschoben\n",
+	"  ❌ Skipping %s - submodule sync failed\n":                                    "  ❌ %s übersprungen - Submodul-Synchronisation fehlgeschlagen\n",
+	"⚡ %s: %s changed, syncing immediately\n":                                      "⚡ %s: %s geändert, wird sofort synchronisiert\n",
+	"⏳ %s: %s still being written, deferring to next cycle\n":                      "⏳ %s: %s wird noch geschrieben, auf den nächsten Zyklus verschoben\n",
+	"📦 %s: %d new untracked files in %s - paused, %s (or raise untracked_limit)\n": "📦 %s: %d neue ungetrackte Dateien in %s - pausiert, %s (oder untracked_limit erhöhen)\n",
+	"%s: paused, %d new untracked files in %s - %s":                                "%s: pausiert, %d neue ungetrackte Dateien in %s - %s",
+	"📝 %s%s: Auto committing changes...\n":                                         "📝 %s%s: Änderungen werden automatisch committet...\n",
+	"  🙈 Leaving %s unstaged (never_commit)\n":                                     "  🙈 %s bleibt ungestaged (never_commit)\n",
+	"  ⚠️  Skipping %s - no git identity (user.name/user.email) configured\n":      "  ⚠️  %s übersprungen - keine Git-Identität (user.name/user.email) eingerichtet\n",
+	"%s: commits skipped, no git identity. Run git config user.name/user.email or set identity in the git-air config": "%s: Commits übersprungen, keine Git-Identität. Setze sie mit git config user.name/user.email oder mit identity in der git-air-Konfiguration",
+	"  🔒 Skipping %s - %s is locked, unlock it to commit encrypted files\n":                                           "  🔒 %s übersprungen - %s ist gesperrt, entsperre es, um verschlüsselte Dateien zu committen\n",
+	"%s: commits skipped, %s is locked":                                                                       "%s: Commits übersprungen, %s ist gesperrt",
+	"  ⏳ Skipping %s - %s, deferring to next cycle\n":                                                         "  ⏳ %s übersprungen - %s, auf den nächsten Zyklus verschoben\n",
+	"  🔏 Skipping %s - commit signing unavailable (%s), deferring to next cycle\n":                            "  🔏 %s übersprungen - Signieren nicht möglich (%s), auf den nächsten Zyklus verschoben\n",
+	"%s: commits deferred, signing unavailable: %s - unlock the agent or set signing_unavailable to unsigned": "%s: Commits verschoben, Signieren nicht möglich: %s - entsperre den Agent oder setze signing_unavailable auf unsigned",
+	"  🔏 Commit signing unavailable (%s), committing unsigned\n":                                              "  🔏 Signieren nicht möglich (%s), Commit ohne Signatur\n",
+	"%s: committing unsigned, signing unavailable: %s":                                                        "%s: Commits ohne Signatur, Signieren nicht möglich: %s",
+	"  🚦 Skipping %s - policy: %s, deferring to next cycle\n":                                                 "  🚦 %s übersprungen - Richtlinie: %s, auf den nächsten Zyklus verschoben\n",
+	"  ❌ Error staging changes in %s\n":                                                                       "  ❌ Fehler beim Stagen der Änderungen in %s\n",
+	"  🔓 Skipping %s - %s would be committed unencrypted\n":                                                   "  🔓 %s übersprungen - %s würde unverschlüsselt committet\n",
+	"%s: commits skipped, %s would be committed unencrypted":                                                  "%s: Commits übersprungen, %s würde unverschlüsselt committet",
+	"  ⚠️  %s: commitlint rules ignored: %v\n":                                                                "  ⚠️  %s: commitlint-Regeln ignoriert: %v\n",
+	"  ⏭️  Skipped %s\n":                                                                                      "  ⏭️  %s übersprungen\n",
+	"  ⚠️  Commit failed in %s: %s\n":                                                                         "  ⚠️  Commit in %s fehlgeschlagen: %s\n",
+	"  👤 Set %s to %s\n":                                                                                      "  👤 %s auf %s gesetzt\n",
+	"  ⚠️  Could not set %s\n":                                                                                "  ⚠️  %s konnte nicht gesetzt werden\n",
+	"  ⚠️  --confirm needs an interactive terminal, skipping %s\n":                                            "  ⚠️  --confirm braucht ein interaktives Terminal, %s übersprungen\n",
+	"  💬 Message: %s\n":                                                                                       "  💬 Nachricht: %s\n",
+	"  🙋 Commit %s? [y]es / [e]dit message / [s]kip: ":                                                        "  🙋 %s committen? [y] ja / [e] Nachricht bearbeiten / [s] überspringen: ",
+	"  ✏️  New message: ":                                                                                     "  ✏️  Neue Nachricht: ",
+	"  ⚠️  Please answer y, e or s":                                                                           "  ⚠️  Bitte mit y, e oder s antworten",
+	"  ⏳ %s: %s, not pulling\n":                                                                               "  ⏳ %s: %s, kein Pull\n",
+	"%s: %d new file(s) not committed: %s - git add them or add them to .gitignore":                           "%s: %d neue Datei(en) nicht committet: %s - mit git add hinzufügen oder in .gitignore eintragen",
+	"  ⚠️  No remotes configured, skipping push":                                                              "  ⚠️  Keine Remotes eingerichtet, kein Push",
+	" ❌ failed\n": " ❌ fehlgeschlagen\n",
+	"%s: diverged from %s and could not be rescued: %v":   "%s: von %s auseinandergelaufen und nicht zu retten: %v",
+	"  📦 Syncing submodules...":                           "  📦 Submodule werden synchronisiert...",
+	"  ⚠️  failed to stage submodule changes\n":           "  ⚠️  Submodul-Änderungen konnten nicht gestaged werden\n",
+	"\n🚨 %d issue(s) need attention:\n":                   "\n🚨 %d Problem(e) brauchen Aufmerksamkeit:\n",
+	"current directory":                                   "aktuelles Verzeichnis",
+	"%s (and %d more)":                                    "%s (und %d weitere)",
+	"🔑 Checking remote credentials...":                    "🔑 Zugangsdaten der Remotes werden geprüft...",
+	"  ✓ All remotes are reachable without prompts":       "  ✓ Alle Remotes sind ohne Passwortabfrage erreichbar",
+	"  ❌ %s: %s (%s) would block: %s\n":                   "  ❌ %s: %s (%s) würde hängen bleiben: %s\n",
+	"%s: %s (%s) would block: %s":                         "%s: %s (%s) würde hängen bleiben: %s",
+	"  ❌ %s: untracking the plaintext of %s failed: %s\n": "  ❌ %s: Klartext von %s aus dem Index entfernen fehlgeschlagen: %s\n",
+	"%s: %s to be encrypted still tracked as plaintext, untracking failed - run git rm --cached on them":                "%s: %s, die verschlüsselt werden sollen, noch als Klartext versioniert, Entfernen fehlgeschlagen - führe git rm --cached dafür aus",
+	"  🔒 %s: untracked the plaintext of %s, only the .age file is committed from now on\n":                              "  🔒 %s: Klartext von %s aus dem Index entfernt, ab jetzt wird nur die .age-Datei committet\n",
+	"%s: %s had been committed as plaintext and is now untracked - it is still in the history, so rotate those secrets": "%s: %s wurde als Klartext committet und ist jetzt nicht mehr versioniert - es steht noch in der Historie, also tausche diese Geheimnisse aus",
+	"%s: push held back until %s has pushed its commits":                                                                "%s: Push zurückgehalten, bis %s seine Commits gepusht hat",
+
+	// Commands, background features and alerts
+	"USAGE:": "AUFRUF:",
+	"  git-air activity [--weeks N] [--days N] [--html <file>] [repo...]":                                     "  git-air activity [--weeks N] [--days N] [--html <Datei>] [repo...]",
+	"\nShows a commit heatmap per repository (one column per week, Monday on top)":                            "\nZeigt eine Commit-Heatmap pro Repository (eine Spalte pro Woche, Montag oben)",
+	"and the lines added and deleted per day, from the state store. Without":                                  "und die pro Tag hinzugefügten und gelöschten Zeilen aus dem Zustandsspeicher.",
+	"repos, every repository git-air committed to.":                                                           "Ohne Repos jedes Repository, in das git-air committet hat.",
+	"❌ Error reading history: %v\n":                                                                           "❌ Fehler beim Lesen des Verlaufs: %v\n",
+	"No commits recorded in this period.":                                                                     "Keine Commits in diesem Zeitraum erfasst.",
+	"❌ Error writing %s: %v\n":                                                                                "❌ Fehler beim Schreiben von %s: %v\n",
+	"✓ Wrote %s\n":                                                                                            "✓ %s geschrieben\n",
+	"📈 %s: %d commits since %s\n":                                                                             "📈 %s: %d Commits seit %s\n",
+	"     %s less … more (busiest day: %d commits)\n":                                                         "     %s weniger … mehr (aktivster Tag: %d Commits)\n",
+	"  %s  %4d commits  %+7d  %7s\n":                                                                          "  %s  %4d Commits  %+7d  %7s\n",
+	"  git-air adopt [--remote <url>] [-c <file>] <dir>":                                                      "  git-air adopt [--remote <url>] [-c <Datei>] <dir>",
+	"\nTurns a plain directory into a Git repository with an initial":                                         "\nMacht aus einem einfachen Verzeichnis ein Git-Repository mit einem ersten",
+	"commit, so git-air manages it from then on.":                                                             "Commit, damit git-air es von da an verwaltet.",
+	"  ❌ Cannot adopt %s: not a directory\n":                                                                  "  ❌ %s kann nicht übernommen werden: kein Verzeichnis\n",
+	"  ✓ %s is already a Git repository\n":                                                                    "  ✓ %s ist bereits ein Git-Repository\n",
+	"🌱 Adopting %s...\n":                                                                                      "🌱 Übernehme %s...\n",
+	"  ❌ git init failed: %s\n":                                                                               "  ❌ git init fehlgeschlagen: %s\n",
+	"  ⚠️  No git identity configured, initial commit skipped\n":                                              "  ⚠️  Keine Git-Identität eingerichtet, erster Commit übersprungen\n",
+	"  ❌ Error staging files in %s\n":                                                                         "  ❌ Fehler beim Stagen der Dateien in %s\n",
+	"  ❌ Initial commit failed: %s\n":                                                                         "  ❌ Erster Commit fehlgeschlagen: %s\n",
+	"  ✓ Initial commit created\n":                                                                            "  ✓ Erster Commit erstellt\n",
+	"  ❌ Creating bare remote %s failed: %s\n":                                                                "  ❌ Anlegen des Bare-Remotes %s fehlgeschlagen: %s\n",
+	"  📦 Created bare remote %s\n":                                                                            "  📦 Bare-Remote %s angelegt\n",
+	"  ❌ Adding remote failed: %s\n":                                                                          "  ❌ Hinzufügen des Remotes fehlgeschlagen: %s\n",
+	"  ⚠️  %s: AI commit message unavailable: %v\n":                                                           "  ⚠️  %s: KI-Commit-Nachricht nicht verfügbar: %v\n",
+	"  ⚠️  %s: AI commit message breaks commitlint rules (%s)\n":                                              "  ⚠️  %s: KI-Commit-Nachricht verletzt die commitlint-Regeln (%s)\n",
+	"  ❌ Backup %s: %v\n":                                                                                     "  ❌ Backup %s: %v\n",
+	"  ❌ Backup %s: %s\n":                                                                                     "  ❌ Backup %s: %s\n",
+	"  ❌ Backup %s: bundle verification failed, removed %s\n":                                                 "  ❌ Backup %s: Prüfung des Bundles fehlgeschlagen, %s entfernt\n",
+	"%s: bundle backup failed verification":                                                                   "%s: Bundle-Backup hat die Prüfung nicht bestanden",
+	"  ⚠️  Backup %s: %v\n":                                                                                   "  ⚠️  Backup %s: %v\n",
+	"  💾 Backed up %s to %s\n":                                                                                "  💾 %s nach %s gesichert\n",
+	"  ❌ Backup upload %s: %v\n":                                                                              "  ❌ Backup-Upload %s: %v\n",
+	"%s: bundle upload failed: %v":                                                                            "%s: Hochladen des Bundles fehlgeschlagen: %v",
+	"  ☁️  Uploaded %s/%s to s3://%s\n":                                                                       "  ☁️  %s/%s nach s3://%s hochgeladen\n",
+	"  ⚠️  Backup upload %s: %v\n":                                                                            "  ⚠️  Backup-Upload %s: %v\n",
+	"  ⚠️  Backup retention %s: %v\n":                                                                         "  ⚠️  Backup-Aufbewahrung %s: %v\n",
+	"  git-air backup [--dir <path>] [repo...]":                                                               "  git-air backup [--dir <Pfad>] [repo...]",
+	"\nWrites an incremental git bundle of each repo (default: all below":                                     "\nSchreibt ein inkrementelles Git-Bundle jedes Repos (Standard: alle unter",
+	"the current directory), verifies it and uploads it if backup.s3 is set.":                                 "dem aktuellen Verzeichnis), prüft es und lädt es hoch, wenn backup.s3 gesetzt ist.",
+	"❌ Error: no backup target, set backup.dir or backup.s3 in the config or use --dir":                       "❌ Fehler: kein Backup-Ziel, backup.dir oder backup.s3 in der Konfiguration setzen oder --dir angeben",
+	"❌ Error finding repositories: %v\n":                                                                      "❌ Fehler beim Suchen der Repositories: %v\n",
+	"  git-air bench [-i <time>] [--network] [repo...]":                                                       "  git-air bench [-i <Zeit>] [--network] [repo...]",
+	"\nTimes repository discovery, git status per repo (first and warm run) and":                              "\nMisst die Suche nach Repositories, git status pro Repo (erster und warmer Lauf)",
+	"the decisions of a cycle, without committing, pushing or pulling anything,":                              "und die Entscheidungen eines Zyklus, ohne etwas zu committen, zu pushen oder zu",
+	"and prints a per-repo breakdown, slowest first. Without repos, all repos":                                "pullen, und zeigt eine Aufschlüsselung pro Repo, langsamste zuerst. Ohne Repos",
+	"below the current directory.":                                                                            "alle Repos unter dem aktuellen Verzeichnis.",
+	"❌ Error: %v\n":                                                                                           "❌ Fehler: %v\n",
+	"🔍 Discovery: %d repositories, cached scan %s, full walk %s\n":                                            "🔍 Suche: %d Repositories, zwischengespeicherter Scan %s, vollständige Suche %s\n",
+	"No repositories found.":                                                                                  "Keine Repositories gefunden.",
+	"\n⏱️  Estimated cycle: %s for %d repositories (interval %s)\n":                                           "\n⏱️  Geschätzter Zyklus: %s für %d Repositories (Intervall %s)\n",
+	"  Slowest: %s with %s (%d%% of the cycle)\n":                                                             "  Am langsamsten: %s mit %s (%d%% des Zyklus)\n",
+	"  ⚠️  Cycles take more than half the interval; check the slowest repos, move":                            "  ⚠️  Zyklen dauern länger als das halbe Intervall; prüfe die langsamsten Repos,",
+	"     them off network file systems or set watchman, or raise the interval":                               "     verschiebe sie von Netzwerk-Dateisystemen, setze watchman oder erhöhe das Intervall",
+	"\n📁 %s (%s, %d tracked files)\n":                                                                         "\n📁 %s (%s, %d getrackte Dateien)\n",
+	"  status:   %s first run, %s warm\n":                                                                     "  Status:   %s erster Lauf, %s warm\n",
+	"  dry run:  %s, %s\n":                                                                                    "  Probelauf: %s, %s\n",
+	"  ⚠️  %s: could not delete %s on %s: %s\n":                                                               "  ⚠️  %s: %s auf %s konnte nicht gelöscht werden: %s\n",
+	"  🧹 %s: deleted stale branch(es) %s\n":                                                                   "  🧹 %s: veraltete(n) Branch(es) %s gelöscht\n",
+	"  git-air changelog [--since <date>] [--until <date>] [--no-ai] <repo>":                                  "  git-air changelog [--since <Datum>] [--until <Datum>] [--no-ai] <repo>",
+	"\nPrints a Markdown changelog of the period, one section per day.":                                       "\nGibt einen Markdown-Changelog des Zeitraums aus, ein Abschnitt pro Tag.",
+	"Runs of auto-commits are summarized (by the AI provider if configured).":                                 "Folgen von Auto-Commits werden zusammengefasst (vom KI-Anbieter, falls eingerichtet).",
+	"❌ Error changing to %s: %v\n":                                                                            "❌ Fehler beim Wechseln nach %s: %v\n",
+	"# Changelog: %s (since %s)\n":                                                                            "# Changelog: %s (seit %s)\n",
+	"\nNo commits in this period.":                                                                            "\nKeine Commits in diesem Zeitraum.",
+	"⚠️  AI summary unavailable: %v\n":                                                                        "⚠️  KI-Zusammenfassung nicht verfügbar: %v\n",
+	"  ⚠️  %s: CI status of %s/%s unavailable (%v), pulling anyway\n":                                         "  ⚠️  %s: CI-Status von %s/%s nicht verfügbar (%v), pulle trotzdem\n",
+	"  🔴 %s: CI failed for %s/%s at %s, not pulling\n":                                                        "  🔴 %s: CI für %s/%s bei %s fehlgeschlagen, kein Pull\n",
+	"%s: pull from %s delayed, CI failed for %s":                                                              "%s: Pull von %s verschoben, CI für %s fehlgeschlagen",
+	"  🟡 %s: CI still running for %s/%s, pulling later\n":                                                     "  🟡 %s: CI für %s/%s läuft noch, Pull später\n",
+	"  ☁️  %s is inside %s - syncing anyway (allow_cloud_sync), pause %s while git-air commits if you can\n":  "  ☁️  %s liegt in %s - wird trotzdem synchronisiert (allow_cloud_sync), pausiere %s wenn möglich, während git-air committet\n",
+	"  ☁️  Skipping %s - it is inside %s, and two sync tools writing .git corrupt repositories\n":             "  ☁️  Überspringe %s - es liegt in %s, und zwei Sync-Werkzeuge, die .git schreiben, beschädigen Repositories\n",
+	"%s: skipped, the repository is inside %s. Move it out or set allow_cloud_sync for it":                    "%s: übersprungen, das Repository liegt in %s. Verschiebe es oder setze allow_cloud_sync dafür",
+	"  ⚠️  %s: could not merge %s with %s: %s\n":                                                              "  ⚠️  %s: %s konnte nicht mit %s zusammengeführt werden: %s\n",
+	"  🤝 %s: merged concurrent auto-commits from %s (%s)\n":                                                   "  🤝 %s: gleichzeitige Auto-Commits von %s zusammengeführt (%s)\n",
+	"  git-air status [--json] [dir]":                                                                         "  git-air status [--json] [dir]",
+	"\nShows the running git-air that syncs the directory (default: the current":                              "\nZeigt das laufende git-air, das das Verzeichnis synchronisiert (Standard: das",
+	"one): its cycle, and per repository whether it is paused, has pending":                                   "aktuelle): seinen Zyklus und pro Repository, ob es pausiert ist, ausstehende",
+	"changes or problems, and when it was last committed, pushed and pulled.":                                 "Änderungen oder Probleme hat und wann zuletzt committet, gepusht und gepullt wurde.",
+	"  git-air doctor [repo...]":                                                                              "  git-air doctor [repo...]",
+	"\nChecks that every remote can be fetched without a password prompt: SSH":                                "\nPrüft, dass jedes Remote ohne Passwortabfrage geholt werden kann: SSH-Schlüssel",
+	"keys in the agent, HTTPS credentials in a credential helper. Without repos,":                             "im Agenten, HTTPS-Zugangsdaten in einem Credential-Helper. Ohne Repos alle",
+	"all repos below the current directory. Exits with 1 if a remote would block.":                            "Repos unter dem aktuellen Verzeichnis. Endet mit 1, wenn ein Remote blockieren würde.",
+	"❌ %s: %s (%s) would block: %s\n":                                                                         "❌ %s: %s (%s) würde blockieren: %s\n",
+	"  🔓 Not pushing %s: %s committed unencrypted\n":                                                          "  🔓 %s wird nicht gepusht: %s unverschlüsselt committet\n",
+	"%s: push blocked, %s committed unencrypted - unlock the repo and amend the commit":                       "%s: Push blockiert, %s unverschlüsselt committet - entsperre das Repo und ändere den Commit mit amend",
+	"  git-air stop [--pid-file <file>]":                                                                      "  git-air stop [--pid-file <Datei>]",
+	"\nStops the git-air started with --daemon, using its PID file (default:":                                 "\nBeendet das mit --daemon gestartete git-air über seine PID-Datei (Standard:",
+	"pid_file from the config, else git-air.pid in the state directory).":                                     "pid_file aus der Konfiguration, sonst git-air.pid im Zustandsverzeichnis).",
+	"  git-air restart [--pid-file <file>]":                                                                   "  git-air restart [--pid-file <Datei>]",
+	"\nStops the git-air started with --daemon and starts it again in the same":                               "\nBeendet das mit --daemon gestartete git-air und startet es im selben",
+	"directory with the same options, e.g. after changing the config.":                                        "Verzeichnis mit denselben Optionen neu, z. B. nach einer Änderung der Konfiguration.",
+	"🗑️  %s: holding %d deletion(s) for up to %s: %s\n":                                                       "🗑️  %s: halte %d Löschung(en) bis zu %s zurück: %s\n",
+	"🗑️  %s: %d deletion(s) would wait for confirmation: %s\n":                                                "🗑️  %s: %d Löschung(en) würden auf Bestätigung warten: %s\n",
+	"%s: %d deletion(s) waiting for confirmation: %s - commit them yourself or restore them with git restore": "%s: %d Löschung(en) warten auf Bestätigung: %s - committe sie selbst oder stelle sie mit git restore wieder her",
+	"  🙋 Commit %d deletion(s) in %s? [y]es / [n]o: ":                                                         "  🙋 %d Löschung(en) in %s committen? [y] ja / [n] nein: ",
+	"  ⚠️  depends_on forms a cycle between %s, syncing them in priority order\n":                             "  ⚠️  depends_on bildet einen Zyklus zwischen %s, synchronisiere sie nach Priorität\n",
+	"  ⚠️  Digest: reading history failed: %v\n":                                                              "  ⚠️  Zusammenfassung: Lesen des Verlaufs fehlgeschlagen: %v\n",
+	"\n📰 Daily digest %s:\n%s\n":                                                                              "\n📰 Tageszusammenfassung %s:\n%s\n",
+	"Git Air daily digest ":                                                                                   "Git Air Tageszusammenfassung ",
+	"%d commits in %d repos (+%d/-%d lines), %d pushes, %d pulls, %d failures\n":                              "%d Commits in %d Repos (+%d/-%d Zeilen), %d Pushes, %d Pulls, %d Fehler\n",
+	"• %s: %d commits (+%d/-%d), %d pushes, %d pulls":                                                         "• %s: %d Commits (+%d/-%d), %d Pushes, %d Pulls",
+	", %d failures":                                                ", %d Fehler",
+	"  ❌ Encrypting %s in %s failed: %s\n":                         "  ❌ Verschlüsseln von %s in %s fehlgeschlagen: %s\n",
+	"%s: encrypting %s failed":                                     "%s: Verschlüsseln von %s fehlgeschlagen",
+	"  ❌ Decrypting %s in %s failed: %v\n":                         "  ❌ Entschlüsseln von %s in %s fehlgeschlagen: %v\n",
+	"%s: decrypting %s failed: %v":                                 "%s: Entschlüsseln von %s fehlgeschlagen: %v",
+	"  ❌ Writing %s in %s failed: %v\n":                            "  ❌ Schreiben von %s in %s fehlgeschlagen: %v\n",
+	"  🔑 Decrypted %s in %s\n":                                     "  🔑 %s in %s entschlüsselt\n",
+	"  ⚠️  %s: %s changed locally and upstream, resolve by hand\n": "  ⚠️  %s: %s lokal und upstream geändert, bitte von Hand auflösen\n",
+	"%s: %s changed locally and upstream - merge into the plaintext and delete the .age file to keep it, or delete the plaintext to take upstream": "%s: %s lokal und upstream geändert - in den Klartext übernehmen und die .age-Datei löschen, um ihn zu behalten, oder den Klartext löschen, um upstream zu übernehmen",
+	"  ✓ Committed changes in %s\n":                                                                           "  ✓ Änderungen in %s committet\n",
+	"%s: .git/index.lock is %s old - delete it if no git command is running":                                  "%s: .git/index.lock ist %s alt - lösche sie, wenn kein git-Befehl läuft",
+	"  🪝 Running %s...":                                                                                       "  🪝 Führe %s aus...",
+	"%s: after_pull command %q failed: %s":                                                                    "%s: after_pull-Befehl %q fehlgeschlagen: %s",
+	"  🔐 %s: SSH host key check failed for %s:\n":                                                             "  🔐 %s: SSH-Hostschlüsselprüfung für %s fehlgeschlagen:\n",
+	"%s: SSH host key check failed for %s (%s):\n%s":                                                          "%s: SSH-Hostschlüsselprüfung für %s (%s) fehlgeschlagen:\n%s",
+	"  git-air known-hosts [repo...]":                                                                         "  git-air known-hosts [repo...]",
+	"\nFetches the host keys of SSH remotes missing from ~/.ssh/known_hosts with":                             "\nHolt die Hostschlüssel von SSH-Remotes, die in ~/.ssh/known_hosts fehlen, mit",
+	"ssh-keyscan and shows their fingerprints. A key is only added after you type":                            "ssh-keyscan und zeigt ihre Fingerabdrücke. Ein Schlüssel wird erst eingetragen,",
+	"the host name, so compare the fingerprints with the ones your server or":                                 "nachdem du den Hostnamen eingegeben hast, vergleiche die Fingerabdrücke also",
+	"forge publishes first. Changed keys are never replaced.":                                                 "vorher mit denen, die dein Server oder deine Forge veröffentlicht. Geänderte Schlüssel werden nie ersetzt.",
+	"❌ Error: known-hosts needs an interactive terminal":                                                      "❌ Fehler: known-hosts braucht ein interaktives Terminal",
+	"❌ Error: no home directory for ~/.ssh/known_hosts":                                                       "❌ Fehler: kein Home-Verzeichnis für ~/.ssh/known_hosts",
+	"✓ %s is already known\n":                                                                                 "✓ %s ist bereits bekannt\n",
+	"❌ %s: ssh-keyscan returned no keys\n":                                                                    "❌ %s: ssh-keyscan hat keine Schlüssel geliefert\n",
+	"❌ %s: could not compute fingerprints: %v\n":                                                              "❌ %s: Fingerabdrücke konnten nicht berechnet werden: %v\n",
+	"🔐 %s is not in %s. Its keys:\n":                                                                          "🔐 %s ist nicht in %s. Seine Schlüssel:\n",
+	"  Type %s to trust these keys, anything else skips it: ":                                                 "  Gib %s ein, um diesen Schlüsseln zu vertrauen, alles andere überspringt ihn: ",
+	"  ✓ Added %s to %s\n":                                                                                    "  ✓ %s zu %s hinzugefügt\n",
+	"  git-air suggest-ignore [--ai] [-c <file>] <repo>":                                                      "  git-air suggest-ignore [--ai] [-c <Datei>] <repo>",
+	"\nAnalyzes untracked files and proposes .gitignore entries,":                                             "\nAnalysiert ungetrackte Dateien und schlägt .gitignore-Einträge vor,",
+	"which are appended after one confirmation.":                                                              "die nach einer Bestätigung angehängt werden.",
+	"❌ Error: %s is not a Git repository\n":                                                                   "❌ Fehler: %s ist kein Git-Repository\n",
+	"✓ No untracked files, nothing to suggest":                                                                "✓ Keine ungetrackten Dateien, nichts vorzuschlagen",
+	"🔍 Analyzing %d untracked files...\n":                                                                     "🔍 Analysiere %d ungetrackte Dateien...\n",
+	"  ⚠️  AI suggestions unavailable: %v\n":                                                                  "  ⚠️  KI-Vorschläge nicht verfügbar: %v\n",
+	"✓ No .gitignore entries to suggest":                                                                      "✓ Keine .gitignore-Einträge vorzuschlagen",
+	"\n💡 Suggested .gitignore entries:":                                                                       "\n💡 Vorgeschlagene .gitignore-Einträge:",
+	"\nAppend these %d entries to .gitignore? [y/N]: ":                                                        "\nDiese %d Einträge an .gitignore anhängen? [y/N]: ",
+	"⏭️  Nothing changed":                                                                                     "⏭️  Nichts geändert",
+	"❌ Error updating .gitignore: %v\n":                                                                       "❌ Fehler beim Aktualisieren der .gitignore: %v\n",
+	"✓ .gitignore updated":                                                                                    "✓ .gitignore aktualisiert",
+	"  📬 %d incoming commit(s) from %s:\n":                                                                    "  📬 %d eingehende(r) Commit(s) von %s:\n",
+	"Git Air: %s updated from %s":                                                                             "Git Air: %s von %s aktualisiert",
+	"  👀 %s/%s is %d commit(s) ahead - run git-air pull %s to merge:\n":                                       "  👀 %s/%s ist %d Commit(s) voraus - git-air pull %s zum Zusammenführen ausführen:\n",
+	"%s: %s/%s is %d commit(s) ahead, waiting for git-air pull (latest: %s)":                                  "%s: %s/%s ist %d Commit(s) voraus, wartet auf git-air pull (neuester: %s)",
+	"  git-air pull <repo...>":                                                                                "  git-air pull <repo...>",
+	"\nFetches and merges from every remote now. This is how incoming changes":                                "\nHolt jetzt von jedem Remote und führt zusammen. So werden eingehende Änderungen",
+	"are accepted in repos with \"pull\": \"review\"; CI gates are skipped too.":                              "in Repos mit \"pull\": \"review\" übernommen; CI-Prüfungen werden ebenfalls übersprungen.",
+	"❌ Error: %s: %s, try again later\n":                                                                      "❌ Fehler: %s: %s, später erneut versuchen\n",
+	"  ⚠️  Skipping %s - already managed by git-air (pid %d, started in %s)\n":                                "  ⚠️  Überspringe %s - wird bereits von git-air verwaltet (PID %d, gestartet in %s)\n",
+	"%s: skipped, another git-air (pid %d, started in %s) already syncs it - stop one of the instances":       "%s: übersprungen, ein anderes git-air (PID %d, gestartet in %s) synchronisiert es bereits - beende eine der Instanzen",
+	"  ⚠️  Jira: checking %s failed: %v\n":                                                                    "  ⚠️  Jira: Prüfen von %s fehlgeschlagen: %v\n",
+	"  ⚠️  Jira: issue %s not found, commit not linked\n":                                                     "  ⚠️  Jira: Ticket %s nicht gefunden, Commit nicht verknüpft\n",
+	"  🔒 Leaving %s unstaged, locked by someone else in Git LFS\n":                                            "  🔒 Lasse %s ungestaget, in Git LFS von jemand anderem gesperrt\n",
+	"%s: changes to LFS files locked by others not committed: %s":                                             "%s: Änderungen an von anderen gesperrten LFS-Dateien nicht committet: %s",
+	"  ⚠️  %s: could not switch to %s: %s\n":                                                                  "  ⚠️  %s: Wechsel zu %s nicht möglich: %s\n",
+	"%s: commits skipped, could not switch from %s to %s: %s":                                                 "%s: Commits übersprungen, Wechsel von %s zu %s nicht möglich: %s",
+	"  🖥️  %s: switched from %s to %s\n":                                                                      "  🖥️  %s: von %s zu %s gewechselt\n",
+	"  git-air merge-machines <repo...>":                                                                      "  git-air merge-machines <repo...>",
+	"\nFetches every remote, merges the machines/* branches of the other machines":                            "\nHolt jedes Remote, führt die machines/*-Branches der anderen Maschinen",
+	"and the shared branch into %s, then pushes it and\n":                                                     "und den gemeinsamen Branch in %s zusammen, pusht ihn dann und\n",
+	"fast-forwards the shared branch on the remotes. Stops at the first conflict.":                            "spult den gemeinsamen Branch auf den Remotes vor. Hält beim ersten Konflikt an.",
+	"❌ Error: %s does not use machine_branch\n":                                                               "❌ Fehler: %s verwendet machine_branch nicht\n",
+	"❌ Error: %s is on %s, not %s\n":                                                                          "❌ Fehler: %s ist auf %s, nicht auf %s\n",
+	"❌ Error: fetching %s: %s\n":                                                                              "❌ Fehler: Holen von %s: %s\n",
+	"❌ Error: %s: merging %s: %s\n":                                                                           "❌ Fehler: %s: Zusammenführen von %s: %s\n",
+	"   Merge it by hand with git merge %s, then run merge-machines again\n":                                  "   Führe es von Hand mit git merge %s zusammen und starte merge-machines dann erneut\n",
+	"  🔀 %s: merged %s\n":                                                                                     "  🔀 %s: %s zusammengeführt\n",
+	"❌ Error: pushing %s to %s: %s\n":                                                                         "❌ Fehler: Pushen von %s nach %s: %s\n",
+	"  ✓ %s: %s and %s are up to date on %d remote(s)\n":                                                      "  ✓ %s: %s und %s sind auf %d Remote(s) aktuell\n",
+	"❌ Error: %v\n\n":                                                                                         "❌ Fehler: %v\n\n",
+	"❌ Error: --max-parallel-net must be at least 1, got: %d\n\n":                                             "❌ Fehler: --max-parallel-net muss mindestens 1 sein, erhalten: %d\n\n",
+	"❌ Error: --workers must be at least 1, got: %d\n\n":                                                      "❌ Fehler: --workers muss mindestens 1 sein, erhalten: %d\n\n",
+	"  git-air metered [on|off|auto]":                                                                         "  git-air metered [on|off|auto]",
+	"\nOn a metered connection git-air keeps committing but defers pushes and":                                "\nAuf einer getakteten Verbindung committet git-air weiter, verschiebt aber Pushes",
+	"skips pulls. \"on\" and \"off\" override the detection, \"auto\" restores it.":                           "und überspringt Pulls. \"on\" und \"off\" übersteuern die Erkennung, \"auto\" stellt sie wieder her.",
+	"Without an argument, shows the current mode. Running instances pick up a":                                "Ohne Argument wird der aktuelle Modus angezeigt. Laufende Instanzen übernehmen",
+	"change on their next cycle.":                                                                             "eine Änderung im nächsten Zyklus.",
+	"📶 Metered mode: ON (pushes deferred, pulls skipped)":                                                     "📶 Getakteter Modus: AN (Pushes verschoben, Pulls übersprungen)",
+	"📶 Metered mode: OFF (syncing normally, detection disabled)":                                              "📶 Getakteter Modus: AUS (normale Synchronisation, Erkennung deaktiviert)",
+	"📶 Metered mode: AUTO (connection %s)\n":                                                                  "📶 Getakteter Modus: AUTO (Verbindung %s)\n",
+	"🌐 %s is on %s: safe mode, checked every %.0f minutes without fsmonitor\n":                                "🌐 %s liegt auf %s: sicherer Modus, Prüfung alle %.0f Minuten ohne fsmonitor\n",
+	"  ⚠️  Could not write the sync note: %s\n":                                                               "  ⚠️  Die Sync-Notiz konnte nicht geschrieben werden: %s\n",
+	"  ⚠️  %s: pushing the sync notes to %s failed: %s\n":                                                     "  ⚠️  %s: Pushen der Sync-Notizen nach %s fehlgeschlagen: %s\n",
+	"  ⚠️  Desktop notification failed: %v\n":                                                                 "  ⚠️  Desktop-Benachrichtigung fehlgeschlagen: %v\n",
+	"  ⚠️  Notification command failed: %v\n":                                                                 "  ⚠️  Benachrichtigungsbefehl fehlgeschlagen: %v\n",
+	"  ⚠️  Notification webhook failed: %v\n":                                                                 "  ⚠️  Benachrichtigungs-Webhook fehlgeschlagen: %v\n",
+	"  ⚠️  Notification webhook returned %s\n":                                                                "  ⚠️  Benachrichtigungs-Webhook antwortete mit %s\n",
+	"  git-air %s [dir]\n":                                                                                    "  git-air %s [dir]\n",
+	"\nStops the running git-air that syncs the directory (default: the current":                              "\nHält das laufende git-air, das das Verzeichnis synchronisiert (Standard: das",
+	"one) from committing, pushing and pulling until git-air resume, so rebases":                              "aktuelle), bis git-air resume vom Committen, Pushen und Pullen ab, damit Rebases",
+	"and other history edits aren't auto-committed over. Inside one of its":                                   "und andere Änderungen am Verlauf nicht überschrieben werden. In einem seiner",
+	"repositories only that one is paused, in the directory it was started in":                                "Repositories wird nur dieses pausiert, im Verzeichnis, in dem es gestartet wurde,",
+	"everything. The repo being synced is finished first.":                                                    "alles. Das gerade synchronisierte Repo wird zuerst fertig bearbeitet.",
+	"\nLets the git-air paused with git-air pause sync the directory (default:":                               "\nLässt das mit git-air pause angehaltene git-air das Verzeichnis (Standard:",
+	"the current one) again, starting a cycle right away. In the directory it":                                "das aktuelle) wieder synchronisieren und startet sofort einen Zyklus. Im Verzeichnis,",
+	"was started in, every paused repository is resumed.":                                                     "in dem es gestartet wurde, wird jedes pausierte Repository fortgesetzt.",
+	"🔌 On mains power again":                                                                                  "🔌 Wieder am Stromnetz",
+	"  ⏸️  Push deferred (%s)\n":                                                                              "  ⏸️  Push verschoben (%s)\n",
+	"\n🚀 Pushing deferred commits...":                                                                         "\n🚀 Pushe verschobene Commits...",
+	"  ⏸️  %s: waiting for %s to be pushed\n":                                                                 "  ⏸️  %s: wartet, bis %s gepusht ist\n",
+	"  🚦 %s: waiting, policy: %s\n":                                                                           "  🚦 %s: wartet, Richtlinie: %s\n",
+	"  ❌ %s: could not capture changes: %v\n":                                                                 "  ❌ %s: Änderungen konnten nicht erfasst werden: %v\n",
+	"  ❌ %s: could not capture changes: %s\n":                                                                 "  ❌ %s: Änderungen konnten nicht erfasst werden: %s\n",
+	"  ❌ %s: could not store the snapshot\n":                                                                  "  ❌ %s: Snapshot konnte nicht gespeichert werden\n",
+	"  📥 %s: queued snapshot #%d (%s), review with git-air review\n":                                          "  📥 %s: Snapshot #%d (%s) eingereiht, prüfen mit git-air review\n",
+	"  git-air review <repo...>":                                                                              "  git-air review <repo...>",
+	"\nLists the snapshots captured in \"capture\": \"queue\" mode and commits them in":                       "\nListet die im Modus \"capture\": \"queue\" erfassten Snapshots und committet sie",
+	"batches: all snapshots up to the one you pick become one commit with your":                               "gebündelt: alle Snapshots bis zum gewählten werden ein Commit mit deiner",
+	"message, which is pushed to every remote.":                                                               "Nachricht, der auf jedes Remote gepusht wird.",
+	"❌ Error: review needs an interactive terminal":                                                           "❌ Fehler: review braucht ein interaktives Terminal",
+	"✓ %s: no queued snapshots\n":                                                                             "✓ %s: keine eingereihten Snapshots\n",
+	"⚠️  %s: HEAD moved since the snapshots were taken, they can only be discarded\n":                         "⚠️  %s: HEAD hat sich seit den Snapshots bewegt, sie können nur verworfen werden\n",
+	"  Discard the queue? The working tree keeps every change [y/N]: ":                                        "  Warteschlange verwerfen? Der Arbeitsbaum behält jede Änderung [y/N]: ",
+	"📋 %s: %d queued snapshot(s)\n":                                                                           "📋 %s: %d eingereihte(r) Snapshot(s)\n",
+	"  Commit up to snapshot [1-%d, a=all] / show [s N] / [d]iscard queue / [q]uit: ":                         "  Bis Snapshot committen [1-%d, a=alle] / zeigen [s N] / [d] Warteschlange verwerfen / [q] beenden: ",
+	"  🗑️  Queue discarded, the working tree keeps every change":                                              "  🗑️  Warteschlange verworfen, der Arbeitsbaum behält jede Änderung",
+	"  ⚠️  Please answer a snapshot number, a, s N, d or q":                                                   "  ⚠️  Bitte mit einer Snapshot-Nummer, a, s N, d oder q antworten",
+	"  💬 Message [%s]: ":                                                                                      "  💬 Nachricht [%s]: ",
+	"❌ Error: %s: %s\n":                                                                                       "❌ Fehler: %s: %s\n",
+	"  ⚠️  %s: %s ignored: %v\n":                                                                              "  ⚠️  %s: %s ignoriert: %v\n",
+	"%s: %s ignored: %v":                                                                                      "%s: %s ignoriert: %v",
+	"  git-air report [--from <date>] [--to <date>] [--format csv|json] [--events]":                           "  git-air report [--from <Datum>] [--to <Datum>] [--format csv|json] [--events]",
+	"\nExports per-repository activity and errors from the state store.":                                      "\nExportiert Aktivität und Fehler pro Repository aus dem Zustandsspeicher.",
+	"❌ Error: --from: %v\n":                                                                                   "❌ Fehler: --from: %v\n",
+	"❌ Error: --to: %v\n":                                                                                     "❌ Fehler: --to: %v\n",
+	"❌ Error: unknown format %q (use csv or json)\n":                                                          "❌ Fehler: unbekanntes Format %q (csv oder json verwenden)\n",
+	"❌ Error writing report: %v\n":                                                                            "❌ Fehler beim Schreiben des Berichts: %v\n",
+	"  🛟 %s: diverged from %s, local commits saved to %s and %s reset to %s/%s\n":                             "  🛟 %s: von %s abgewichen, lokale Commits in %s gesichert und %s auf %s/%s zurückgesetzt\n",
+	"Git Air: %s diverged from %s":                                                                            "Git Air: %s ist von %s abgewichen",
+	"Local commits were pushed to %s and %s now follows %s/%s. Merge the rescue branch to bring them back.":   "Lokale Commits wurden nach %s gepusht und %s folgt jetzt %s/%s. Führe den Rettungs-Branch zusammen, um sie zurückzuholen.",
+	"  git-air restore <repo> --at <time> [--to <dir>] [path...]":                                             "  git-air restore <repo> --at <Zeit> [--to <dir>] [Pfad...]",
+	"\nFinds the latest commit or git-air snapshot (capture queue, stash and":                                 "\nSucht den letzten Commit oder git-air-Snapshot (Modi capture queue, stash und",
+	"snapshot modes) made at or before the time and restores the paths, or the":                               "snapshot) zu oder vor der Zeit und stellt daraus die Pfade oder den ganzen",
+	"whole tree, from it. Uncommitted changes are stashed first; with --to the":                               "Baum wieder her. Nicht committete Änderungen werden zuerst gestasht; mit --to",
+	"repo is left alone and the files are written to the directory.":                                          "bleibt das Repo unberührt und die Dateien werden in das Verzeichnis geschrieben.",
+	"❌ Error: %s: %v\n":                                                                                       "❌ Fehler: %s: %v\n",
+	"🕰️  Restoring from %s\n":                                                                                 "🕰️  Stelle aus %s wieder her\n",
+	"✓ Files written to %s\n":                                                                                 "✓ Dateien nach %s geschrieben\n",
+	"❌ Error: %s\n":                                                                                           "❌ Fehler: %s\n",
+	"✓ Restored into the working tree; git-air commits it like any change":                                    "✓ In den Arbeitsbaum wiederhergestellt; git-air committet es wie jede Änderung",
+	"  ⚠️  %s: could not prune %s: %v\n":                                                                      "  ⚠️  %s: %s konnte nicht bereinigt werden: %v\n",
+	"  🧹 %s: pruned %d old snapshot(s)\n":                                                                     "  🧹 %s: %d alte(n) Snapshot(s) bereinigt\n",
+	"❌ Git server: %v\n":                                                                                      "❌ Git-Server: %v\n",
+	"❌ Git server: serve.token_env is not set, not serving":                                                   "❌ Git-Server: serve.token_env ist nicht gesetzt, kein Server",
+	"❌ Git server: %s is not set, not serving\n":                                                              "❌ Git-Server: %s ist nicht gesetzt, kein Server\n",
+	"⚠️  Git server: %s not served, %s already uses the name %s\n":                                            "⚠️  Git-Server: %s wird nicht angeboten, %s verwendet bereits den Namen %s\n",
+	"❌ Git server stopped: %v\n":                                                                              "❌ Git-Server beendet: %v\n",
+	"🌐 Git server: serving %d repos on %s (%s)\n":                                                             "🌐 Git-Server: biete %d Repos auf %s an (%s)\n",
+	"  ❌ %s: could not snapshot changes: %v\n":                                                                "  ❌ %s: Snapshot der Änderungen fehlgeschlagen: %v\n",
+	"  ❌ %s: could not snapshot changes: %s\n":                                                                "  ❌ %s: Snapshot der Änderungen fehlgeschlagen: %s\n",
+	"  📸 %s: snapshot in %s (%s)\n":                                                                           "  📸 %s: Snapshot in %s (%s)\n",
+	"  🧩 %s: not squashing, the unpushed commits include merges, tags or manual commits\n":                    "  🧩 %s: kein Squash, die ungepushten Commits enthalten Merges, Tags oder manuelle Commits\n",
+	"  ⚠️  %s: could not keep the commits to squash, pushing them as they are\n":                              "  ⚠️  %s: die zu squashenden Commits konnten nicht gesichert werden, pushe sie unverändert\n",
+	"  ⚠️  %s: squashing failed, pushing the commits as they are: %s\n":                                       "  ⚠️  %s: Squash fehlgeschlagen, pushe die Commits unverändert: %s\n",
+	"  🧩 %s: squashed %d auto-commits into one (originals kept in %s)\n":                                      "  🧩 %s: %d Auto-Commits zu einem zusammengefasst (Originale in %s erhalten)\n",
+	"  ❌ %s: could not store the snapshot: %s\n":                                                              "  ❌ %s: Snapshot konnte nicht gespeichert werden: %s\n",
+	"  📸 %s: stashed snapshot (%s), restore with git stash apply\n":                                           "  📸 %s: Snapshot gestasht (%s), wiederherstellen mit git stash apply\n",
+	"  git-air stats [--days N] [repo...]":                                                                    "  git-air stats [--days N] [repo...]",
+	"\nShows auto vs manual commits, average commit size, busiest hours and":                                  "\nZeigt Auto- und manuelle Commits, durchschnittliche Commit-Größe, aktivste Stunden",
+	"push success rate. Without repos, all repos below the current directory.":                                "und Push-Erfolgsquote. Ohne Repos alle Repos unter dem aktuellen Verzeichnis.",
+	"⚠️  State store unavailable, push rates omitted: %v\n":                                                   "⚠️  Zustandsspeicher nicht verfügbar, Push-Quoten ausgelassen: %v\n",
+	"📊 %s (last %d days)\n":                                                                                   "📊 %s (letzte %d Tage)\n",
+	"  Commits:       %d (%d auto, %d manual)\n":                                                              "  Commits:          %d (%d auto, %d manuell)\n",
+	"  Average size:  %.1f lines, %.1f files\n":                                                               "  Ø Größe:          %.1f Zeilen, %.1f Dateien\n",
+	"  Busiest hours: %s\n":                                                                                   "  Aktivste Stunden: %s\n",
+	"  Pushes:        %d/%d succeeded (%.0f%%)\n":                                                             "  Pushes:           %d/%d erfolgreich (%.0f%%)\n",
+	"  Pushes:        none recorded\n":                                                                        "  Pushes:           keine erfasst\n",
+	"  ⏸️  Holding the pointer bump of %s until the submodule commit is pushed\n":                             "  ⏸️  Halte die Zeigeraktualisierung von %s zurück, bis der Submodul-Commit gepusht ist\n",
+	"%s: submodule pointer bump of %s not committed, the submodule commit isn't on its remote yet":            "%s: Zeigeraktualisierung des Submoduls %s nicht committet, der Submodul-Commit ist noch nicht auf seinem Remote",
+	"  ⚠️  Tagging %s failed: %s\n":                                                                           "  ⚠️  Taggen von %s fehlgeschlagen: %s\n",
+	"  🏷️  Version bump in %s, tagged %s\n":                                                                   "  🏷️  Versionssprung in %s, getaggt als %s\n",
+	"  🏷️  Pushing %s to %s...":                                                                               "  🏷️  Pushe %s nach %s...",
+	"  ⚠️  Template .gitignore: %v\n":                                                                         "  ⚠️  Vorlage .gitignore: %v\n",
+	"  ⚠️  Template license: %v\n":                                                                            "  ⚠️  Vorlage Lizenz: %v\n",
+	"  ⚠️  Could not write %s: %v\n":                                                                          "  ⚠️  %s konnte nicht geschrieben werden: %v\n",
+	"  📄 Added %s from template\n":                                                                            "  📄 %s aus der Vorlage hinzugefügt\n",
+	"⚠️  Tray: %v - running without an icon\n":                                                                "⚠️  Tray: %v - laufe ohne Symbol\n",
+	"🟢 Tray: icon shown (pause, sync now and recent events in its menu)":                                      "🟢 Tray: Symbol angezeigt (Pause, Jetzt synchronisieren und letzte Ereignisse im Menü)",
+	"⚠️  Tray: the icon was closed, git-air keeps running":                                                    "⚠️  Tray: das Symbol wurde geschlossen, git-air läuft weiter",
+	"▶️  Resumed from the tray":                                                                               "▶️  Über das Tray fortgesetzt",
+	"⏸️  Paused from the tray: no commits, pushes or pulls until resumed":                                     "⏸️  Über das Tray angehalten: keine Commits, Pushes oder Pulls bis zur Fortsetzung",
+	"🔄 Sync requested from the tray":                                                                          "🔄 Synchronisation über das Tray angefordert",
+	"👋 Quit from the tray":                                                                                    "👋 Über das Tray beendet",
+	"⚠️  Tray: %v\n":                                                                                          "⚠️  Tray: %v\n",
+	"⚠️  Tray: could not open %s: %v\n":                                                                       "⚠️  Tray: %s konnte nicht geöffnet werden: %v\n",
+	"❌ Trigger endpoint: %s is not set, not listening\n":                                                      "❌ Trigger-Endpunkt: %s ist nicht gesetzt, lausche nicht\n",
+	"❌ Trigger endpoint: %s is reachable from other machines, set trigger.token_env or listen on 127.0.0.1\n": "❌ Trigger-Endpunkt: %s ist von anderen Rechnern erreichbar, setze trigger.token_env oder lausche auf 127.0.0.1\n",
+	"❌ Trigger endpoint stopped: %v\n":                                                                        "❌ Trigger-Endpunkt beendet: %v\n",
+	"✏️  Trigger endpoint: listening for editor saves on %s\n":                                                "✏️  Trigger-Endpunkt: lausche auf %s auf Speichervorgänge im Editor\n",
+	"✏️  %s: saved in an editor, syncing now\n":                                                               "✏️  %s: im Editor gespeichert, synchronisiere jetzt\n",
+	"  git-air trigger [file...]":                                                                             "  git-air trigger [Datei...]",
+	"\nTells the running git-air that the files were just saved, so their repos":                              "\nTeilt dem laufenden git-air mit, dass die Dateien gerade gespeichert wurden, damit",
+	"are synced as soon as they settle instead of at the next cycle; meant for":                               "ihre Repos synchronisiert werden, sobald sie sich beruhigt haben, statt im nächsten",
+	"editor on-save hooks. With trigger.listen in the config it goes to that":                                 "Zyklus; gedacht für On-Save-Hooks von Editoren. Mit trigger.listen in der",
+	"endpoint, otherwise to the control socket of the git-air syncing the":                                    "Konfiguration geht es an diesen Endpunkt, sonst an den Steuer-Socket des git-air,",
+	"files, which syncs them right away. Without files, the git-air syncing":                                  "das die Dateien synchronisiert und sie sofort abgleicht. Ohne Dateien startet das",
+	"the current directory starts a cycle now.":                                                               "git-air, das das aktuelle Verzeichnis synchronisiert, jetzt einen Zyklus.",
+	"❌ Error: %s is not set\n":                                                                                "❌ Fehler: %s ist nicht gesetzt\n",
+	"❌ %s: git-air is not reachable: %v\n":                                                                    "❌ %s: git-air ist nicht erreichbar: %v\n",
+	"the watcher closed":                                                                                      "der Watcher wurde geschlossen",
+	"  ⚠️  %s: Watchman unavailable, polling instead: %v\n":                                                   "  ⚠️  %s: Watchman nicht verfügbar, frage stattdessen ab: %v\n",
+	"  ⚠️  %s: Watchman query failed: %v\n":                                                                   "  ⚠️  %s: Watchman-Abfrage fehlgeschlagen: %v\n",
+	"  ⚠️  %s: inotify watch limit reached, polling instead\n":                                                "  ⚠️  %s: inotify-Watch-Limit erreicht, frage stattdessen ab\n",
+	"%s: inotify watch limit reached, changes are polled - raise it with sudo sysctl fs.inotify.max_user_watches=524288 (add fs.inotify.max_user_watches=524288 to /etc/sysctl.d/99-inotify.conf to keep it)": "%s: inotify-Watch-Limit erreicht, Änderungen werden abgefragt - erhöhe es mit sudo sysctl fs.inotify.max_user_watches=524288 (fs.inotify.max_user_watches=524288 in /etc/sysctl.d/99-inotify.conf eintragen, um es beizubehalten)",
+	"  ⚠️  %s (%s) is in the %s file system: git is slow across the WSL boundary and file locks aren't shared.\n":                                                                                             "  ⚠️  %s (%s) liegt im Dateisystem von %s: git ist über die WSL-Grenze langsam und Dateisperren werden nicht geteilt.\n",
+	"      Move it to this side or run git-air on the %s side for it\n":                                                                                                                                       "      Verschiebe es auf diese Seite oder führe git-air für es auf der Seite von %s aus\n",
+	"  ⚠️  %s: on branch %s, but branch is set to %s - not pushing or pulling\n":                                                                                                                              "  ⚠️  %s: auf Branch %s, aber branch ist auf %s gesetzt - kein Push oder Pull\n",
+	"%s: pushes and pulls held, %s is checked out instead of %s":                                                                                                                                              "%s: Pushes und Pulls angehalten, %s ist statt %s ausgecheckt",
+	"  🚀 %s: pushed to %s\n":             "  🚀 %s: zu %s gepusht\n",
+	"  📥 %s: %s is up to date\n":         "  📥 %s: %s ist aktuell\n",
+	"  📡 %s: pulled updates from %s\n":   "  📡 %s: Updates von %s gepullt\n",
+	"  ⚠️  %s: diverged from %s\n":       "  ⚠️  %s: von %s auseinandergelaufen\n",
+	"  ❌ %s: push to %s failed: %s\n":    "  ❌ %s: Push zu %s fehlgeschlagen: %s\n",
+	"  ❌ %s: pull from %s failed: %s\n":  "  ❌ %s: Pull von %s fehlgeschlagen: %s\n",
+	"  📥 %s: nothing incoming from %s\n": "  📥 %s: nichts eingehend von %s\n",
+	"intervals under 30 seconds keep git status running in every repo almost constantly": "Intervalle unter 30 Sekunden lassen git status in jedem Repo fast ständig laufen",
+	"with intervals over a day, changes can stay uncommitted and unpushed for days":      "bei Intervallen über einem Tag können Änderungen tagelang weder committet noch gepusht werden",
+	"add the generated paths to .gitignore":                                              "trage die erzeugten Pfade in .gitignore ein",
+	"add /%s to .gitignore":                                                              "trage /%s in .gitignore ein",
+	"%s has unpushed commits":                                                            "%s hat ungepushte Commits",
+	"policy: %s":                                                                         "Richtlinie: %s",
+	"clean":                                                                              "sauber",
+	"only churn, no commit":                                                              "nur Rauschen, kein Commit",
+	"would wait, %s still being written":                                                 "würde warten, %s wird noch geschrieben",
+	"would pause, %d new untracked files in %s":                                          "würde pausieren, %d neue ungetrackte Dateien in %s",
+	" (monorepo)":                                                                        " (Monorepo)",
+	"would commit %s%s":                                                                  "würde %s%s committen",
+	"lease on %s unavailable: %s":                                                        "Lease auf %s nicht verfügbar: %s",
+	"%s holds the lease until %s":                                                        "%s hält die Lease bis %s",
+	"could not write the lease":                                                          "Lease konnte nicht geschrieben werden",
+	"another machine took the lease":                                                     "ein anderer Rechner hat die Lease übernommen",
+	"the SSH agent has no keys loaded":                                                   "der SSH-Agent hat keine Schlüssel geladen",
+	"no SSH agent is running":                                                            "es läuft kein SSH-Agent",
+	"ssh-add is not available":                                                           "ssh-add ist nicht verfügbar",
+	"no answer within %s":                                                                "keine Antwort innerhalb von %s",
+	" - no cached credentials, run a git fetch by hand once or set up a credential helper": " - keine gespeicherten Zugangsdaten, führe einmal git fetch von Hand aus oder richte einen Credential-Helper ein",
+	"metered mode":       "getakteter Modus",
+	"metered connection": "getaktete Verbindung",
+	"battery at %d%%":    "Akku bei %d%%",
+	"squash on push: %d commit(s), pushing at %s": "Squash beim Push: %d Commit(s), Push um %s",
+	"Git Air: %d repo(s) in sync":                 "Git Air: %d Repo(s) synchron",
+	"Git Air: paused":                             "Git Air: pausiert",
+	"Git Air: %d problem(s), see recent events":   "Git Air: %d Problem(e), siehe letzte Ereignisse",
+	"Git Air: pushes and pulls paused (%s)":       "Git Air: Pushes und Pulls pausiert (%s)",
+	"Git Air: %d repo(s) waiting to push":         "Git Air: %d Repo(s) warten auf Push",
+	"local":                                       "lokal",
+	"git status failed: %v":                       "git status fehlgeschlagen: %v",
+	"❌ Error: git status failed: %v\n":            "❌ Fehler: git status fehlgeschlagen: %v\n",
+}
@@ -0,0 +1,222 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git-air/pkg/ai"
+)
+
+// knownIgnoreDirs are directories that hold generated or installed files
+var knownIgnoreDirs = []string{
+	"node_modules", "target", "dist", "build", "out", ".venv", "venv", "__pycache__",
+	".pytest_cache", ".mypy_cache", ".tox", ".gradle", ".next", ".nuxt", ".cache",
+	"coverage", ".idea", ".terraform", "bin", "obj",
+}
+
+// knownIgnoreFiles are file patterns that are almost never meant to be committed
+var knownIgnoreFiles = []string{
+	"*.log", "*.tmp", "*.pyc", "*.pyo", "*.o", "*.obj", "*.class", "*.exe", "*.dll",
+	"*.so", "*.dylib", ".env", "*.pid",
+}
+
+// runSuggestIgnore implements `git-air suggest-ignore <repo>`
+func runSuggestIgnore(args []string) int {
+	fs := flag.NewFlagSet("suggest-ignore", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	useAI := fs.Bool("ai", false, "Ask the configured AI provider for additional suggestions")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air suggest-ignore [--ai] [-c <file>] <repo>"))
+		fmt.Println(tr("\nAnalyzes untracked files and proposes .gitignore entries,"))
+		fmt.Println(tr("which are appended after one confirmation."))
+	}
+	positional := parseInterspersed(fs, args)
+
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	repoPath := positional[0]
+
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	settings := settingsFor(repoPath)
+
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s is not a Git repository\n"), repoPath)
+		return 1
+	}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+		return 1
+	}
+	defer leave()
+
+	status, err := gitStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: git status failed: %v\n"), err)
+		return 1
+	}
+	changes := changedFiles(settings, status)
+	var untracked []string
+	for _, change := range changes {
+		if change.Status == "??" {
+			untracked = append(untracked, change.Path)
+		}
+	}
+	if len(untracked) == 0 {
+		fmt.Println(tr("✓ No untracked files, nothing to suggest"))
+		return 0
+	}
+	fmt.Printf(tr("🔍 Analyzing %d untracked files...\n"), len(untracked))
+
+	suggestions := heuristicIgnores(untracked)
+	if dir, _ := largestUntrackedDir(changes, settings.untrackedLimit()); dir != "" && dir != "." {
+		suggestions = append(suggestions, "/"+dir)
+	}
+	if *useAI {
+		aiSuggestions, err := aiIgnores(settings.aiConfig(), untracked)
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  AI suggestions unavailable: %v\n"), err)
+		}
+		suggestions = append(suggestions, aiSuggestions...)
+	}
+	suggestions = newIgnoreEntries(suggestions)
+
+	if len(suggestions) == 0 {
+		fmt.Println(tr("✓ No .gitignore entries to suggest"))
+		return 0
+	}
+
+	fmt.Println(tr("\n💡 Suggested .gitignore entries:"))
+	for _, entry := range suggestions {
+		fmt.Printf("  %s\n", entry)
+	}
+	fmt.Printf(tr("\nAppend these %d entries to .gitignore? [y/N]: "), len(suggestions))
+	answer, _ := stdin.ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Println(tr("⏭️  Nothing changed"))
+		return 0
+	}
+
+	if err := appendIgnores(suggestions); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error updating .gitignore: %v\n"), err)
+		return 1
+	}
+	fmt.Println(tr("✓ .gitignore updated"))
+	return 0
+}
+
+// heuristicIgnores matches untracked paths against well-known generated directories and files
+func heuristicIgnores(untracked []string) []string {
+	found := map[string]bool{}
+	for _, path := range untracked {
+		parts := strings.Split(path, "/")
+		for _, dir := range parts[:len(parts)-1] {
+			for _, known := range knownIgnoreDirs {
+				if dir == known {
+					found[known+"/"] = true
+				}
+			}
+		}
+		if matched := matchingPattern(knownIgnoreFiles, parts[len(parts)-1]); matched != "" {
+			found[matched] = true
+		}
+	}
+
+	entries := make([]string, 0, len(found))
+	for entry := range found {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// matchingPattern returns the first pattern matching name, or ""
+func matchingPattern(patterns []string, name string) string {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// aiIgnores asks the AI provider which of the untracked paths should be ignored
+func aiIgnores(cfg AIConfig, untracked []string) ([]string, error) {
+	listing := untracked
+	if len(listing) > 300 {
+		listing = listing[:300]
+	}
+	existing, _ := os.ReadFile(inRepo(".gitignore"))
+
+	system := "You maintain .gitignore files. Reply with .gitignore lines only, one per line, " +
+		"no comments or explanations. Only suggest entries for generated, installed, cached, " +
+		"secret or machine-specific files. Reply with nothing if everything looks like source."
+	prompt := fmt.Sprintf("Existing .gitignore:\n%s\n\nUntracked files (%d total, first %d shown):\n%s",
+		string(existing), len(untracked), len(listing), strings.Join(listing, "\n"))
+
+	reply, err := ai.Complete(cfg, system, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "`")
+		if line == "" || strings.HasPrefix(line, "#") || strings.Contains(line, " ") {
+			continue
+		}
+		entries = append(entries, line)
+	}
+	return entries, nil
+}
+
+// newIgnoreEntries drops duplicates and entries already present in .gitignore
+func newIgnoreEntries(entries []string) []string {
+	present := map[string]bool{}
+	if data, err := os.ReadFile(inRepo(".gitignore")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			present[strings.TrimSpace(line)] = true
+		}
+	}
+
+	var result []string
+	for _, entry := range entries {
+		if present[entry] || present["/"+entry] {
+			continue
+		}
+		present[entry] = true
+		result = append(result, entry)
+	}
+	return result
+}
+
+// appendIgnores appends entries to the repo's .gitignore
+func appendIgnores(entries []string) error {
+	existing, err := os.ReadFile(inRepo(".gitignore"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.OpenFile(inRepo(".gitignore"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	text := "# Added by git-air suggest-ignore\n" + strings.Join(entries, "\n") + "\n"
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		text = "\n" + text
+	}
+	_, err = f.WriteString(text)
+	return err
+}
@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"strings"
+	"time"
+)
+
+// immediatePollInterval is how often repos with immediate rules are checked
+// between cycles
+const immediatePollInterval = 2 * time.Second
+
+// immediateSeen is the last status of each repo's immediate paths, so a
+// change is acted on once and not on every poll
+var immediateSeen = map[string]string{}
+
+// immediatePaths lists the changed files that match the immediate patterns
+func immediatePaths(changes []fileChange, patterns []string) []string {
+	var paths []string
+	for _, change := range changes {
+		if matchesAny(patterns, change.Path) {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
+// sleepWatchingImmediate sleeps until the next cycle, but meanwhile polls the
+// immediate paths of every repo that has them and syncs a repo as soon as one
+// of them changes. Returns true if the sleep was cut short by "Sync now".
+func sleepWatchingImmediate(repos []string, duration time.Duration) bool {
+	var watching []string
+	for _, repo := range repos {
+		if len(settingsFor(repo).Immediate) > 0 {
+			watching = append(watching, repo)
+		}
+	}
+	if len(watching) == 0 {
+		return sleepHandlingTriggers(repos, duration)
+	}
+
+	deadline := time.Now().Add(duration)
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return false
+		}
+		if wait > immediatePollInterval {
+			wait = immediatePollInterval
+		}
+		if sleepHandlingTriggers(repos, wait) {
+			return true
+		}
+
+		for _, repo := range watching {
+			if interrupted() {
+				return true
+			}
+			if immediateChanged(repo, settingsFor(repo).Immediate) {
+				syncTrigger = "immediate"
+				syncRepo(repo)
+				syncTrigger = "cycle"
+			}
+		}
+	}
+}
+
+// immediateChanged reports whether the status of a repo's immediate paths
+// differs from the last poll and is not clean
+func immediateChanged(repoPath string, patterns []string) bool {
+	args := []string{"-C", repoPath, "status", "--porcelain", "-z", "--untracked-files=all", "--"}
+	for _, pattern := range patterns {
+		args = append(args, includePathspec(pattern))
+	}
+	output, err := gitCommand(args...).Output()
+	if err != nil {
+		return false
+	}
+	status := string(output)
+	changed := status != "" && status != immediateSeen[repoPath]
+	immediateSeen[repoPath] = status
+	return changed
+}
+
+// includePathspec turns a pattern into a git pathspec that matches it
+func includePathspec(pattern string) string {
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return ":(glob)" + pattern
+}
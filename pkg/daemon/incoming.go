@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// incomingCommit is a commit a pull brought in
+type incomingCommit struct {
+	Author  string
+	Subject string
+	Files   []string
+}
+
+// incomingCommits lists the non-merge commits in from..to, oldest first
+func incomingCommits(from, to string) []incomingCommit {
+	output, err := gitCommand("log", "--reverse", "--no-merges", "--name-only", "--format=%x1e%an%x1f%s", from+".."+to).Output()
+	if err != nil {
+		return nil
+	}
+
+	var commits []incomingCommit
+	for _, record := range strings.Split(string(output), "\x1e")[1:] {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		author, subject, _ := strings.Cut(lines[0], "\x1f")
+		commit := incomingCommit{Author: author, Subject: subject}
+		for _, file := range lines[1:] {
+			if file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// reportIncoming prints and notifies the commits a pull from remote brought
+// in since before, so the human learns what arrived
+func reportIncoming(repoPath, remote, before string) {
+	commits := incomingCommits(before, "HEAD")
+	if len(commits) == 0 {
+		return
+	}
+
+	lines := commitLines(commits)
+	fmt.Printf(tr("  📬 %d incoming commit(s) from %s:\n"), len(commits), remote)
+	for _, line := range lines {
+		fmt.Printf("    • %s\n", line)
+	}
+
+	if len(lines) > 10 {
+		lines = append(lines[:10], fmt.Sprintf("… and %d more", len(lines)-10))
+	}
+	notify(fmt.Sprintf(tr("Git Air: %s updated from %s"), displayName(repoPath), remote), strings.Join(lines, "\n"), repoPath)
+}
+
+// reviewIncoming reports what remote/branch has that HEAD doesn't, without
+// merging it, until the user runs git-air pull
+func reviewIncoming(repoPath, remote, branch string) {
+	commits := incomingCommits("HEAD", remote+"/"+branch)
+	if len(commits) == 0 {
+		fmt.Printf(tr("  📥 %s: nothing incoming from %s\n"), filepath.Base(repoPath), remote)
+		clearAlert(repoPath, "incoming:"+remote)
+		return
+	}
+
+	lines := commitLines(commits)
+	fmt.Printf(tr("  👀 %s/%s is %d commit(s) ahead - run git-air pull %s to merge:\n"), remote, branch, len(commits), repoPath)
+	for _, line := range lines {
+		fmt.Printf("    • %s\n", line)
+	}
+	raiseAlert(repoPath, "incoming:"+remote, fmt.Sprintf(tr("%s: %s/%s is %d commit(s) ahead, waiting for git-air pull (latest: %s)"), displayName(repoPath), remote, branch, len(commits), lines[len(lines)-1]))
+}
+
+// commitLines renders commits as "author: subject (files)"
+func commitLines(commits []incomingCommit) []string {
+	var lines []string
+	for _, commit := range commits {
+		line := fmt.Sprintf("%s: %s", commit.Author, commit.Subject)
+		if files := commit.Files; len(files) > 3 {
+			line += fmt.Sprintf(" (%s, +%d more)", strings.Join(files[:3], ", "), len(files)-3)
+		} else if len(files) > 0 {
+			line += " (" + strings.Join(files, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// runPull implements `git-air pull <repo...>`: pull from every remote right
+// away, including repos whose pulls wait for review
+func runPull(args []string) int {
+	fs := flag.NewFlagSet("pull", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air pull <repo...>"))
+		fmt.Println(tr("\nFetches and merges from every remote now. This is how incoming changes"))
+		fmt.Println(tr("are accepted in repos with \"pull\": \"review\"; CI gates are skipped too."))
+	}
+	repos := parseInterspersed(fs, args)
+	if len(repos) == 0 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+
+	status := 0
+	for _, repoPath := range repos {
+		if !pullRepoNow(repoPath) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// pullRepoNow pulls one repo for runPull
+func pullRepoNow(repoPath string) bool {
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s is not a Git repository\n"), repoPath)
+		return false
+	}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+		return false
+	}
+	defer leave()
+	unlock, busy := claimRepo(repoPath)
+	if busy != "" {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s, try again later\n"), repoPath, busy)
+		return false
+	}
+	defer unlock()
+
+	settings := settingsFor(repoPath)
+	ok := pullFromRemotes(repoPath, settings, true)
+	syncEncrypted(repoPath, settings.Encrypt)
+	return ok
+}
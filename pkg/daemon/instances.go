@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// instanceRecord is what a running git-air publishes in the state dir about
+// itself, so instances started at other directory levels notice each other
+type instanceRecord struct {
+	PID     int       `json:"pid"`
+	Root    string    `json:"root"`
+	Started time.Time `json:"started"`
+	Repos   []string  `json:"repos"`
+}
+
+// instanceStarted is when this instance started; older instances keep their repos
+var instanceStarted = time.Now()
+
+// instanceUnlock releases the lock that marks this instance as alive
+var instanceUnlock func()
+
+// forceInstance runs this instance even where another one already syncs
+// the tree or its repos (--force)
+var forceInstance bool
+
+// treeUnlock releases the lock on the directory tree this instance syncs
+var treeUnlock func()
+
+// instancesDir holds one <pid>.json record and <pid>.lock per running instance
+func instancesDir() string {
+	return filepath.Join(stateDir(), "instances")
+}
+
+// claimRepos drops the repos an older running instance already manages,
+// reporting each overlap, and publishes the rest as managed here
+func claimRepos(repos []string) []string {
+	if dryRun {
+		return repos // Shows every repo and claims none
+	}
+	owners := map[string]instanceRecord{}
+	for _, other := range liveInstances() {
+		if forceInstance {
+			break
+		}
+		if other.Started.After(instanceStarted) || other.Started.Equal(instanceStarted) && other.PID > os.Getpid() {
+			continue // Younger instances give way to us
+		}
+		for _, repo := range other.Repos {
+			owners[repo] = other
+		}
+	}
+
+	var claimed, absolute []string
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			abs = repo
+		}
+		if other, ok := owners[abs]; ok {
+			fmt.Printf(tr("  ⚠️  Skipping %s - already managed by git-air (pid %d, started in %s)\n"), displayName(repo), other.PID, other.Root)
+			raiseAlert(repo, "overlap", fmt.Sprintf(tr("%s: skipped, another git-air (pid %d, started in %s) already syncs it - stop one of the instances"), displayName(repo), other.PID, other.Root))
+			continue
+		}
+		claimed = append(claimed, repo)
+		absolute = append(absolute, abs)
+	}
+	publishInstance(absolute)
+	return claimed
+}
+
+// liveInstances reads the records of the other running instances and removes
+// those of instances that are gone (their lock is free)
+func liveInstances() []instanceRecord {
+	files, _ := filepath.Glob(filepath.Join(instancesDir(), "*.json"))
+	var live []instanceRecord
+	for _, file := range files {
+		base := strings.TrimSuffix(file, ".json")
+		if base == filepath.Join(instancesDir(), fmt.Sprint(os.Getpid())) {
+			continue
+		}
+		if unlock, err := lockFile(base + ".lock"); err == nil {
+			unlock()
+			os.Remove(file)
+			os.Remove(base + ".lock")
+			os.Remove(base + ".sock")
+			continue
+		}
+		var record instanceRecord
+		if data, err := os.ReadFile(file); err == nil && json.Unmarshal(data, &record) == nil {
+			live = append(live, record)
+		}
+	}
+	return live
+}
+
+// publishInstance writes this instance's record, taking its alive lock the
+// first time. Failing only means other instances can't see this one.
+func publishInstance(repos []string) {
+	base := filepath.Join(instancesDir(), fmt.Sprint(os.Getpid()))
+	if instanceUnlock == nil {
+		if os.MkdirAll(instancesDir(), 0755) != nil {
+			return
+		}
+		unlock, err := lockFile(base + ".lock")
+		if err != nil {
+			return
+		}
+		instanceUnlock = unlock
+	}
+
+	root, _ := os.Getwd()
+	data, err := json.Marshal(instanceRecord{PID: os.Getpid(), Root: root, Started: instanceStarted, Repos: repos})
+	if err != nil {
+		return
+	}
+	if os.WriteFile(base+".json.tmp", data, 0644) == nil {
+		os.Rename(base+".json.tmp", base+".json")
+	}
+}
+
+// treeLockPath is the lock file for a root directory, named by a hash of
+// its absolute path so it stays out of the tree itself
+func treeLockPath(root string) string {
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(instancesDir(), "tree-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
+// lockTree takes the lock on the directory tree git-air was started in, so
+// a second instance started in the same directory refuses to run instead
+// of committing and pushing the same changes. The lock is held until exit.
+func lockTree() error {
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(instancesDir(), 0755); err != nil {
+		return err
+	}
+	unlock, err := lockFile(treeLockPath(root))
+	if err != nil {
+		for _, other := range liveInstances() {
+			if other.Root == root {
+				return fmt.Errorf(tr("another git-air (pid %d) already syncs %s"), other.PID, root)
+			}
+		}
+		return fmt.Errorf(tr("another git-air already syncs %s"), root)
+	}
+	treeUnlock = unlock
+	return nil
+}
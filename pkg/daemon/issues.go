@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IssueLinks adds a reference line to auto-commits so they appear on the
+// issue's timeline (GitHub "Refs #42", Linear "Refs ENG-123")
+type IssueLinks struct {
+	Provider      string            `json:"provider,omitempty"`       // "github" or "linear", picks the default branch pattern
+	Issue         string            `json:"issue,omitempty"`          // fixed issue, e.g. "42" or "ENG-123"
+	Branches      map[string]string `json:"branches,omitempty"`       // branch glob → issue, e.g. {"release/*": "17"}
+	BranchPattern string            `json:"branch_pattern,omitempty"` // regex whose first group is the issue in the branch name
+	Format        string            `json:"format,omitempty"`         // default "Refs {issue}"
+}
+
+// defaultIssueBranchPatterns find issues in typical branch names:
+// 42-fix-login, issue-42, fix/gh-42 and eng-123-title, user/eng-123-title
+var defaultIssueBranchPatterns = map[string]string{
+	"github": `(?i)(?:^|/)(?:issue-|gh-)?([0-9]+)(?:-|$)`,
+	"linear": `(?i)(?:^|/)([a-z][a-z0-9]*-[0-9]+)(?:-|$)`,
+}
+
+// validate checks the provider and patterns
+func (l *IssueLinks) validate() error {
+	if _, ok := defaultIssueBranchPatterns[l.Provider]; !ok {
+		return fmt.Errorf("issue_links provider must be github or linear, got %q", l.Provider)
+	}
+	if l.BranchPattern != "" {
+		re, err := regexp.Compile(l.BranchPattern)
+		if err != nil {
+			return fmt.Errorf("invalid issue_links branch_pattern: %v", err)
+		}
+		if re.NumSubexp() < 1 {
+			return fmt.Errorf("issue_links branch_pattern needs a group for the issue")
+		}
+	}
+	for pattern := range l.Branches {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid issue_links branch %q", pattern)
+		}
+	}
+	return nil
+}
+
+// issueReference returns the reference line for the current branch, or ""
+func issueReference(links *IssueLinks) string {
+	if links == nil {
+		return ""
+	}
+
+	branch := getCurrentBranch()
+	issue := links.Issue
+	patterns := make([]string, 0, len(links.Branches))
+	for pattern := range links.Branches {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, branch); ok {
+			issue = links.Branches[pattern]
+			break
+		}
+	}
+	if issue == "" {
+		pattern := links.BranchPattern
+		if pattern == "" {
+			pattern = defaultIssueBranchPatterns[links.Provider]
+		}
+		if match := regexp.MustCompile(pattern).FindStringSubmatch(branch); match != nil {
+			issue = match[1]
+		}
+	}
+	if issue == "" {
+		return ""
+	}
+
+	issue = strings.TrimPrefix(issue, "#")
+	if links.Provider == "github" {
+		issue = "#" + issue
+	} else {
+		issue = strings.ToUpper(issue)
+	}
+	format := links.Format
+	if format == "" {
+		format = "Refs {issue}"
+	}
+	return strings.ReplaceAll(format, "{issue}", issue)
+}
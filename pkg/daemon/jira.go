@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// JiraConfig links auto-commits to Jira issues with smart-commit syntax
+type JiraConfig struct {
+	URL      string `json:"url,omitempty"`       // e.g. https://acme.atlassian.net, empty disables
+	User     string `json:"user,omitempty"`      // Jira Cloud account email; empty sends the token as a bearer PAT (Server/Data Center)
+	TokenEnv string `json:"token_env,omitempty"` // env var holding the API token, default JIRA_API_TOKEN
+	LogTime  bool   `json:"log_time,omitempty"`  // add "#time" with the time spent, logging work on the issue
+}
+
+// jiraKeyPattern finds issue keys such as PROJ-123 in branch names
+var jiraKeyPattern = regexp.MustCompile(`(?i)(?:^|[^a-z0-9])([a-z][a-z0-9_]+-[1-9][0-9]*)(?:$|[^0-9])`)
+
+// jiraChecked caches issue lookups for an hour, so every commit doesn't hit the API
+var jiraChecked = map[string]jiraLookup{}
+
+type jiraLookup struct {
+	exists bool
+	at     time.Time
+}
+
+// jiraReference returns the smart-commit line for the active issue, or "".
+// The issue comes from the repo's jira_issue setting or the branch name and
+// must exist in Jira.
+func jiraReference(settings RepoSettings, spent time.Duration) string {
+	if config.Jira.URL == "" {
+		return ""
+	}
+
+	key := ""
+	if settings.JiraIssue != nil {
+		key = strings.ToUpper(*settings.JiraIssue)
+	} else if match := jiraKeyPattern.FindStringSubmatch(getCurrentBranch()); match != nil {
+		key = strings.ToUpper(match[1])
+	}
+	if key == "" {
+		return ""
+	}
+
+	lookup, ok := jiraChecked[key]
+	if !ok || time.Since(lookup.at) > time.Hour {
+		exists, err := jiraIssueExists(key)
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  Jira: checking %s failed: %v\n"), key, err)
+			return ""
+		}
+		lookup = jiraLookup{exists: exists, at: time.Now()}
+		jiraChecked[key] = lookup
+	}
+	if !lookup.exists {
+		fmt.Printf(tr("  ⚠️  Jira: issue %s not found, commit not linked\n"), key)
+		return ""
+	}
+
+	if config.Jira.LogTime {
+		return key + " #time " + jiraDuration(spent)
+	}
+	return key
+}
+
+// jiraIssueExists looks an issue up through the Jira REST API
+func jiraIssueExists(key string) (bool, error) {
+	tokenEnv := config.Jira.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "JIRA_API_TOKEN"
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return false, fmt.Errorf("%s is not set", tokenEnv)
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(config.Jira.URL, "/")+"/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary", nil)
+	if err != nil {
+		return false, err
+	}
+	if config.Jira.User != "" {
+		req.SetBasicAuth(config.Jira.User, token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := http.Client{Timeout: 20 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%s", resp.Status)
+	}
+}
+
+// jiraDuration formats a duration the way Jira's #time expects, e.g. "1h 5m"
+func jiraDuration(d time.Duration) string {
+	mins := int(d.Round(time.Minute).Minutes())
+	if mins < 1 {
+		mins = 1
+	}
+	if mins < 60 {
+		return fmt.Sprintf("%dm", mins)
+	}
+	if mins%60 == 0 {
+		return fmt.Sprintf("%dh", mins/60)
+	}
+	return fmt.Sprintf("%dh %dm", mins/60, mins%60)
+}
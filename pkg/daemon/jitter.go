@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// maxJitter caps jitter_seconds; more would stretch short intervals beyond recognition
+const maxJitter = 30 * time.Minute
+
+// jitter returns a random delay of up to jitter_seconds, so machines running
+// git-air with the same interval drift apart instead of hitting the server
+// in the same second. 0 without the setting.
+func jitter() time.Duration {
+	if config.JitterSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(config.JitterSeconds)))
+}
+
+// validateJitter checks jitter_seconds
+func validateJitter(jitter Seconds) error {
+	if jitter < 0 || time.Duration(jitter) > maxJitter {
+		return fmt.Errorf("jitter_seconds must be between 0 and %d", int(maxJitter.Seconds()))
+	}
+	return nil
+}
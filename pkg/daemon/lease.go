@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// leaseRef is the remote ref a machine holds while it commits and pushes a
+// repo with remote_lease. It points to a blob "<host> <expiry unix time>".
+const leaseRef = "refs/git-air/lock"
+
+// leaseDuration is how long a lease is valid, so a machine that dies while
+// holding one only blocks the others for a while
+const leaseDuration = 5 * time.Minute
+
+// leaseRemote returns the remote that holds the lease: origin, or the first remote
+func leaseRemote() string {
+	remotes := getRemotes()
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote
+		}
+	}
+	if len(remotes) == 0 {
+		return ""
+	}
+	return remotes[0]
+}
+
+// readLease returns the holder and expiry of the lease blob oid, fetching it
+// from remote if it isn't local yet
+func readLease(remote, oid string) (string, time.Time, bool) {
+	content, err := gitCommand("cat-file", "blob", oid).Output()
+	if err != nil {
+		if _, err := gitNetwork("fetch", remote, leaseRef); err != nil {
+			return "", time.Time{}, false
+		}
+		if content, err = gitCommand("cat-file", "blob", oid).Output(); err != nil {
+			return "", time.Time{}, false
+		}
+	}
+	host, expiry, ok := strings.Cut(strings.TrimSpace(string(content)), " ")
+	if !ok {
+		return "", time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return host, time.Unix(seconds, 0), true
+}
+
+// acquireLease takes the lease on remote for this machine. The push only
+// succeeds if the ref still has the value read before (--force-with-lease),
+// so two machines can't both take it. It returns the function releasing the
+// lease, or why the repo has to wait.
+func acquireLease(remote string) (func(), string) {
+	output, err := gitNetwork("ls-remote", remote, leaseRef)
+	if err != nil {
+		return nil, fmt.Sprintf(tr("lease on %s unavailable: %s"), remote, lastLine(output))
+	}
+	me, old := hostLabel(), ""
+	if fields := strings.Fields(output); len(fields) > 0 {
+		old = fields[0]
+		if host, until, ok := readLease(remote, old); ok && host != me && time.Now().Before(until) {
+			return nil, fmt.Sprintf(tr("%s holds the lease until %s"), host, until.Format("15:04:05"))
+		}
+	}
+
+	cmd := gitCommand("hash-object", "-w", "--stdin")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("%s %d\n", me, time.Now().Add(leaseDuration).Unix()))
+	blob, err := cmd.Output()
+	if err != nil {
+		return nil, tr("could not write the lease")
+	}
+	oid := strings.TrimSpace(string(blob))
+	if _, err := gitNetwork("push", "--force-with-lease="+leaseRef+":"+old, remote, oid+":"+leaseRef); err != nil {
+		return nil, tr("another machine took the lease")
+	}
+	return func() {
+		gitNetwork("push", "--force-with-lease="+leaseRef+":"+oid, remote, ":"+leaseRef)
+	}, ""
+}
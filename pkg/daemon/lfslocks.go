@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// lfsLock is an entry of git lfs locks --json
+type lfsLock struct {
+	Path  string `json:"path"`
+	Owner struct {
+		Name string `json:"name"`
+	} `json:"owner"`
+}
+
+// heldLFSLocks returns the changed LFS files that someone else has locked
+// (git lfs locks --verify), so they stay unstaged instead of overwriting
+// work guarded by the lock. Without git-lfs or a server that supports
+// locking nothing is held.
+func heldLFSLocks(repoPath string, changes []fileChange) []string {
+	var tracked []string
+	for path, filter := range filterAttributes(changePaths(changes)) {
+		if filter == "lfs" {
+			tracked = append(tracked, path)
+		}
+	}
+	if len(tracked) == 0 {
+		clearAlert(repoPath, "lfs-lock")
+		return nil
+	}
+
+	// Locks live on the LFS server; stdout only, so warnings don't break the JSON
+	netSlots <- struct{}{}
+	output, err := gitCommand(append(proxyOptions(), "lfs", "locks", "--verify", "--json")...).Output()
+	<-netSlots
+	if err != nil {
+		return nil
+	}
+	var locks struct {
+		Theirs []lfsLock `json:"theirs"`
+	}
+	if json.Unmarshal(output, &locks) != nil {
+		return nil
+	}
+	owners := map[string]string{}
+	for _, lock := range locks.Theirs {
+		owners[lock.Path] = lock.Owner.Name
+	}
+
+	var held, who []string
+	for _, path := range tracked {
+		if owner, ok := owners[path]; ok {
+			held = append(held, path)
+			who = append(who, path+" ("+owner+")")
+		}
+	}
+	if len(held) == 0 {
+		clearAlert(repoPath, "lfs-lock")
+		return nil
+	}
+	sort.Strings(who)
+	fmt.Printf(tr("  🔒 Leaving %s unstaged, locked by someone else in Git LFS\n"), describeCount(held))
+	raiseAlert(repoPath, "lfs-lock", fmt.Sprintf(tr("%s: changes to LFS files locked by others not committed: %s"), displayName(repoPath), strings.Join(who, ", ")))
+	return held
+}
@@ -0,0 +1,165 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git-air/pkg/gitair"
+)
+
+// machineBranch names this machine's branch in machine_branch mode
+func machineBranch() string {
+	return "machines/" + hostLabel()
+}
+
+// onMachineBranch moves the repo from the shared branch to this machine's
+// branch before committing, keeping uncommitted changes. Other branches are
+// left alone, so a feature branch checked out by hand is committed as usual.
+// Returns false if the switch failed and nothing should be committed.
+func onMachineBranch(repoPath string, settings RepoSettings) bool {
+	current, own := getCurrentBranch(), machineBranch()
+	if current != settings.sharedBranch() {
+		return true
+	}
+
+	args := []string{"switch", own}
+	if !runGit("rev-parse", "--verify", "--quiet", "refs/heads/"+own) {
+		args = []string{"switch", "-c", own}
+	}
+	if output, err := gitOutput(args...); err != nil {
+		fmt.Printf(tr("  ⚠️  %s: could not switch to %s: %s\n"), displayName(repoPath), own, lastLine(output))
+		raiseAlert(repoPath, "machine-branch", fmt.Sprintf(tr("%s: commits skipped, could not switch from %s to %s: %s"), displayName(repoPath), current, own, lastLine(output)))
+		return false
+	}
+	clearAlert(repoPath, "machine-branch")
+	fmt.Printf(tr("  🖥️  %s: switched from %s to %s\n"), displayName(repoPath), current, own)
+	return true
+}
+
+// machineRefs lists the remote-tracking refs merge-machines combines: every
+// machines/* branch of another machine and the shared branch, on all remotes
+func machineRefs(shared string) []string {
+	output, err := gitCommand("for-each-ref", "--format=%(refname:short)", "refs/remotes/").Output()
+	if err != nil {
+		return nil
+	}
+	own := machineBranch()
+	var refs []string
+	for _, ref := range strings.Fields(string(output)) {
+		_, branch, ok := strings.Cut(ref, "/")
+		if !ok || branch == own {
+			continue
+		}
+		if branch == shared || strings.HasPrefix(branch, "machines/") {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// runMergeMachines implements `git-air merge-machines <repo...>`: merge the
+// other machines' branches into this machine's and publish the result as the
+// shared branch
+func runMergeMachines(args []string) int {
+	fs := flag.NewFlagSet("merge-machines", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air merge-machines <repo...>"))
+		fmt.Println(tr("\nFetches every remote, merges the machines/* branches of the other machines"))
+		fmt.Printf(tr("and the shared branch into %s, then pushes it and\n"), machineBranch())
+		fmt.Println(tr("fast-forwards the shared branch on the remotes. Stops at the first conflict."))
+	}
+	repos := parseInterspersed(fs, args)
+	if len(repos) == 0 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+
+	status := 0
+	for _, repoPath := range repos {
+		if !mergeMachines(repoPath) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// mergeMachines combines the machine branches of one repo for runMergeMachines
+func mergeMachines(repoPath string) bool {
+	if _, err := os.Stat(filepath.Join(repoPath, ".git")); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s is not a Git repository\n"), repoPath)
+		return false
+	}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+		return false
+	}
+	defer leave()
+	unlock, busy := claimRepo(repoPath)
+	if busy != "" {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s, try again later\n"), repoPath, busy)
+		return false
+	}
+	defer unlock()
+
+	settings := settingsFor(repoPath)
+	if settings.MachineBranch == nil || !*settings.MachineBranch {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s does not use machine_branch\n"), repoPath)
+		return false
+	}
+	shared, own := settings.sharedBranch(), machineBranch()
+	if !onMachineBranch(repoPath, settings) {
+		return false
+	}
+	if current := getCurrentBranch(); current != own {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s is on %s, not %s\n"), repoPath, current, own)
+		return false
+	}
+
+	remotes := getRemotes()
+	for _, remote := range remotes {
+		if output, err := gitNetwork("fetch", remote); err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: fetching %s: %s\n"), remote, lastLine(output))
+			return false
+		}
+	}
+
+	for _, ref := range machineRefs(shared) {
+		if runGit("merge-base", "--is-ancestor", ref, "HEAD") {
+			continue
+		}
+		message := withTrailers("Merge "+ref+" into "+own, []string{"Git-Air-Machines: merge"})
+		if output, err := gitOutput("merge", "--no-edit", "-m", message, ref); err != nil {
+			runGit("merge", "--abort")
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %s: merging %s: %s\n"), repoPath, ref, lastLine(output))
+			fmt.Fprintf(os.Stderr, tr("   Merge it by hand with git merge %s, then run merge-machines again\n"), ref)
+			return false
+		}
+		fmt.Printf(tr("  🔀 %s: merged %s\n"), displayName(repoPath), ref)
+	}
+
+	ok := true
+	for _, remote := range remotes {
+		for _, target := range []string{own, shared} {
+			if output, err := gitNetwork("push", remote, "HEAD:refs/heads/"+target); err != nil {
+				fmt.Fprintf(os.Stderr, tr("❌ Error: pushing %s to %s: %s\n"), target, remote, lastLine(output))
+				ok = false
+				continue
+			}
+			publish(gitair.Event{Type: gitair.Pushed, Remote: remote})
+		}
+	}
+	if ok {
+		fmt.Printf(tr("  ✓ %s: %s and %s are up to date on %d remote(s)\n"), displayName(repoPath), own, shared, len(remotes))
+	}
+	return ok
+}
@@ -0,0 +1,1582 @@
+// Package daemon is the git-air service: the flags and subcommands, the
+// config, and the loop that commits, pushes and pulls every repository it
+// finds. cmd/git-air runs it through Main; other programs can sync with its
+// rules through Syncer after LoadConfig, and follow Events.
+package daemon
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+var (
+	forceMonorepo bool
+	intervalMins  string
+	confirmMode   bool
+	configPath    string
+	lowPriority   bool
+	maxParallel   int
+	watchFlag     bool
+	workersFlag   int
+	daemonFlag    bool
+	pidFileFlag   string
+	logFileFlag   string
+
+	stdin = bufio.NewReader(os.Stdin)
+)
+
+func init() {
+	flag.BoolVar(&forceMonorepo, "mr", false, "Force monorepo mode (auto-detects if not set)")
+	flag.BoolVar(&forceMonorepo, "monorepo", false, "Force monorepo mode (auto-detects if not set)")
+	flag.StringVar(&intervalMins, "i", "0.5", "Check interval in minutes or as a duration (45s, 2m30s, 4h)")
+	flag.StringVar(&intervalMins, "interval", "0.5", "Check interval in minutes or as a duration (45s, 2m30s, 4h)")
+	flag.StringVar(&configPath, "c", "", "Path to config file (default: ./git-air.json or .yaml, then ~/.config/git-air/)")
+	flag.StringVar(&configPath, "config", "", "Path to config file (default: ./git-air.json or .yaml, then ~/.config/git-air/)")
+	flag.BoolVar(&confirmMode, "confirm", false, "Ask before each commit (approve, edit message, or skip)")
+	flag.BoolVar(&lowPriority, "low-priority", false, "Run git with reduced CPU and I/O priority")
+	flag.IntVar(&maxParallel, "max-parallel-net", 4, "Maximum simultaneous pushes, fetches and pulls")
+	flag.IntVar(&workersFlag, "workers", 1, "Repos checked and fetched at the same time")
+	flag.BoolVar(&watchFlag, "watch", false, "Sync repos as their files change, the interval only as a fallback")
+	flag.BoolVar(&daemonFlag, "daemon", false, "Run in the background, writing a PID file and a log")
+	flag.StringVar(&pidFileFlag, "pid-file", "", "PID file for --daemon (default: git-air.pid in the state directory)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Show what one cycle would commit and push, without changing anything")
+	flag.BoolVar(&onceMode, "once", false, "Run a single cycle and exit with its status, for cron and scripts")
+	flag.BoolVar(&forceInstance, "force", false, "Run even if another git-air already syncs this directory")
+	flag.StringVar(&apiListen, "api", "", "Serve the JSON REST API on this address, e.g. 127.0.0.1:8090")
+	flag.StringVar(&logFileFlag, "log-file", "", "Log file for --daemon (default: git-air.log in the state directory)")
+
+	flag.Usage = showHelp
+}
+
+func showHelp() {
+	fmt.Println(tr("🚀 Git Air - Automatic Git synchronization service"))
+	fmt.Println(tr("\nUSAGE:"))
+	fmt.Println(tr("  git-air [options]"))
+	fmt.Println(tr("  git-air <command> [args]"))
+	fmt.Println(tr("\nCOMMANDS:"))
+	fmt.Println(tr("  suggest-ignore <repo>   Propose .gitignore entries for untracked files"))
+	fmt.Println(tr("                          (--ai to ask the configured AI provider too)"))
+	fmt.Println(tr("  adopt <dir>             Turn a plain directory into a managed repo"))
+	fmt.Println(tr("                          (--remote <url> to add and push to origin)"))
+	fmt.Println(tr("  changelog <repo>        Markdown changelog of a period (--since <date>),"))
+	fmt.Println(tr("                          auto-commits summarized by the AI provider"))
+	fmt.Println(tr("  report                  Export activity and errors from the state store"))
+	fmt.Println(tr("                          (--from, --to, --format csv|json, --events)"))
+	fmt.Println(tr("  stats [repo...]         Auto vs manual commits, commit size, busiest"))
+	fmt.Println(tr("                          hours and push success rate (--days N)"))
+	fmt.Println(tr("  activity [repo...]      Commit heatmap and daily line totals from the"))
+	fmt.Println(tr("                          state store (--weeks N, --days N, --html <file>)"))
+	fmt.Println(tr("  backup [repo...]        Write verified incremental git bundles now and"))
+	fmt.Println(tr("                          upload them if backup.s3 is configured"))
+	fmt.Println(tr("                          (--dir <path>, default backup.dir from config)"))
+	fmt.Println(tr("  pull <repo...>          Pull now, also repos in review mode"))
+	fmt.Println(tr("  review <repo...>        Commit the snapshots queued in capture queue mode"))
+	fmt.Println(tr("                          in batches, with your own messages"))
+	fmt.Println(tr("  restore <repo> --at <time> [path...]"))
+	fmt.Println(tr("                          Restore files as they were at a time, from commits"))
+	fmt.Println(tr("                          and snapshots (--to <dir> writes them elsewhere)"))
+	fmt.Println(tr("  merge-machines <repo>   Merge the machines/* branches and update the"))
+	fmt.Println(tr("                          shared branch (machine_branch mode)"))
+	fmt.Println(tr("  doctor [repo...]        Check that every remote works without a password"))
+	fmt.Println(tr("                          prompt (SSH agent keys, cached HTTPS credentials)"))
+	fmt.Println(tr("  bench [repo...]         Time discovery, git status and a dry-run cycle"))
+	fmt.Println(tr("                          per repo to find slow repos (--network)"))
+	fmt.Println(tr("  known-hosts [repo...]   Add missing SSH host keys of the remotes after"))
+	fmt.Println(tr("                          you compared and confirmed their fingerprints"))
+	fmt.Println(tr("  status [dir]            Show what the running git-air syncing the"))
+	fmt.Println(tr("                          directory is doing, per repo (--json)"))
+	fmt.Println(tr("  trigger [file...]       Sync the repos of files just saved in an editor,"))
+	fmt.Println(tr("                          or start a cycle now without files"))
+	fmt.Println(tr("  stop                    Stop the git-air started with --daemon"))
+	fmt.Println(tr("  restart                 Restart it with the same directory and options"))
+	fmt.Println(tr("  pause [dir]             Suspend the running git-air syncing the directory,"))
+	fmt.Println(tr("                          or only the repo the directory is in"))
+	fmt.Println(tr("  resume [dir]            Let it sync again"))
+	fmt.Println(tr("  tray [options]          Run with a system tray icon: status, pause,"))
+	fmt.Println(tr("                          sync now and recent events (takes the options below)"))
+	fmt.Println(tr("  metered [on|off|auto]   Show or set metered mode: commit only, pushes"))
+	fmt.Println(tr("                          wait for an unmetered connection"))
+	fmt.Println(tr("\nOPTIONS:"))
+	fmt.Println(tr("  -h, --help              Show this help screen"))
+	fmt.Println(tr("  -i, --interval <time>   Check interval in minutes or as a duration"))
+	fmt.Println(tr("                          Examples: 0.5, 1, 10, 45s, 2m30s, 4h"))
+	fmt.Println(tr("                          Default: 0.5 (30 seconds)"))
+	fmt.Println(tr("  -mr, --monorepo         Force monorepo mode"))
+	fmt.Println(tr("                          (auto-detects if not set)"))
+	fmt.Println(tr("  -c, --config <file>     Config file, JSON or YAML (default: ./git-air.json"))
+	fmt.Println(tr("                          or .yaml, then the same in ~/.config/git-air/)"))
+	fmt.Println(tr("  --confirm               Ask before each commit: approve, edit"))
+	fmt.Println(tr("                          the message, or skip the repo this cycle"))
+	fmt.Println(tr("  --low-priority          Run git with reduced CPU and I/O priority"))
+	fmt.Println(tr("                          (also low_priority in the config)"))
+	fmt.Println(tr("  --max-parallel-net <n>  Maximum simultaneous pushes, fetches and"))
+	fmt.Println(tr("                          pulls (default 4)"))
+	fmt.Println(tr("  --workers <n>           Repos whose status is checked and whose remotes"))
+	fmt.Println(tr("                          are fetched at the same time (default 1)"))
+	fmt.Println(tr("  --watch                 Sync repos as soon as files change,"))
+	fmt.Println(tr("                          checking every interval only as a fallback"))
+	fmt.Println(tr("  --dry-run               Show which repos would be committed, with which"))
+	fmt.Println(tr("                          message, and pushed where; changes nothing"))
+	fmt.Println(tr("  --once                  Run one cycle (commit, push, pull) and exit:"))
+	fmt.Println(tr("                          0 in sync, 1 something failed, 2 needs attention"))
+	fmt.Println(tr("  --daemon                Run in the background; output goes to the log"))
+	fmt.Println(tr("  --force                 Run even if another git-air syncs this directory"))
+	fmt.Println(tr("  --pid-file <file>       PID file of --daemon (default: git-air.pid in"))
+	fmt.Println(tr("                          ~/.local/state/git-air, or pid_file)"))
+	fmt.Println(tr("  --log-file <file>       Log of --daemon (default: git-air.log next to"))
+	fmt.Println(tr("                          the PID file's default, or log_file)"))
+	fmt.Println(tr("  --api <addr>            Serve a JSON REST API on the address, e.g."))
+	fmt.Println(tr("                          127.0.0.1:8090: status, trigger, pause and"))
+	fmt.Println(tr("                          recent commits (api.token_env for other hosts)"))
+	fmt.Println(tr("\nEXAMPLES:"))
+	fmt.Println(tr("  git-air                 # Run with default 30 second interval"))
+	fmt.Println(tr("  git-air -i 1            # Check every 1 minute"))
+	fmt.Println(tr("  git-air -i 5 -mr        # Check every 5 minutes, force monorepo"))
+	fmt.Println(tr("  git-air --interval 10   # Check every 10 minutes"))
+	fmt.Println(tr("  git-air -i 4h           # Check every 4 hours"))
+	fmt.Println(tr("  git-air --confirm       # Review every commit before it is made"))
+	fmt.Println(tr("\nDESCRIPTION:"))
+	fmt.Println(tr("  Automatically discovers and synchronizes all Git repositories"))
+	fmt.Println(tr("  in the current directory and subdirectories."))
+	fmt.Println(tr("\n  Features:"))
+	fmt.Println(tr("  • Auto-commits changes with timestamp"))
+	fmt.Println(tr("  • Pushes to ALL configured remotes"))
+	fmt.Println(tr("  • Pulls updates for inter-project communication"))
+	fmt.Println(tr("  • Handles monorepos with submodules"))
+	fmt.Println()
+}
+
+// parseInterval reads the check interval: minutes ("0.5", "10") or a Go
+// duration ("45s", "2m30s", "4h")
+func parseInterval(intervalStr string) (time.Duration, error) {
+	interval, err := parseDuration(intervalStr, time.Minute)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval: %v", err)
+	}
+
+	if interval <= 0 {
+		return 0, fmt.Errorf("interval must be positive, got: %s", intervalStr)
+	}
+
+	return interval, nil
+}
+
+// intervalWarning says what to expect from an unusually short or long
+// check interval, "" for the usual ones
+func intervalWarning(interval time.Duration) string {
+	switch {
+	case interval < 30*time.Second:
+		return tr("intervals under 30 seconds keep git status running in every repo almost constantly")
+	case interval > 24*time.Hour:
+		return tr("with intervals over a day, changes can stay uncommitted and unpushed for days")
+	}
+	return ""
+}
+
+// subcommands maps command names to their implementations, which return the exit code
+var subcommands = map[string]func(args []string) int{
+	"suggest-ignore": runSuggestIgnore,
+	"adopt":          runAdopt,
+	"changelog":      runChangelog,
+	"report":         runReport,
+	"stats":          runStats,
+	"activity":       runActivity,
+	"backup":         runBackup,
+	"metered":        runMetered,
+	"pull":           runPull,
+	"review":         runReview,
+	"restore":        runRestore,
+	"merge-machines": runMergeMachines,
+	"doctor":         runDoctor,
+	"bench":          runBench,
+	"known-hosts":    runKnownHosts,
+	"trigger":        runTrigger,
+	"tray":           runTray,
+	"stop":           runStop,
+	"restart":        runRestart,
+	"status":         runStatus,
+	"pause":          runPause,
+	"resume":         runResume,
+}
+
+// flagSet reports whether any of the named flags was given on the command line
+func flagSet(names ...string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		for _, name := range names {
+			if f.Name == name {
+				set = true
+			}
+		}
+	})
+	return set
+}
+
+// parseInterspersed parses subcommand flags that may come before or after
+// the positional arguments (git-air changelog <repo> --since ...) and returns the
+// positionals, with Windows paths given in WSL turned into /mnt/<drive> paths
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, normalizePath(fs.Arg(0)))
+		args = fs.Args()[1:]
+	}
+}
+
+// Main runs git-air with the command line in os.Args: a subcommand, or the
+// daemon with its flags. It returns only if the daemon does; cmd/git-air
+// is nothing but a call to it.
+func Main() {
+	configurePlatformGit()
+	setLocale("")
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
+	flag.Parse()
+	runDaemon()
+}
+
+// runDaemon discovers the repos and syncs them until the process is stopped
+func runDaemon() {
+	cfg, cfgFile, err := loadConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		os.Exit(1)
+	}
+	config = cfg
+	setLocale(config.Locale)
+
+	// One instance per tree, checked before --daemon detaches so the refusal
+	// shows up in the terminal. A dry run changes nothing and may run beside one.
+	onceMode = onceMode || dryRun
+	if !forceInstance && !dryRun {
+		if err := lockTree(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			fmt.Fprintln(os.Stderr, tr("   Stop it first, or start with --force to run anyway"))
+			os.Exit(1)
+		}
+	}
+
+	if os.Getenv(daemonEnv) != "" {
+		handleStopSignals()
+		removePIDFileOnExit(pidFilePath(pidFileFlag))
+	} else if daemonFlag {
+		if treeUnlock != nil {
+			treeUnlock() // The detached process takes it over
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			os.Exit(1)
+		}
+		record := daemonRecord{Dir: dir, Args: daemonArgs(os.Args[1:]), Log: logFilePath(logFileFlag)}
+		os.Exit(startDaemon(record, pidFilePath(pidFileFlag)))
+	} else {
+		handleStopSignals()
+	}
+
+	// Parse and validate interval; -i wins over the config file
+	checkInterval, err := parseInterval(intervalMins)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n\n"), err)
+		showHelp()
+		os.Exit(1)
+	}
+	if flagSet("i", "interval") {
+		config.Interval = nil // Nor does it hold back any repo
+	} else if config.Interval != nil {
+		checkInterval = time.Duration(*config.Interval)
+	}
+	cycleInterval = checkInterval
+	forceMonorepo = forceMonorepo || config.Monorepo
+	watchMode = watchFlag || config.Watch
+
+	if maxParallel < 1 {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: --max-parallel-net must be at least 1, got: %d\n\n"), maxParallel)
+		showHelp()
+		os.Exit(1)
+	}
+	netSlots = make(chan struct{}, maxParallel)
+	workers = workersFlag
+	if !flagSet("workers") && config.Workers > 0 {
+		workers = config.Workers
+	}
+	if workers < 1 {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: --workers must be at least 1, got: %d\n\n"), workers)
+		showHelp()
+		os.Exit(1)
+	}
+
+	fmt.Println(tr("🚀 Git Air - Auto sync all Git repos"))
+	fmt.Println(tr("📡 Inter-project communication via Git synchronization"))
+	fmt.Println(tr("📚 Supports monorepos and multi-repos"))
+	fmt.Printf(tr("⏱️  Check interval: %s\n"), formatInterval(checkInterval))
+	if warning := intervalWarning(checkInterval); warning != "" {
+		fmt.Printf(tr("⚠️  Note: %s\n"), warning)
+	}
+	if forceMonorepo {
+		fmt.Println(tr("🔧 Monorepo mode: FORCED"))
+	} else {
+		fmt.Println(tr("🔧 Monorepo mode: AUTO-DETECT"))
+	}
+	if cfgFile != "" {
+		fmt.Printf(tr("⚙️  Config: %s\n"), cfgFile)
+	}
+	if dryRun {
+		confirmMode = false
+		fmt.Println(tr("🧪 Dry run: showing what one cycle would do, nothing is changed"))
+	} else if confirmMode {
+		fmt.Println(tr("🙋 Confirm mode: ON (each commit needs approval)"))
+	}
+	if watchMode {
+		fmt.Printf(tr("👀 Watch mode: ON (syncing as files change, full check every %s)\n"), formatInterval(watchFallbackInterval))
+	}
+	if lowPriority || config.LowPriority {
+		if err := lowerPriority(); err != nil {
+			fmt.Printf(tr("⚠️  Low priority: could not be set: %v\n"), err)
+		} else {
+			fmt.Println(tr("🐢 Low priority: ON (git runs niced)"))
+		}
+	}
+	fmt.Println()
+
+	// Turn configured plain directories into repos before discovery
+	var adopted []string
+	if !dryRun {
+		adopted = adoptConfiguredDirs()
+	}
+
+	// Find all git repos in current directory and subdirs
+	repos, err := findGitRepos(".")
+	if err != nil {
+		log.Fatalf("❌ Error finding repositories: %v\n", err)
+	}
+	repos = claimRepos(excludeConfigured(excludeCloudSynced(appendMissing(repos, adopted))))
+
+	if len(repos) == 0 {
+		fmt.Println(tr("⚠️  No Git repositories found in current directory"))
+		fmt.Println(tr("💡 Make sure you're in a directory containing Git repositories"))
+		os.Exit(0)
+	}
+
+	fmt.Printf(tr("Found %d Git repositories\n"), len(repos))
+	for _, repo := range repos {
+		repoType := "repo"
+		if forceMonorepo || isMonorepo(repo) {
+			repoType = "MONOREPO"
+		}
+		fmt.Printf("  📁 %s [%s]\n", repo, repoType)
+	}
+	warnCrossingWSL(repos)
+	fmt.Println()
+
+	if onceMode {
+		events.Subscribe(countFailures)
+	} else {
+		startServer(repos)
+		startTrigger()
+		startControl()
+		startAPI()
+		handlePauseSignals()
+		watchRepos(repos)
+		startTray(len(repos))
+	}
+
+	// Calculate pull interval (every minute or every checkInterval, whichever is longer)
+	pullInterval := time.Minute
+	if checkInterval > pullInterval {
+		pullInterval = checkInterval
+	}
+
+	// Machines started together (at boot, by a fleet rollout) spread out right away
+	if delay := jitter(); delay > 0 && !dryRun {
+		fmt.Printf(tr("🎲 Starting in %.0f seconds (jitter)\n\n"), delay.Seconds())
+		select {
+		case <-stopRequested:
+		case <-time.After(delay):
+		}
+	}
+
+	// Main loop
+	nextPull := time.Now().Add(pullInterval + jitter())
+	lastDiscovery := time.Now()
+	iteration := 0
+
+	for {
+		if stopping() {
+			stopNow()
+		}
+
+		// Paused from the tray or by git-air pause: only "Sync now", resuming
+		// or stopping ends the wait
+		if paused.Load() {
+			updateTray(len(repos))
+			noteCycle(repos, iteration, time.Now().Add(checkInterval))
+			select {
+			case <-syncNow:
+			case <-time.After(checkInterval):
+			}
+			continue
+		}
+
+		iteration++
+		runTotals.cycles = iteration
+		noteCycle(repos, iteration, time.Time{})
+		fmt.Printf(tr("🔄 Check cycle #%d\n"), iteration)
+		sleepFor := checkPower(checkInterval)
+		checkMetered()
+
+		// Pick up repos cloned or created since the last scan
+		if time.Since(lastDiscovery) >= rediscoverInterval {
+			if found, err := findGitRepos("."); err == nil {
+				repos = rediscovered(repos, claimRepos(excludeConfigured(excludeCloudSynced(appendMissing(found, adopted)))))
+				warnCrossingWSL(repos)
+				watchRepos(repos)
+			}
+			lastDiscovery = time.Now()
+		}
+
+		// Missing keys and credentials show up before a push hangs on them
+		checkCredentials(repos)
+
+		// Auto commit and push changes, the most important repos first and
+		// dependencies before the repos using them
+		repos = byPriority(repos)
+		changesFound := false
+		if order := syncOrder(repos, false); config.Stagger && !onceMode && len(order) > 1 {
+			changesFound, sleepFor = processStaggered(repos, order, sleepFor)
+		} else {
+			if workers > 1 {
+				order = scanRepos(order)
+			}
+			for _, repo := range order {
+				if interrupted() {
+					break
+				}
+				if syncRepo(repo) {
+					changesFound = true
+				}
+			}
+		}
+
+		if !changesFound {
+			fmt.Println(tr("  ✓ No changes detected"))
+		}
+
+		if dryRun {
+			printAlerts()
+			fmt.Println(tr("\n🧪 Dry run: no repository was changed"))
+			os.Exit(0)
+		}
+		if stopping() {
+			printAlerts()
+			stopNow()
+		}
+		if paused.Load() {
+			continue // Paused mid-cycle: no pushes, pulls or backups either
+		}
+
+		pushDeferred()
+
+		// Pull from all repos at pull interval
+		if networkHeld == "" && (onceMode || !time.Now().Before(nextPull)) {
+			fmt.Println(tr("\n📡 Checking for inter-project updates..."))
+			order := syncOrder(repos, true)
+			if workers > 1 {
+				prefetchRemotes(order)
+			}
+			for _, repo := range order {
+				if interrupted() {
+					break
+				}
+				pullUpdates(repo)
+			}
+			prefetched = map[string]fetchResult{}
+			nextPull = time.Now().Add(pullInterval + jitter())
+		}
+		if stopping() {
+			printAlerts()
+			stopNow()
+		}
+		if paused.Load() {
+			continue // Paused mid-cycle: no pushes, pulls or backups either
+		}
+
+		runBackups(repos)
+
+		printAlerts()
+		checkDigest()
+		if onceMode {
+			os.Exit(onceStatus())
+		}
+		updateTray(len(repos))
+
+		sleepFor += jitter()
+		noteCycle(repos, iteration, time.Now().Add(sleepFor))
+		fmt.Printf(tr("\n💤 Sleeping for %s...\n\n"), formatInterval(sleepFor.Round(time.Second)))
+		sleepWatchingImmediate(repos, sleepFor)
+	}
+}
+
+// rediscovered reports repos that appeared or disappeared since the last scan
+// and returns the new list
+func rediscovered(repos, found []string) []string {
+	known := map[string]bool{}
+	for _, repo := range repos {
+		known[repo] = true
+	}
+	for _, repo := range found {
+		if !known[repo] {
+			fmt.Printf(tr("  📁 New repository: %s\n"), repo)
+		}
+		delete(known, repo)
+	}
+	for _, repo := range repos {
+		if known[repo] {
+			fmt.Printf(tr("  📁 Repository gone: %s\n"), repo)
+		}
+	}
+	return found
+}
+
+// appendMissing adds the paths not yet in repos, e.g. adopted dirs outside the scan root
+func appendMissing(repos, paths []string) []string {
+	for _, path := range paths {
+		found := false
+		for _, repo := range repos {
+			if filepath.Clean(repo) == path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			repos = append(repos, path)
+		}
+	}
+	return repos
+}
+
+// byPriority returns the repos with the highest priority setting first,
+// keeping the discovery order among equal priorities
+func byPriority(repos []string) []string {
+	ordered := append([]string(nil), repos...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return settingsFor(ordered[i]).priority() > settingsFor(ordered[j]).priority()
+	})
+	return ordered
+}
+
+// processRepo handles one git repository, returns true if changes were committed
+func processRepo(repoPath string, forceMonorepo bool) bool {
+	if repoPaused(repoPath) {
+		fmt.Printf(tr("⏸️  %s: paused, skipping\n"), filepath.Base(repoPath))
+		return false
+	}
+
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Printf(tr("  ❌ Error changing to %s: %v\n"), repoPath, err)
+		return false
+	}
+	defer leave()
+
+	// Stay out of the way of git commands run by the user or an IDE. A dry
+	// run only looks, so it takes no lock.
+	if dryRun {
+		if busy := gitBusy(repoPath); busy != "" {
+			fmt.Printf(tr("⏳ %s: %s, deferring to next cycle\n"), filepath.Base(repoPath), busy)
+			return false
+		}
+	} else {
+		unlock, busy := claimRepo(repoPath)
+		trackConflicts(busy)
+		if busy != "" {
+			fmt.Printf(tr("⏳ %s: %s, deferring to next cycle\n"), filepath.Base(repoPath), busy)
+			return false
+		}
+		defer unlock()
+	}
+
+	settings := settingsFor(repoPath)
+	if !settings.autoCommit() {
+		return false // Only receives pulls
+	}
+	// A repo with a longer interval of its own sits out the cycles in between
+	if syncTrigger == "cycle" && !repoDue(repoPath, settings) {
+		return false
+	}
+	// Watched repos are synced as files change; the cycle is only a fallback
+	if syncTrigger == "cycle" && !watchFallbackDue(repoPath) {
+		return false
+	}
+
+	// Network file systems are slow to scan and shared with other clients
+	networkFS := safeMode(repoPath, settings)
+	if networkFS != "" && !safeModeDue(repoPath) {
+		return false
+	}
+
+	// Determine if this is a monorepo
+	isMonorepoMode := forceMonorepo || isMonorepo(repoDir)
+
+	// For monorepos: sync submodules FIRST
+	var heldBumps []string
+	if isMonorepoMode {
+		var ok bool
+		if heldBumps, ok = syncSubmodules(repoPath, settings); !ok {
+			fmt.Printf(tr("  ❌ Skipping %s - submodule sync failed\n"), filepath.Base(repoPath))
+			return false
+		}
+	}
+
+	// Encrypt edited secrets so the ciphertext shows up as a change
+	if !dryRun {
+		syncEncrypted(repoPath, settings.Encrypt)
+	}
+
+	// Watchman knows whether anything changed without git scanning the tree
+	if settings.Watchman != nil && *settings.Watchman && !watchmanChanged(repoPath) {
+		return false
+	}
+
+	// Check if there are changes AFTER submodule sync. One status run
+	// serves every decision below, which matters in huge repos.
+	status, err := gitStatus()
+	if err != nil {
+		publish(errorEvent("commit", "", err.Error()))
+		return false
+	}
+	if settings.untrackedPolicy() == "report" {
+		reportUntracked(repoPath, settings, status)
+	}
+	changes := changedFiles(settings, status)
+	var lineEndings []string
+	if settings.ignoreLineEndings() {
+		lineEndings = lineEndingOnly(changes)
+		changes = withoutPaths(changes, lineEndings)
+	}
+	if len(changes) == 0 {
+		watchmanSettled(repoPath)
+		return false // No changes to commit
+	}
+	held := append(heldDeletions(repoPath, changes, settings), heldBumps...)
+	held = append(held, heldLFSLocks(repoPath, changes)...)
+	if changes = withoutPaths(changes, held); len(changes) == 0 {
+		return false // Only deletions, pointer bumps or locked files that are on hold
+	}
+	if len(triggeringChanges(changes, settings)) == 0 {
+		watchmanSettled(repoPath)
+		return false // Only churn such as whitespace
+	}
+
+	repoName := filepath.Base(repoPath)
+
+	// Files that are still being written get another cycle to settle,
+	// unless an immediate file changed
+	if urgent := immediatePaths(changes, settings.Immediate); len(urgent) > 0 {
+		fmt.Printf(tr("⚡ %s: %s changed, syncing immediately\n"), repoName, describeCount(urgent))
+	} else if busy := filesInFlux(changePaths(changes), settings.settleTime()); len(busy) > 0 {
+		if !dryRun {
+			fmt.Printf(tr("⏳ %s: %s still being written, deferring to next cycle\n"), repoName, busy[0])
+			return false
+		}
+		fmt.Printf(tr("⏳ %s: %s still being written, would wait for it to settle\n"), repoName, busy[0])
+	}
+
+	// A flood of new files is almost always a build or dependency dir nobody meant to commit
+	if dir, count := largestUntrackedDir(changes, settings.untrackedLimit()); dir != "" {
+		hint := tr("add the generated paths to .gitignore")
+		if dir != "." {
+			hint = fmt.Sprintf(tr("add /%s to .gitignore"), dir)
+		}
+		fmt.Printf(tr("📦 %s: %d new untracked files in %s - paused, %s (or raise untracked_limit)\n"), repoName, count, dir, hint)
+		raiseAlert(repoPath, "untracked", fmt.Sprintf(tr("%s: paused, %d new untracked files in %s - %s"), displayName(repoPath), count, dir, hint))
+		return false
+	}
+	clearAlert(repoPath, "untracked")
+
+	repoType := ""
+	if isMonorepoMode {
+		repoType = " [MONOREPO]"
+	}
+	publish(gitair.Event{Type: gitair.RepoDirty, Changes: changes})
+	if dryRun {
+		fmt.Printf(tr("📝 %s%s: Changes found (dry run)\n"), repoName, repoType)
+	} else {
+		fmt.Printf(tr("📝 %s%s: Auto committing changes...\n"), repoName, repoType)
+	}
+	if private := neverCommitted(settings, status); len(private) > 0 {
+		fmt.Printf(tr("  🙈 Leaving %s unstaged (never_commit)\n"), describeCount(private))
+	}
+
+	if !dryRun {
+		ensureIdentity(settings.Identity)
+	}
+	if !hasIdentity() && !(dryRun && settings.Identity.Name != "" && settings.Identity.Email != "") {
+		fmt.Printf(tr("  ⚠️  Skipping %s - no git identity (user.name/user.email) configured\n"), repoName)
+		raiseAlert(repoPath, "identity", fmt.Sprintf(tr("%s: commits skipped, no git identity. Run git config user.name/user.email or set identity in the git-air config"), displayName(repoPath)))
+		return false
+	}
+	clearAlert(repoPath, "identity")
+
+	// git-crypt/transcrypt files would be committed as plaintext while locked
+	encrypted := encryptedPaths(changePaths(changes))
+	if tool := lockedCryptTool(encrypted); tool != "" {
+		fmt.Printf(tr("  🔒 Skipping %s - %s is locked, unlock it to commit encrypted files\n"), repoName, tool)
+		raiseAlert(repoPath, "crypt", fmt.Sprintf(tr("%s: commits skipped, %s is locked"), displayName(repoPath), tool))
+		return false
+	}
+	clearAlert(repoPath, "crypt")
+
+	// Each machine commits to its own branch in machine_branch mode
+	if settings.MachineBranch != nil && *settings.MachineBranch && !dryRun && !onMachineBranch(repoPath, settings) {
+		return false
+	}
+
+	// With remote_lease only one machine at a time commits and pushes; the
+	// holder first takes in whatever the previous one pushed
+	if settings.RemoteLease != nil && *settings.RemoteLease && networkHeld == "" && !dryRun {
+		if remote := leaseRemote(); remote != "" {
+			release, busy := acquireLease(remote)
+			if busy != "" {
+				fmt.Printf(tr("  ⏳ Skipping %s - %s, deferring to next cycle\n"), repoName, busy)
+				return false
+			}
+			defer release()
+			pullFromRemotes(repoPath, settings, false)
+		}
+	}
+
+	// Locks on network file systems may not reach other clients: look for
+	// their git commands once more just before touching the index
+	if networkFS != "" {
+		if busy := gitBusy(repoPath); busy != "" {
+			fmt.Printf(tr("  ⏳ Skipping %s - %s, deferring to next cycle\n"), repoName, busy)
+			return false
+		}
+	}
+
+	// A locked signing agent would make git commit prompt or fail
+	var commitOptions []string
+	if signingEnabled() {
+		if problem := signingProblem(); problem != "" {
+			if settings.signingPolicy() == "defer" {
+				fmt.Printf(tr("  🔏 Skipping %s - commit signing unavailable (%s), deferring to next cycle\n"), repoName, problem)
+				raiseAlert(repoPath, "signing", fmt.Sprintf(tr("%s: commits deferred, signing unavailable: %s - unlock the agent or set signing_unavailable to unsigned"), displayName(repoPath), problem))
+				return false
+			}
+			fmt.Printf(tr("  🔏 Commit signing unavailable (%s), committing unsigned\n"), problem)
+			raiseAlert(repoPath, "signing", fmt.Sprintf(tr("%s: committing unsigned, signing unavailable: %s"), displayName(repoPath), problem))
+			commitOptions = append(commitOptions, "--no-gpg-sign")
+		} else {
+			clearAlert(repoPath, "signing")
+		}
+	}
+
+	if reason := policyDenial(repoPath, settings, "commit", changePaths(changes)); reason != "" {
+		fmt.Printf(tr("  🚦 Skipping %s - policy: %s, deferring to next cycle\n"), repoName, reason)
+		return false
+	}
+
+	if dryRun {
+		rules, _ := settings.commitlintRules(settings.aiMessages())
+		message := defaultMessage(settings, rules, repoName, changes, isMonorepoMode)
+		if settings.aiMessages() {
+			message += tr(" (or one written by the AI provider)")
+		}
+		showDryRunCommit(repoName, changes, message, settings.captureMode())
+		if settings.captureMode() == "commit" {
+			pushToAllRemotes(repoPath)
+		}
+		return true
+	}
+
+	switch settings.captureMode() {
+	case "queue":
+		captureQueue(repoPath, settings, append(held, lineEndings...)...)
+		return false
+	case "stash":
+		captureStash(repoPath, settings, append(held, lineEndings...)...)
+		return false
+	case "snapshot":
+		captureSnapshot(repoPath, settings, append(held, lineEndings...)...)
+		return false
+	}
+
+	// Auto commit with monorepo-aware message
+	if !stageChanges(settings, append(held, lineEndings...)...) {
+		fmt.Printf(tr("  ❌ Error staging changes in %s\n"), repoName)
+		return false
+	}
+	if plain := plaintextBlobs("", encrypted); len(plain) > 0 {
+		runGit("reset", "-q")
+		fmt.Printf(tr("  🔓 Skipping %s - %s would be committed unencrypted\n"), repoName, describeCount(plain))
+		raiseAlert(repoPath, "crypt", fmt.Sprintf(tr("%s: commits skipped, %s would be committed unencrypted"), displayName(repoPath), describeCount(plain)))
+		return false
+	}
+
+	// Messages follow the repo's commitlint rules; AI-written ones fall back to the default
+	useAI := settings.aiMessages()
+	rules, err := settings.commitlintRules(useAI)
+	if err != nil {
+		fmt.Printf(tr("  ⚠️  %s: commitlint rules ignored: %v\n"), repoName, err)
+	}
+	commitMsg := defaultMessage(settings, rules, repoName, changes, isMonorepoMode)
+	aiWritten := false
+	if useAI {
+		if message := aiCommitMessage(repoName, rules, settings); message != "" {
+			commitMsg, aiWritten = message, true
+		}
+	}
+
+	if confirmMode {
+		var ok bool
+		commitMsg, ok = confirmCommit(repoName, commitMsg)
+		if !ok {
+			// Unstage again so the skipped changes stay as the user left them
+			runGit("reset", "-q")
+			fmt.Printf(tr("  ⏭️  Skipped %s\n"), repoName)
+			return false
+		}
+	}
+
+	// Issue references go in the body, where Jira and the forges look for them
+	spent := activeTime(changePaths(changes))
+	var refs []string
+	for _, ref := range []string{jiraReference(settings, spent), issueReference(settings.IssueLinks)} {
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) > 0 {
+		commitMsg += "\n\n" + strings.Join(refs, "\n")
+	}
+
+	var trailers []string
+	if aiWritten {
+		trailers = append(trailers, aiMessageTrailer)
+	}
+	if settings.TimeTrailer != nil && *settings.TimeTrailer {
+		trailers = append(trailers, "Time-Spent: "+formatSpent(spent))
+	}
+	trailers = append(trailers, hostTrailers(settings.hostTrailer())...)
+	commitMsg = withTrailers(commitMsg, trailers)
+
+	if err := commitStaged(commitMsg, commitOptions...); err != nil {
+		publish(errorEvent("commit", "", err.Error()))
+		return false
+	}
+	publish(commitEvent(commitMsg))
+	noteCommit(settings, syncTrigger, aiWritten)
+	if len(held) == 0 {
+		watchmanSettled(repoPath)
+	}
+
+	tag := ""
+	if settings.TagVersions != nil && *settings.TagVersions {
+		tag = tagVersionBump(settings.tagPrefix())
+	}
+
+	// Push to all remotes immediately, unless encrypted files leaked into HEAD
+	if !checkPlaintextHead(repoPath) {
+		return true
+	}
+	if networkHeld != "" {
+		deferPush(repoPath, tag, networkHeld)
+		return true
+	}
+	// A superproject must not reference dependency commits nobody can fetch
+	if dep := holdForDependency(repoPath); dep != "" {
+		deferPush(repoPath, tag, fmt.Sprintf(tr("%s has unpushed commits"), displayName(dep)))
+		return true
+	}
+	if reason := policyDenial(repoPath, settings, "push", nil); reason != "" {
+		deferPush(repoPath, tag, fmt.Sprintf(tr("policy: %s"), reason))
+		return true
+	}
+	if reason := squashWait(settings); reason != "" {
+		deferPush(repoPath, tag, reason)
+		return true
+	}
+	squashUnpushed(repoPath, settings)
+	pushToAllRemotes(repoPath)
+	if tag != "" {
+		pushTagToAllRemotes(tag)
+	}
+
+	return true
+}
+
+// withTrailers appends git trailer lines ("Key: value") to a commit message
+func withTrailers(message string, trailers []string) string {
+	if len(trailers) == 0 {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + strings.Join(trailers, "\n")
+}
+
+// hostTrailers names the machine making a commit, so snapshots of a repo
+// synced between devices can be told apart
+func hostTrailers(mode string) []string {
+	if mode == "off" {
+		return nil
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	trailers := []string{"Git-Air-Host: " + host}
+	if mode == "full" {
+		trailers = append(trailers, "Git-Air-OS: "+runtime.GOOS+"/"+runtime.GOARCH)
+		if u, err := user.Current(); err == nil {
+			trailers = append(trailers, "Git-Air-User: "+u.Username)
+		}
+	}
+	return trailers
+}
+
+// activeTime estimates how long the changes took: from the earliest
+// modification of a changed file, but not before the previous commit
+func activeTime(paths []string) time.Duration {
+	now := time.Now()
+	start := now
+	for _, path := range paths {
+		if info, err := os.Lstat(inRepo(path)); err == nil && info.ModTime().Before(start) {
+			start = info.ModTime()
+		}
+	}
+
+	cmd := gitCommand("log", "-1", "--format=%ct")
+	if output, err := cmd.Output(); err == nil {
+		if secs, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64); err == nil {
+			if last := time.Unix(secs, 0); last.After(start) {
+				start = last
+			}
+		}
+	}
+	return now.Sub(start)
+}
+
+// formatSpent renders a duration for the Time-Spent trailer, e.g. 27m or 1h05m
+func formatSpent(d time.Duration) string {
+	mins := int(d.Round(time.Minute).Minutes())
+	if mins < 1 {
+		mins = 1
+	}
+	if mins < 60 {
+		return fmt.Sprintf("%dm", mins)
+	}
+	return fmt.Sprintf("%dh%02dm", mins/60, mins%60)
+}
+
+// isAutoCommit reports whether a commit message (or just its subject) was
+// written by git-air: the default subject (possibly with a conventional
+// "chore: " type), or any Git-Air-* trailer
+func isAutoCommit(message string) bool {
+	if gitair.IsAutoCommit(message) {
+		return true
+	}
+	if match := headerPattern.FindStringSubmatch(strings.SplitN(message, "\n", 2)[0]); match != nil && gitair.IsAutoCommit(match[3]) {
+		return true
+	}
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "Git-Air-") {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureIdentity sets user.name/user.email in the repo from the configured
+// identity when the repo has no local value of its own
+func ensureIdentity(identity Identity) {
+	values := map[string]string{"user.name": identity.Name, "user.email": identity.Email}
+	for _, key := range []string{"user.name", "user.email"} {
+		if values[key] == "" || gitConfigValue("--local", key) != "" {
+			continue
+		}
+		if runGit("config", "--local", key, values[key]) {
+			fmt.Printf(tr("  👤 Set %s to %s\n"), key, values[key])
+		} else {
+			fmt.Printf(tr("  ⚠️  Could not set %s\n"), key)
+		}
+	}
+}
+
+// hasIdentity reports whether git can determine an author identity for commits
+func hasIdentity() bool {
+	return runGit("var", "GIT_AUTHOR_IDENT") && runGit("var", "GIT_COMMITTER_IDENT")
+}
+
+// gitConfigValue reads a git config value, returns empty string if unset
+func gitConfigValue(args ...string) string {
+	cmd := gitCommand(append([]string{"config"}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// confirmCommit shows the staged diffstat and proposed message, then asks the
+// user to approve, edit or skip. Returns the message to use and whether to commit.
+func confirmCommit(repoName, commitMsg string) (string, bool) {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Printf(tr("  ⚠️  --confirm needs an interactive terminal, skipping %s\n"), repoName)
+		return commitMsg, false
+	}
+
+	cmd := gitCommand("diff", "--cached", "--stat")
+	if output, err := cmd.Output(); err == nil {
+		fmt.Print(string(output))
+	}
+
+	for {
+		fmt.Printf(tr("  💬 Message: %s\n"), commitMsg)
+		fmt.Printf(tr("  🙋 Commit %s? [y]es / [e]dit message / [s]kip: "), repoName)
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			return commitMsg, false
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return commitMsg, true
+		case "s", "skip", "n", "no":
+			return commitMsg, false
+		case "e", "edit":
+			fmt.Print(tr("  ✏️  New message: "))
+			edited, err := stdin.ReadString('\n')
+			if err != nil {
+				fmt.Println()
+				return commitMsg, false
+			}
+			if edited = strings.TrimSpace(edited); edited != "" {
+				commitMsg = edited
+			}
+		default:
+			fmt.Println(tr("  ⚠️  Please answer y, e or s"))
+		}
+	}
+}
+
+// pullUpdates pulls from remotes for inter-project communication
+func pullUpdates(repoPath string) {
+	if repoPaused(repoPath) {
+		return
+	}
+
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Printf(tr("  ❌ Error changing to %s: %v\n"), repoPath, err)
+		return
+	}
+	defer leave()
+
+	unlock, busy := claimRepo(repoPath)
+	if busy != "" {
+		fmt.Printf(tr("  ⏳ %s: %s, not pulling\n"), filepath.Base(repoPath), busy)
+		return
+	}
+	defer unlock()
+
+	settings := settingsFor(repoPath)
+	pullFromRemotes(repoPath, settings, false)
+	syncEncrypted(repoPath, settings.Encrypt)
+	cleanStaleBranches(repoPath, settings)
+	pruneSnapshots(repoPath, settings)
+}
+
+// fileChange is one entry of git status: the two-letter status code and the path
+type fileChange = gitair.Change
+
+// changedFiles lists modified, deleted and untracked files, minus transient
+// files. Untracked files are left out unless the untracked policy is "add".
+func changedFiles(settings RepoSettings, status []fileChange) []fileChange {
+	var changes []fileChange
+	trackedOnly := settings.untrackedPolicy() != "add"
+	for _, change := range status {
+		if trackedOnly && change.Status == "??" {
+			continue
+		}
+		if !settings.unstaged(change.Path) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// neverCommitted lists the changed files that match the never_commit globs
+func neverCommitted(settings RepoSettings, status []fileChange) []string {
+	var paths []string
+	for _, change := range status {
+		if matchesAny(settings.NeverCommit, change.Path) {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
+// reportUntracked raises an alert listing the new files git-air leaves for
+// the user to add or ignore, and clears it once there are none
+func reportUntracked(repoPath string, settings RepoSettings, status []fileChange) {
+	var untracked []string
+	for _, change := range status {
+		if change.Status == "??" && !settings.unstaged(change.Path) {
+			untracked = append(untracked, change.Path)
+		}
+	}
+	if len(untracked) == 0 {
+		clearAlert(repoPath, "new-files")
+		return
+	}
+	raiseAlert(repoPath, "new-files", fmt.Sprintf(tr("%s: %d new file(s) not committed: %s - git add them or add them to .gitignore"), displayName(repoPath), len(untracked), describeCount(untracked)))
+}
+
+// changePaths returns just the paths of a list of changes
+func changePaths(changes []fileChange) []string {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	return paths
+}
+
+// largestUntrackedDir checks whether the untracked files exceed the limit and
+// returns the new directory holding most of them (as git collapses it in status)
+func largestUntrackedDir(changes []fileChange, limit int) (string, int) {
+	total := 0
+	for _, change := range changes {
+		if change.Status == "??" {
+			total++
+		}
+	}
+	if limit <= 0 || total <= limit {
+		return "", 0
+	}
+
+	cmd := gitCommand(append(statusOptions(), "status", "--porcelain", "-z", "--untracked-files=normal")...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", total
+	}
+
+	bestDir, bestCount := "", 0
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if !strings.HasPrefix(entry, "?? ") || !strings.HasSuffix(entry, "/") {
+			continue
+		}
+		dir := entry[3:]
+		count := 0
+		for _, change := range changes {
+			if change.Status == "??" && strings.HasPrefix(change.Path, dir) {
+				count++
+			}
+		}
+		if count > bestCount {
+			bestDir, bestCount = dir, count
+		}
+	}
+	if bestDir == "" {
+		return ".", total // Spread over many places, no single directory to blame
+	}
+	return bestDir, bestCount
+}
+
+// filesInFlux returns changed files that were modified within the settle time
+// or are still held open for writing, i.e. probably not finished yet
+func filesInFlux(paths []string, settle time.Duration) []string {
+	if settle <= 0 {
+		return nil
+	}
+
+	var busy []string
+	var existing []string
+	for _, path := range paths {
+		info, err := os.Lstat(inRepo(path))
+		if err != nil {
+			continue // Deleted
+		}
+		existing = append(existing, path)
+		if time.Since(info.ModTime()) < settle {
+			busy = append(busy, path)
+		}
+	}
+	if len(busy) > 0 {
+		return busy
+	}
+	return openForWrite(existing)
+}
+
+// stageChanges stages all changes except transient files and the held paths
+func stageChanges(settings RepoSettings, held ...string) bool {
+	return runGit(stageArgs(settings, held...)...)
+}
+
+// stageArgs is the git add command line stageChanges runs
+func stageArgs(settings RepoSettings, held ...string) []string {
+	args := []string{"add", "-A", "--", "."}
+	if settings.untrackedPolicy() != "add" {
+		args = []string{"add", "-u", "--", "."}
+	}
+	for _, pattern := range settings.unstagedPatterns() {
+		args = append(args, excludePathspec(pattern))
+	}
+	if settings.Encrypt != nil && len(settings.Encrypt.Patterns) > 0 {
+		// info/exclude keeps new plaintext out; tracked plaintext is excluded
+		// by path, as a glob would also exclude the .age files it matches
+		for _, path := range trackedPlaintext(settings.Encrypt.Patterns) {
+			args = append(args, ":(exclude,literal)"+path)
+		}
+	}
+	for _, path := range held {
+		args = append(args, ":(exclude,literal)"+path)
+	}
+	return args
+}
+
+// matchesAny reports whether a repo-relative path matches one of the patterns.
+// Patterns without a slash match the file name in any directory, like .gitignore.
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+			// Everything below a directory, at any depth
+			for parent := filepath.Dir(path); parent != "."; parent = filepath.Dir(parent) {
+				if matchesAny([]string{dir}, parent) {
+					return true
+				}
+			}
+			continue
+		}
+		name := path
+		if !strings.Contains(pattern, "/") {
+			name = filepath.Base(path)
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// excludePathspec turns a pattern into a git pathspec that excludes it
+func excludePathspec(pattern string) string {
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return ":(exclude,glob)" + pattern
+}
+
+// pushToAllRemotes pushes the current repo to all configured remotes
+func pushToAllRemotes(repoPath string) {
+	remotes := getRemotes()
+	if len(remotes) == 0 {
+		fmt.Println(tr("  ⚠️  No remotes configured, skipping push"))
+		return
+	}
+
+	settings := settingsFor(repoPath)
+	branch, ok := syncBranch(repoPath, settings)
+	if !ok {
+		return
+	}
+	if dryRun {
+		showDryRunPush(remotes, branch)
+		return
+	}
+	var results, pushed []string
+	for _, remote := range remotes {
+		output, err := gitNetwork("push", remote, branch)
+		checkHostKey(repoPath, remote, output, err != nil)
+		if err == nil {
+			results = append(results, remote+" ok")
+			pushed = append(pushed, remote)
+			publish(gitair.Event{Type: gitair.Pushed, Remote: remote})
+		} else {
+			results = append(results, remote+" failed")
+			publish(errorEvent("push", remote, output))
+		}
+	}
+
+	// The note includes this push's results before it travels along
+	notePushes(settings, results)
+	if settings.notesMode() == "push" {
+		for _, remote := range pushed {
+			pushNotes(repoPath, remote)
+		}
+	}
+}
+
+// pullFromRemotes pulls from remotes for inter-project communication. A
+// manual pull (git-air pull) skips the review and CI gates. Returns false if
+// a fetch or pull failed.
+func pullFromRemotes(repoPath string, settings RepoSettings, manual bool) bool {
+	remotes := getRemotes()
+	if len(remotes) == 0 {
+		return true
+	}
+
+	branch, ok := syncBranch(repoPath, settings)
+	if !ok {
+		return true
+	}
+	review := !manual && settings.pullMode() == "review"
+
+	// Try to pull from each remote
+	for _, remote := range remotes {
+		output, err := fetchRemote(repoPath, remote)
+		checkHostKey(repoPath, remote, output, err != nil)
+		if err != nil {
+			publish(errorEvent("pull", remote, output))
+			ok = false
+			continue
+		}
+		if settings.notesMode() == "push" {
+			fetchNotes(remote)
+		}
+
+		// Check if there are remote changes
+		if !hasRemoteChanges(remote, branch) {
+			publish(gitair.Event{Type: gitair.UpToDate, Remote: remote})
+			continue
+		}
+		if review {
+			reviewIncoming(repoPath, remote, branch)
+			continue
+		}
+		if !manual && settings.WaitForCI != nil && *settings.WaitForCI && !ciAllowsPull(repoPath, remote, branch) {
+			continue
+		}
+		before, _ := gitOutput("rev-parse", "HEAD")
+		output, err = gitNetwork("pull", remote, branch)
+		if err != nil && diverged(remote, branch) {
+			// Both sides committed: settle auto-commit collisions by the
+			// policy, otherwise keep ours on a branch and take theirs
+			publish(gitair.Event{Type: gitair.Diverged, Remote: remote})
+			if policy := settings.conflictPolicy(); policy != "manual" && resolveCollision(repoPath, remote, branch, policy) {
+				err = nil
+			} else if err := rescueDiverged(repoPath, remote, branch); err != nil {
+				raiseAlert(repoPath, "diverged", fmt.Sprintf(tr("%s: diverged from %s and could not be rescued: %v"), displayName(repoPath), remote, err))
+				publish(errorEvent("pull", remote, err.Error()))
+				ok = false
+				continue
+			}
+			err = nil
+		}
+		if err != nil {
+			publish(errorEvent("pull", remote, output))
+			ok = false
+			continue
+		}
+		clearAlert(repoPath, "diverged")
+		publish(gitair.Event{Type: gitair.PullMerged, Remote: remote})
+		clearAlert(repoPath, "incoming:"+remote)
+		reportIncoming(repoPath, remote, strings.TrimSpace(before))
+		runPullHooks(repoPath, remote, strings.TrimSpace(before), settings.AfterPull)
+	}
+	return ok
+}
+
+// syncBranch returns the branch to push and pull. With a branch setting
+// that names another branch than the checked-out one it raises an alert and
+// returns false, so nothing is pushed to or pulled into the wrong branch.
+func syncBranch(repoPath string, settings RepoSettings) (string, bool) {
+	current := getCurrentBranch()
+	if settings.Branch == nil || *settings.Branch == current {
+		clearAlert(repoPath, "branch")
+		return current, true
+	}
+	fmt.Printf(tr("  ⚠️  %s: on branch %s, but branch is set to %s - not pushing or pulling\n"), displayName(repoPath), current, *settings.Branch)
+	raiseAlert(repoPath, "branch", fmt.Sprintf(tr("%s: pushes and pulls held, %s is checked out instead of %s"), displayName(repoPath), current, *settings.Branch))
+	return "", false
+}
+
+// getRemotes returns list of remote names
+func getRemotes() []string {
+	return openGit(inRepo(".")).Remotes()
+}
+
+// getCurrentBranch returns current branch name
+func getCurrentBranch() string {
+	cmd := gitCommand("branch", "--show-current")
+	output, err := cmd.Output()
+	if err != nil {
+		return "main" // fallback
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// runGit runs a git command and returns success
+func runGit(args ...string) bool {
+	cmd := gitCommand(args...)
+	err := cmd.Run()
+	if err != nil {
+		return false
+	}
+	return true
+}
+
+// gitOutput runs a git command and returns its combined output
+func gitOutput(args ...string) (string, error) {
+	cmd := gitCommand(args...)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if line := strings.TrimSpace(lines[len(lines)-1]); line != "" {
+		return line
+	}
+	return "unknown error"
+}
+
+// hasRemoteChanges checks if remote has changes
+func hasRemoteChanges(remote, branch string) bool {
+	cmd := gitCommand("rev-parse", "HEAD")
+	localOut, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	
+	cmd = gitCommand("rev-parse", remote+"/"+branch)
+	remoteOut, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	
+	return string(localOut) != string(remoteOut)
+}
+
+// repoDir is the absolute path of the repo processRepo, pullUpdates and
+// the commands work in, "" outside of one. git runs there, and the paths
+// git reports are resolved against it with inRepo. The process itself never
+// changes directory, so other goroutines can resolve paths at any time, but
+// only the main goroutine may read repoDir.
+var repoDir string
+
+// enterRepo makes repoPath the repo git commands and repo-relative paths
+// refer to until leave is called, which returns to the repo worked in before
+func enterRepo(repoPath string) (leave func(), err error) {
+	dir, err := filepath.Abs(inRepo(repoPath))
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	previous := repoDir
+	repoDir = dir
+	return func() { repoDir = previous }, nil
+}
+
+// inRepo resolves a path relative to the repo being worked in
+func inRepo(path string) string {
+	if repoDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(repoDir, path)
+}
+
+// getCurrentDir returns the repo being worked in, or the directory git-air
+// was started in
+func getCurrentDir() string {
+	if repoDir != "" {
+		return repoDir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// isMonorepo checks if a repository contains submodules or nested repos
+func isMonorepo(repoPath string) bool {
+	// Check for .gitmodules file (Git submodules)
+	gitmodules := filepath.Join(repoPath, ".gitmodules")
+	if _, err := os.Stat(gitmodules); err == nil {
+		return true
+	}
+	
+	// Check for nested .git directories (indicates multiple projects)
+	nestedRepos := 0
+	filepath.Walk(repoPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && info.Name() == ".git" && path != filepath.Join(repoPath, ".git") {
+			nestedRepos++
+			if nestedRepos > 0 {
+				return filepath.SkipDir // Found nested repos, it's a monorepo
+			}
+		}
+		return nil
+	})
+	
+	return nestedRepos > 0
+}
+
+// syncSubmodules ensures all submodules are updated before main repo commit.
+// It runs in the repo and returns the submodules whose pointer bump is
+// held back because their new commit isn't pushed yet.
+func syncSubmodules(repoPath string, settings RepoSettings) ([]string, bool) {
+	// Check if there are submodules
+	if _, err := os.Stat(inRepo(".gitmodules")); err != nil {
+		return nil, true // No submodules, all good
+	}
+
+	if dryRun {
+		// The update fetches, so a dry run can only say which would be updated
+		output, _ := gitOutput("config", "-f", ".gitmodules", "--get-regexp", `^submodule\..*\.path$`)
+		var paths []string
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			if _, path, ok := strings.Cut(line, " "); ok {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 0 {
+			fmt.Printf(tr("  🧪 Would update the submodules %s from their remotes\n"), strings.Join(paths, ", "))
+		}
+		return nil, true
+	}
+
+	fmt.Print(tr("  📦 Syncing submodules..."))
+
+	// Update all submodules
+	if _, err := gitNetwork("submodule", "update", "--remote", "--merge"); err != nil {
+		fmt.Print(tr(" ❌ failed\n"))
+		return nil, false
+	}
+	fmt.Printf(" ✓\n")
+
+	// Add the submodule changes whose commits their remotes have
+	held := heldPointers(repoPath)
+	if !stageChanges(settings, held...) {
+		fmt.Print(tr("  ⚠️  failed to stage submodule changes\n"))
+		return held, false
+	}
+	return held, true
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// meteredMarker holds the user's metered mode, "on" or "off"; without it the
+// connection is detected
+const meteredMarker = "metered"
+
+// meteredMode returns "on", "off" or "auto"
+func meteredMode() string {
+	data, err := os.ReadFile(filepath.Join(stateDir(), meteredMarker))
+	if err != nil {
+		return "auto"
+	}
+	if mode := strings.TrimSpace(string(data)); mode == "on" || mode == "off" {
+		return mode
+	}
+	return "auto"
+}
+
+// checkMetered holds pushes and pulls back while the connection is metered,
+// unless something else already holds them
+func checkMetered() {
+	if networkHeld != "" {
+		return
+	}
+	switch meteredMode() {
+	case "on":
+		networkHeld = tr("metered mode")
+	case "auto":
+		if connectionMetered() {
+			networkHeld = tr("metered connection")
+		}
+	}
+}
+
+// runMetered implements `git-air metered [on|off|auto]`
+func runMetered(args []string) int {
+	fs := flag.NewFlagSet("metered", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air metered [on|off|auto]"))
+		fmt.Println(tr("\nOn a metered connection git-air keeps committing but defers pushes and"))
+		fmt.Println(tr("skips pulls. \"on\" and \"off\" override the detection, \"auto\" restores it."))
+		fmt.Println(tr("Without an argument, shows the current mode. Running instances pick up a"))
+		fmt.Println(tr("change on their next cycle."))
+	}
+	positional := parseInterspersed(fs, args)
+	if len(positional) > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	if len(positional) == 1 {
+		marker := filepath.Join(stateDir(), meteredMarker)
+		var err error
+		switch positional[0] {
+		case "on", "off":
+			if err = os.MkdirAll(stateDir(), 0755); err == nil {
+				err = os.WriteFile(marker, []byte(positional[0]+"\n"), 0644)
+			}
+		case "auto":
+			if err = os.Remove(marker); os.IsNotExist(err) {
+				err = nil
+			}
+		default:
+			fs.Usage()
+			return 2
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			return 1
+		}
+	}
+
+	switch meteredMode() {
+	case "on":
+		fmt.Println(tr("📶 Metered mode: ON (pushes deferred, pulls skipped)"))
+	case "off":
+		fmt.Println(tr("📶 Metered mode: OFF (syncing normally, detection disabled)"))
+	default:
+		state := "not metered"
+		if connectionMetered() {
+			state = "metered, pushes deferred and pulls skipped"
+		}
+		fmt.Printf(tr("📶 Metered mode: AUTO (connection %s)\n"), state)
+	}
+	return 0
+}
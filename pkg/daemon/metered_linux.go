@@ -0,0 +1,21 @@
+package daemon
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// connectionMetered asks NetworkManager whether any connected device is
+// metered, including its own guesses (e.g. phone hotspots)
+func connectionMetered() bool {
+	output, err := exec.Command("nmcli", "-t", "-f", "GENERAL.METERED", "device", "show").Output()
+	if err != nil {
+		return false // No NetworkManager
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if value := strings.TrimPrefix(line, "GENERAL.METERED:"); strings.HasPrefix(value, "yes") {
+			return true
+		}
+	}
+	return false
+}
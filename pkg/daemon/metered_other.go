@@ -0,0 +1,9 @@
+//go:build !linux && !windows
+
+package daemon
+
+// connectionMetered cannot detect metered connections on this platform, use
+// `git-air metered on` instead
+func connectionMetered() bool {
+	return false
+}
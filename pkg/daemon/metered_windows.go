@@ -0,0 +1,20 @@
+package daemon
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// meteredScript prints the cost type of the internet connection profile
+const meteredScript = `$p = [Windows.Networking.Connectivity.NetworkInformation,Windows.Networking.Connectivity,ContentType=WindowsRuntime]::GetInternetConnectionProfile(); if ($p) { $p.GetConnectionCost().NetworkCostType }`
+
+// connectionMetered asks Windows for the connection's cost type: Fixed and
+// Variable are metered, Unrestricted is not
+func connectionMetered() bool {
+	output, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", meteredScript).Output()
+	if err != nil {
+		return false
+	}
+	cost := strings.TrimSpace(string(output))
+	return cost == "Fixed" || cost == "Variable"
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+)
+
+// networkSafeInterval is the least time between two checks of a repo on a
+// network file system, where every status is slow and races with other clients
+const networkSafeInterval = 5 * time.Minute
+
+var (
+	// safeModeRepos records the network file system of each repo in safe
+	// mode, keyed by absolute path, "" for repos checked and found local
+	safeModeRepos = map[string]string{}
+
+	// lastSafeModeCheck records when each safe-mode repo was last processed
+	lastSafeModeCheck = map[string]time.Time{}
+)
+
+// safeMode returns the network file system the repo in the current
+// directory is on, or "" if it is local or safe mode is off. The file system
+// is looked up once per repo.
+func safeMode(repoPath string, settings RepoSettings) string {
+	if settings.NetworkSafeMode != nil && !*settings.NetworkSafeMode {
+		return ""
+	}
+	dir := getCurrentDir()
+	fs, ok := safeModeRepos[dir]
+	if !ok {
+		fs = networkFilesystem(dir)
+		safeModeRepos[dir] = fs
+		if fs != "" {
+			fmt.Printf(tr("🌐 %s is on %s: safe mode, checked every %.0f minutes without fsmonitor\n"), displayName(repoPath), fs, networkSafeInterval.Minutes())
+		}
+	}
+	return fs
+}
+
+// safeModeDue reports whether a safe-mode repo may be processed this cycle,
+// and if so starts its next interval
+func safeModeDue(repoPath string) bool {
+	if time.Since(lastSafeModeCheck[repoPath]) < networkSafeInterval {
+		return false
+	}
+	lastSafeModeCheck[repoPath] = time.Now()
+	return true
+}
@@ -0,0 +1,25 @@
+package daemon
+
+import "syscall"
+
+// networkTypes are the macOS file system names of network mounts
+var networkTypes = map[string]bool{"nfs": true, "smbfs": true, "afpfs": true, "webdav": true}
+
+// networkFilesystem names the network file system path is on, "" for local ones
+func networkFilesystem(path string) string {
+	var stat syscall.Statfs_t
+	if syscall.Statfs(path, &stat) != nil {
+		return ""
+	}
+	var name []byte
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	if networkTypes[string(name)] {
+		return string(name)
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package daemon
+
+import "syscall"
+
+// networkMagic maps statfs f_type values of network file systems to names
+var networkMagic = map[uint32]string{
+	0x6969:     "nfs",
+	0x517B:     "smb",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x01021997: "9p", // also WSL's view of Windows drives
+	0x5346414F: "afs",
+	0x73757245: "coda",
+	0x47504653: "gpfs",
+	0x0BD00BD0: "lustre",
+	0x00C36400: "ceph",
+}
+
+// networkFilesystem names the network file system path is on, "" for local ones
+func networkFilesystem(path string) string {
+	var stat syscall.Statfs_t
+	if syscall.Statfs(path, &stat) != nil {
+		return ""
+	}
+	return networkMagic[uint32(stat.Type)]
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package daemon
+
+// networkFilesystem cannot tell network mounts apart on this platform
+func networkFilesystem(path string) string {
+	return ""
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// driveRemote is DRIVE_REMOTE from GetDriveTypeW, a mapped network drive
+const driveRemote = 4
+
+// networkFilesystem tells UNC paths and mapped network drives apart from
+// local ones, which get ""
+func networkFilesystem(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	volume := filepath.VolumeName(abs)
+	if strings.HasPrefix(volume, `\\`) {
+		return "network share"
+	}
+	root, err := syscall.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return ""
+	}
+	kind, _, _ := syscall.NewLazyDLL("kernel32.dll").NewProc("GetDriveTypeW").Call(uintptr(unsafe.Pointer(root)))
+	if kind == driveRemote {
+		return "network drive"
+	}
+	return ""
+}
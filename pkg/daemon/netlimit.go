@@ -0,0 +1,14 @@
+package daemon
+
+// netSlots limits how many git commands talk to remotes at the same time
+// (--max-parallel-net), so many repos don't open as many SSH connections at
+// once and trip server rate limits
+var netSlots = make(chan struct{}, 4)
+
+// gitNetwork runs a git command that contacts a remote, once a network slot
+// is free, with the repo's proxy settings
+func gitNetwork(args ...string) (string, error) {
+	netSlots <- struct{}{}
+	defer func() { <-netSlots }()
+	return gitOutput(append(proxyOptions(), args...)...)
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// notesRef holds git-air's sync metadata, one note per commit with
+// "Key: value" lines: the machine, what triggered the commit, the AI model
+// that wrote its message and the result of every push
+const notesRef = "refs/notes/git-air"
+
+// notesRemotePrefix keeps each remote's notes as fetched, for merging
+const notesRemotePrefix = "refs/notes/git-air-remotes/"
+
+// syncTrigger says what started the current sync of a repo: "cycle",
+// "immediate" (an immediate path changed) or "editor" (git-air trigger)
+var syncTrigger = "cycle"
+
+// noteCommit records how the current repo's HEAD commit was made
+func noteCommit(settings RepoSettings, trigger string, aiWritten bool) {
+	if settings.notesMode() == "off" {
+		return
+	}
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	lines := []string{"Machine: " + host, "Trigger: " + trigger}
+	if aiWritten {
+		cfg := settings.aiConfig()
+		lines = append(lines, "AI-Model: "+cfg.Provider+"/"+cfg.ModelName())
+	}
+	writeNote("HEAD", lines)
+}
+
+// notePushes adds the push results ("origin ok", "backup failed") to the
+// note of the current repo's HEAD
+func notePushes(settings RepoSettings, results []string) {
+	if settings.notesMode() == "off" || len(results) == 0 {
+		return
+	}
+	stamp := time.Now().Format(time.RFC3339)
+	lines := make([]string, len(results))
+	for i, result := range results {
+		lines[i] = "Push: " + result + " " + stamp
+	}
+	writeNote("HEAD", lines)
+}
+
+// writeNote appends lines to a commit's git-air note
+func writeNote(commit string, lines []string) {
+	if existing, err := gitOutput("notes", "--ref", notesRef, "show", commit); err == nil {
+		lines = append(strings.Split(strings.TrimSpace(existing), "\n"), lines...)
+	}
+	if output, err := gitOutput("notes", "--ref", notesRef, "add", "-f", "-m", strings.Join(lines, "\n"), commit); err != nil {
+		fmt.Printf(tr("  ⚠️  Could not write the sync note: %s\n"), lastLine(output))
+	}
+}
+
+// fetchNotes merges a remote's git-air notes into the local ones. Lines of
+// a commit noted on both sides are combined, so no machine's entries are lost.
+func fetchNotes(remote string) bool {
+	tracking := notesRemotePrefix + remote
+	if _, err := gitNetwork("fetch", "-q", remote, "+"+notesRef+":"+tracking); err != nil {
+		return false // The remote has no notes yet
+	}
+	if _, err := gitOutput("rev-parse", "--verify", "-q", notesRef); err != nil {
+		return runGit("update-ref", notesRef, tracking)
+	}
+	return runGit("notes", "--ref", notesRef, "merge", "-q", "-s", "cat_sort_uniq", tracking)
+}
+
+// pushNotes sends the git-air notes to a remote, merging the remote's first
+// so the push fast-forwards
+func pushNotes(repoPath, remote string) {
+	if _, err := gitOutput("rev-parse", "--verify", "-q", notesRef); err != nil {
+		return // Nothing noted yet
+	}
+	fetchNotes(remote)
+	if output, err := gitNetwork("push", "-q", remote, notesRef+":"+notesRef); err != nil {
+		fmt.Printf(tr("  ⚠️  %s: pushing the sync notes to %s failed: %s\n"), displayName(repoPath), remote, lastLine(output))
+	}
+}
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// NotifyConfig selects where alerts are delivered besides the console
+type NotifyConfig struct {
+	Desktop bool   `json:"desktop,omitempty"` // notify-send on Linux, osascript on macOS
+	Command string `json:"command,omitempty"` // run via sh -c with GIT_AIR_TITLE/GIT_AIR_MESSAGE/GIT_AIR_REPO set
+	Webhook string `json:"webhook,omitempty"` // receives a JSON POST {title, message, repo}
+}
+
+// alerts holds the conditions currently needing attention, keyed by repo and kind,
+// so each one is notified once instead of every cycle
+var alerts = map[string]string{}
+
+// raiseAlert records a problem for a repo and notifies when it is new
+func raiseAlert(repoPath, kind, message string) {
+	key := repoPath + "\x00" + kind
+	if alerts[key] == message {
+		return
+	}
+	alerts[key] = message
+	notify("Git Air: "+displayName(repoPath), message, repoPath)
+}
+
+// clearAlert forgets a problem once it has been resolved
+func clearAlert(repoPath, kind string) {
+	delete(alerts, repoPath+"\x00"+kind)
+}
+
+// printAlerts lists all open alerts as part of the cycle status
+func printAlerts() {
+	if len(alerts) == 0 {
+		return
+	}
+
+	messages := make([]string, 0, len(alerts))
+	for _, message := range alerts {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+
+	fmt.Printf(tr("\n🚨 %d issue(s) need attention:\n"), len(messages))
+	for _, message := range messages {
+		fmt.Printf("  • %s\n", message)
+	}
+}
+
+// displayName returns a display name for a repo path
+func displayName(repoPath string) string {
+	if repoPath == "." {
+		return tr("current directory")
+	}
+	if other := otherSidePath(repoPath); other != "" {
+		return repoPath + " (" + other + ")"
+	}
+	return repoPath
+}
+
+// notify delivers a message to every configured channel. Failures are
+// reported on the console only, so a broken channel never stops syncing.
+func notify(title, message, repoPath string) {
+	cfg := config.Notify
+
+	if cfg.Desktop {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			script := fmt.Sprintf("display notification %q with title %q", message, title)
+			cmd = exec.Command("osascript", "-e", script)
+		default:
+			cmd = exec.Command("notify-send", title, message)
+		}
+		if err := cmd.Run(); err != nil {
+			fmt.Printf(tr("  ⚠️  Desktop notification failed: %v\n"), err)
+		}
+	}
+
+	if cfg.Command != "" {
+		cmd := shellCommand(cfg.Command)
+		cmd.Env = append(os.Environ(),
+			"GIT_AIR_TITLE="+title,
+			"GIT_AIR_MESSAGE="+message,
+			"GIT_AIR_REPO="+repoPath,
+			"GIT_AIR_OTHER_PATH="+otherSidePath(repoPath),
+		)
+		if err := cmd.Run(); err != nil {
+			fmt.Printf(tr("  ⚠️  Notification command failed: %v\n"), err)
+		}
+	}
+
+	if cfg.Webhook != "" {
+		payload := map[string]string{"title": title, "message": message, "repo": repoPath}
+		if other := otherSidePath(repoPath); other != "" {
+			payload["other_path"] = other // the same repo as seen across the WSL boundary
+		}
+		body, _ := json.Marshal(payload)
+		client := http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Post(cfg.Webhook, "application/json", bytes.NewReader(body))
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  Notification webhook failed: %v\n"), err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			fmt.Printf(tr("  ⚠️  Notification webhook returned %s\n"), resp.Status)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"fmt"
+
+	"git-air/pkg/gitair"
+)
+
+// onceMode runs a single discovery, commit, push and pull cycle and exits
+// (--once), for cron, CI and scripts
+var onceMode bool
+
+// Exit codes of --once
+const (
+	onceSynced    = 0 // Every repo is in sync
+	onceFailed    = 1 // A commit, push or pull failed, or git-air could not start
+	onceAttention = 2 // Nothing failed, but issues need attention (held changes, conflicts, ...)
+)
+
+// onceFailures counts the failed operations of the cycle
+var onceFailures int
+
+// countFailures is subscribed to the event stream in --once mode
+func countFailures(event gitair.Event) {
+	if event.Type == gitair.Error {
+		onceFailures++
+	}
+}
+
+// onceStatus reports how the cycle went and returns its exit code
+func onceStatus() int {
+	switch {
+	case onceFailures > 0:
+		fmt.Printf(tr("\n❌ %d operation(s) failed\n"), onceFailures)
+		return onceFailed
+	case len(alerts) > 0:
+		return onceAttention
+	}
+	fmt.Println(tr("\n✓ All repositories in sync"))
+	return onceSynced
+}
@@ -0,0 +1,131 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// handlePauseSignals pauses and resumes syncing on the signals git-air pause
+// and git-air resume send, where the platform has them
+func handlePauseSignals() {
+	signals := make(chan os.Signal, 1)
+	if !notifyPause(signals) {
+		return
+	}
+	go func() {
+		for sig := range signals {
+			if sig == pauseSignal {
+				paused.Store(true)
+				fmt.Println(tr("\n⏸️  Paused by git-air pause: no commits, pushes or pulls until git-air resume"))
+			} else {
+				paused.Store(false)
+				fmt.Println(tr("\n▶️  Resumed by git-air resume"))
+			}
+			wake() // Resuming starts a cycle right away
+		}
+	}()
+}
+
+// interrupted reports whether the cycle should end early: git-air is
+// stopping or was paused
+func interrupted() bool {
+	return stopping() || paused.Load()
+}
+
+// runPause implements `git-air pause`: suspends the running git-air syncing
+// the current directory, e.g. for a rebase
+func runPause(args []string) int {
+	return pauseInstances("pause", args, true)
+}
+
+// runResume implements `git-air resume`: lets the paused git-air sync again
+func runResume(args []string) int {
+	return pauseInstances("resume", args, false)
+}
+
+// pauseInstances pauses or resumes every running git-air whose tree or repos
+// contain the directory (default: the current one), or lie below it
+func pauseInstances(name string, args []string, pause bool) int {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Printf(tr("  git-air %s [dir]\n"), name)
+		if pause {
+			fmt.Println(tr("\nStops the running git-air that syncs the directory (default: the current"))
+			fmt.Println(tr("one) from committing, pushing and pulling until git-air resume, so rebases"))
+			fmt.Println(tr("and other history edits aren't auto-committed over. Inside one of its"))
+			fmt.Println(tr("repositories only that one is paused, in the directory it was started in"))
+			fmt.Println(tr("everything. The repo being synced is finished first."))
+		} else {
+			fmt.Println(tr("\nLets the git-air paused with git-air pause sync the directory (default:"))
+			fmt.Println(tr("the current one) again, starting a cycle right away. In the directory it"))
+			fmt.Println(tr("was started in, every paused repository is resumed."))
+		}
+	}
+	dirs := parseInterspersed(fs, args)
+	if len(dirs) > 1 {
+		fs.Usage()
+		return 2
+	}
+	dir := "."
+	if len(dirs) == 1 {
+		dir = dirs[0]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+		return 1
+	}
+
+	instances := coveringInstances(dir)
+	if len(instances) == 0 {
+		return 1
+	}
+	for _, instance := range instances {
+		// The control socket can pause single repos; without one (git-air
+		// --once) the signal pauses everything
+		reply := map[string]string{"repo": "all"}
+		var err error
+		if _, statErr := os.Stat(controlSocketPath(instance.PID)); statErr == nil {
+			err = callControl(instance.PID, http.MethodPost, "/"+name, controlRequest{Path: dir}, &reply)
+		} else {
+			err = signalPause(instance.PID, pause)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ pid %d: %v\n"), instance.PID, err)
+			return 1
+		}
+		switch {
+		case reply["repo"] != "all" && pause:
+			fmt.Printf(tr("⏸️  Paused %s in git-air (pid %d)\n"), reply["repo"], instance.PID)
+		case reply["repo"] != "all":
+			fmt.Printf(tr("▶️  Resumed %s in git-air (pid %d)\n"), reply["repo"], instance.PID)
+		case pause:
+			fmt.Printf(tr("⏸️  Paused git-air (pid %d, syncing %s)\n"), instance.PID, instance.Root)
+		default:
+			fmt.Printf(tr("▶️  Resumed git-air (pid %d, syncing %s)\n"), instance.PID, instance.Root)
+		}
+	}
+	return 0
+}
+
+// instanceCovers reports whether an instance syncs dir: its tree or one of
+// its repos contains dir or lies below it
+func instanceCovers(instance instanceRecord, dir string) bool {
+	for _, path := range append([]string{instance.Root}, instance.Repos...) {
+		if nestedPath(dir, path) || nestedPath(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedPath reports whether path is dir or below it
+func nestedPath(path, dir string) bool {
+	path, dir = filepath.Clean(path), filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
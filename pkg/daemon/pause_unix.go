@@ -0,0 +1,29 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// pauseSignal pauses a running git-air, resumeSignal lets it sync again
+const (
+	pauseSignal  = syscall.SIGUSR1
+	resumeSignal = syscall.SIGUSR2
+)
+
+// notifyPause delivers the pause and resume signals to signals
+func notifyPause(signals chan<- os.Signal) bool {
+	signal.Notify(signals, pauseSignal, resumeSignal)
+	return true
+}
+
+// signalPause sends a running git-air the pause or resume signal
+func signalPause(pid int, pause bool) error {
+	if pause {
+		return syscall.Kill(pid, pauseSignal)
+	}
+	return syscall.Kill(pid, resumeSignal)
+}
@@ -0,0 +1,20 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// pauseSignal never arrives: Windows has no user signals
+const pauseSignal = syscall.Signal(-1)
+
+// notifyPause reports that pausing by signal is unavailable
+func notifyPause(signals chan<- os.Signal) bool {
+	return false
+}
+
+// signalPause can't reach another process on Windows
+func signalPause(pid int, pause bool) error {
+	return errors.New(tr("pausing from the command line needs signals, which Windows doesn't have - use Pause in the tray menu"))
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package daemon
+
+import "os/exec"
+
+// configurePlatformGit has nothing to set outside Windows
+func configurePlatformGit() {}
+
+// longPath returns path unchanged, only Windows limits path length
+func longPath(path string) string {
+	return path
+}
+
+// shellCommand runs a configured command line with sh
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// maxPath is where Windows APIs start failing without the \\?\ prefix (MAX_PATH
+// minus room for a file name)
+const maxPath = 248
+
+// configurePlatformGit turns on core.longpaths for every git process git-air
+// starts, through GIT_CONFIG_COUNT, unless it is configured either way.
+// Without it git can't stage files below deep node_modules-style trees.
+func configurePlatformGit() {
+	if gitConfigValue("core.longpaths") != "" {
+		return
+	}
+	n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_KEY_%d", n), "core.longpaths")
+	os.Setenv(fmt.Sprintf("GIT_CONFIG_VALUE_%d", n), "true")
+	os.Setenv("GIT_CONFIG_COUNT", strconv.Itoa(n+1))
+}
+
+// longPath returns the extended-length form (\\?\C:\... or \\?\UNC\...) of
+// a path too long for the classic Windows APIs, so discovery can descend
+// into deep trees
+func longPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil || len(abs) < maxPath || strings.HasPrefix(abs, `\\?\`) {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
+
+// shellCommand runs a configured command line with cmd.exe. The line is
+// passed verbatim: Go's argument quoting follows different rules than cmd's,
+// which breaks commands containing quotes.
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command("cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `/d /s /c "` + command + `"`}
+	return cmd
+}
@@ -0,0 +1,339 @@
+package daemon
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy holds back commits or pushes while its Deny expression is true,
+// e.g. `touches("migrations/**") && weekday == "Friday" && hour >= 16`.
+// Expressions use Go syntax: && || ! == != < <= > >=, parentheses, int and
+// string literals, the policyVars and the policyFuncs.
+type Policy struct {
+	When   string `json:"when"`             // "commit" or "push"
+	Deny   string `json:"deny"`             // expression
+	Reason string `json:"reason,omitempty"` // shown when it denies, default the expression
+}
+
+// policyVars are the names expressions can use, with a zero value of their type
+var policyVars = map[string]any{
+	"repo":    "",       // directory name of the repo
+	"branch":  "",       // checked-out branch
+	"host":    "",       // this machine, as in Git-Air-Host
+	"weekday": "",       // "Monday" ... "Sunday"
+	"date":    "",       // "2006-01-02"
+	"hour":    int64(0), // 0-23, local time
+	"minute":  int64(0),
+	"changed": int64(0), // number of files to commit or push
+	"added":   int64(0), // lines added in them
+	"deleted": int64(0), // lines deleted in them
+}
+
+// policyFuncs take patterns (as in never_commit) and look at the files
+var policyFuncs = map[string]func(patterns []string, files []string) bool{
+	// touches: some file matches
+	"touches": func(patterns, files []string) bool {
+		for _, file := range files {
+			if matchesAny(patterns, file) {
+				return true
+			}
+		}
+		return false
+	},
+	// only: there are files and all of them match
+	"only": func(patterns, files []string) bool {
+		for _, file := range files {
+			if !matchesAny(patterns, file) {
+				return false
+			}
+		}
+		return len(files) > 0
+	},
+}
+
+// policyEnv is what a policy is evaluated against
+type policyEnv struct {
+	vars  map[string]any
+	files []string
+
+	// strict evaluates both sides of && and ||, so validation type-checks
+	// every part of an expression
+	strict bool
+}
+
+// validate parses the expression and type-checks it against zero values
+func (p Policy) validate() error {
+	if p.When != "commit" && p.When != "push" {
+		return fmt.Errorf("policy when must be commit or push, got %q", p.When)
+	}
+	expr, err := parser.ParseExpr(p.Deny)
+	if err != nil {
+		return fmt.Errorf("policy %q: %v", p.Deny, err)
+	}
+	value, err := evalPolicy(expr, policyEnv{vars: policyVars, strict: true})
+	if err != nil {
+		return fmt.Errorf("policy %q: %v", p.Deny, err)
+	}
+	if _, ok := value.(bool); !ok {
+		return fmt.Errorf("policy %q: must be true or false, not a %s", p.Deny, valueType(value))
+	}
+	return nil
+}
+
+// reason is how a denying policy is reported
+func (p Policy) reason() string {
+	if p.Reason != "" {
+		return p.Reason
+	}
+	return p.Deny
+}
+
+// evalPolicy evaluates an expression tree
+func evalPolicy(expr ast.Expr, env policyEnv) (any, error) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return evalPolicy(e.X, env)
+
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return strconv.ParseInt(e.Value, 0, 64)
+		case token.STRING:
+			return strconv.Unquote(e.Value)
+		}
+		return nil, fmt.Errorf("unsupported literal %s", e.Value)
+
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		if value, ok := env.vars[e.Name]; ok {
+			return value, nil
+		}
+		return nil, fmt.Errorf("unknown name %s", e.Name)
+
+	case *ast.UnaryExpr:
+		x, err := evalPolicy(e.X, env)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := x.(bool); ok && e.Op == token.NOT {
+			return !b, nil
+		}
+		return nil, fmt.Errorf("%s needs a boolean", e.Op)
+
+	case *ast.CallExpr:
+		name, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported call of %s", exprKind(e.Fun))
+		}
+		if policyFuncs[name.Name] == nil {
+			return nil, fmt.Errorf("unknown function %s", name.Name)
+		}
+		var patterns []string
+		for _, arg := range e.Args {
+			value, err := evalPolicy(arg, env)
+			if err != nil {
+				return nil, err
+			}
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s takes patterns (strings)", name.Name)
+			}
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q", pattern)
+			}
+			patterns = append(patterns, pattern)
+		}
+		if len(patterns) == 0 {
+			return nil, fmt.Errorf("%s needs a pattern", name.Name)
+		}
+		return policyFuncs[name.Name](patterns, env.files), nil
+
+	case *ast.BinaryExpr:
+		return evalBinary(e, env)
+	}
+	return nil, fmt.Errorf("unsupported expression %s", exprKind(expr))
+}
+
+// evalBinary evaluates the logical operators (short-circuiting unless
+// strict) and the comparisons of two ints or two strings
+func evalBinary(e *ast.BinaryExpr, env policyEnv) (any, error) {
+	x, err := evalPolicy(e.X, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Op == token.LAND || e.Op == token.LOR {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs booleans", e.Op)
+		}
+		if !env.strict && left == (e.Op == token.LOR) {
+			return left, nil
+		}
+		y, err := evalPolicy(e.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		right, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s needs booleans", e.Op)
+		}
+		if e.Op == token.LOR {
+			return left || right, nil
+		}
+		return left && right, nil
+	}
+
+	y, err := evalPolicy(e.Y, env)
+	if err != nil {
+		return nil, err
+	}
+	switch left := x.(type) {
+	case int64:
+		if right, ok := y.(int64); ok {
+			return compare(e.Op, left, right)
+		}
+	case string:
+		if right, ok := y.(string); ok {
+			return compare(e.Op, left, right)
+		}
+	case bool:
+		if right, ok := y.(bool); ok && (e.Op == token.EQL || e.Op == token.NEQ) {
+			return (left == right) == (e.Op == token.EQL), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot compare %s %s %s", valueType(x), e.Op, valueType(y))
+}
+
+// valueType names the type of a value for error messages
+func valueType(value any) string {
+	switch value.(type) {
+	case int64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "string"
+}
+
+// compare applies a comparison operator
+func compare[T int64 | string](op token.Token, x, y T) (any, error) {
+	switch op {
+	case token.EQL:
+		return x == y, nil
+	case token.NEQ:
+		return x != y, nil
+	case token.LSS:
+		return x < y, nil
+	case token.LEQ:
+		return x <= y, nil
+	case token.GTR:
+		return x > y, nil
+	case token.GEQ:
+		return x >= y, nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", op)
+}
+
+// exprKind names an unsupported part of an expression for error messages
+func exprKind(expr ast.Expr) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", expr), "*ast.")
+}
+
+// policyFiles lists the files a decision is about with their line counts:
+// the uncommitted changes for "commit", the commits no remote has yet for "push"
+func policyFiles(when string) ([]string, int64, int64) {
+	args := []string{"diff", "--numstat", "HEAD"}
+	if when == "push" {
+		args = []string{"log", "--numstat", "--format=", "HEAD", "--not", "--remotes"}
+	}
+	output, err := gitCommand(args...).Output()
+	if err != nil {
+		return nil, 0, 0
+	}
+	seen := map[string]bool{}
+	var files []string
+	var added, deleted int64
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		// Binary files show "-" instead of line counts
+		if n, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			added += n
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			deleted += n
+		}
+		if !seen[fields[2]] {
+			seen[fields[2]] = true
+			files = append(files, fields[2])
+		}
+	}
+	return files, added, deleted
+}
+
+// policyDenial evaluates the repo's policies for a decision ("commit" or
+// "push") in the current repo and returns the reason of the first that
+// denies it, "" if none does. For commits, files are the paths about to be
+// committed, since new files have no diff against HEAD yet.
+func policyDenial(repoPath string, settings RepoSettings, when string, files []string) string {
+	var policies []Policy
+	for _, policy := range settings.Policies {
+		if policy.When == when {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return ""
+	}
+
+	diffFiles, added, deleted := policyFiles(when)
+	if files == nil {
+		files = diffFiles
+	}
+	now := time.Now()
+	abs, _ := filepath.Abs(repoPath)
+	env := policyEnv{
+		vars: map[string]any{
+			"repo":    filepath.Base(abs),
+			"branch":  getCurrentBranch(),
+			"host":    hostLabel(),
+			"weekday": now.Weekday().String(),
+			"date":    now.Format("2006-01-02"),
+			"hour":    int64(now.Hour()),
+			"minute":  int64(now.Minute()),
+			"changed": int64(len(files)),
+			"added":   added,
+			"deleted": deleted,
+		},
+		files: files,
+	}
+
+	for _, policy := range policies {
+		expr, err := parser.ParseExpr(policy.Deny)
+		if err != nil {
+			continue // Checked when the config was loaded
+		}
+		value, err := evalPolicy(expr, env)
+		if err != nil {
+			return fmt.Sprintf("policy %q failed: %v", policy.Deny, err)
+		}
+		if denied, _ := value.(bool); denied {
+			return policy.reason()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+)
+
+// PowerConfig makes git-air go easy on laptop batteries
+type PowerConfig struct {
+	Disabled          bool    `json:"disabled,omitempty"`            // ignore the power state
+	BatteryInterval   Minutes `json:"battery_interval,omitempty"`    // time between cycles on battery, default 5 minutes
+	PauseNetworkBelow *int    `json:"pause_network_below,omitempty"` // charge in percent below which pushes and pulls wait, default 20, 0 never pauses
+}
+
+// networkHeld says why pushes and pulls are paused this cycle, "" if they aren't
+var networkHeld string
+
+// powerNotice is the last power state printed, so only changes are reported
+var powerNotice string
+
+// deferredPushes lists the repos with commits (and tags) waiting to be pushed
+var deferredPushes = map[string][]string{}
+
+// validate checks the interval and threshold
+func (c PowerConfig) validate() error {
+	if c.BatteryInterval < 0 {
+		return fmt.Errorf("power battery_interval must not be negative")
+	}
+	if c.PauseNetworkBelow != nil && (*c.PauseNetworkBelow < 0 || *c.PauseNetworkBelow > 100) {
+		return fmt.Errorf("power pause_network_below must be between 0 and 100")
+	}
+	return nil
+}
+
+// batteryInterval returns how long to sleep between cycles on battery, never
+// shorter than the normal interval
+func (c PowerConfig) batteryInterval(interval time.Duration) time.Duration {
+	battery := 5 * time.Minute
+	if c.BatteryInterval != 0 {
+		battery = time.Duration(c.BatteryInterval)
+	}
+	if battery < interval {
+		return interval
+	}
+	return battery
+}
+
+// pauseNetworkBelow returns the charge below which the network is left alone
+func (c PowerConfig) pauseNetworkBelow() int {
+	if c.PauseNetworkBelow != nil {
+		return *c.PauseNetworkBelow
+	}
+	return 20
+}
+
+// checkPower reads the power state at the start of a cycle, sets networkHeld
+// and returns how long to sleep after the cycle
+func checkPower(interval time.Duration) time.Duration {
+	networkHeld = ""
+	onBattery, percent, ok := readBattery()
+	if config.Power.Disabled || !ok || !onBattery {
+		reportPower("")
+		return interval
+	}
+
+	interval = config.Power.batteryInterval(interval)
+	if percent < config.Power.pauseNetworkBelow() {
+		networkHeld = fmt.Sprintf(tr("battery at %d%%"), percent)
+		reportPower(fmt.Sprintf("🔋 Battery at %d%%: checking every %s, pushes and pulls paused", percent, formatInterval(interval)))
+	} else {
+		reportPower(fmt.Sprintf("🔋 On battery: checking every %s", formatInterval(interval)))
+	}
+	return interval
+}
+
+// reportPower prints the power state when it changes
+func reportPower(notice string) {
+	if notice == powerNotice {
+		return
+	}
+	if notice == "" {
+		fmt.Println(tr("🔌 On mains power again"))
+	} else {
+		fmt.Println(notice)
+	}
+	powerNotice = notice
+}
+
+// deferPush remembers that the current repo has a commit (and maybe a tag)
+// to push once the network may be used again, or its dependencies are pushed
+func deferPush(repoPath, tag, reason string) {
+	fmt.Printf(tr("  ⏸️  Push deferred (%s)\n"), reason)
+	tags := deferredPushes[repoPath]
+	if tag != "" {
+		tags = append(tags, tag)
+	}
+	deferredPushes[repoPath] = tags
+}
+
+// pushDeferred pushes the commits and tags held back while the network was paused
+func pushDeferred() {
+	if networkHeld != "" || len(deferredPushes) == 0 {
+		return
+	}
+
+	fmt.Println(tr("\n🚀 Pushing deferred commits..."))
+	for repoPath, tags := range deferredPushes {
+		if dep := holdForDependency(repoPath); dep != "" {
+			fmt.Printf(tr("  ⏸️  %s: waiting for %s to be pushed\n"), displayName(repoPath), displayName(dep))
+			continue
+		}
+		// Kept for later, like processRepo does, while paused or mid-rebase
+		if repoPaused(repoPath) {
+			fmt.Printf(tr("  ⏸️  %s: paused, push kept for later\n"), displayName(repoPath))
+			continue
+		}
+		leave, err := enterRepo(repoPath)
+		if err != nil {
+			fmt.Printf(tr("  ❌ Error changing to %s: %v\n"), repoPath, err)
+			continue
+		}
+		unlock, busy := claimRepo(repoPath)
+		if busy != "" {
+			fmt.Printf(tr("  ⏳ %s: %s, push kept for later\n"), displayName(repoPath), busy)
+			leave()
+			continue
+		}
+		settings := settingsFor(repoPath)
+		if reason := policyDenial(repoPath, settings, "push", nil); reason != "" {
+			fmt.Printf(tr("  🚦 %s: waiting, policy: %s\n"), displayName(repoPath), reason)
+			unlock()
+			leave()
+			continue
+		}
+		if squashWait(settings) != "" {
+			unlock()
+			leave()
+			continue
+		}
+		fmt.Printf("  📁 %s\n", displayName(repoPath))
+		squashUnpushed(repoPath, settings)
+		pushToAllRemotes(repoPath)
+		for _, tag := range tags {
+			pushTagToAllRemotes(tag)
+		}
+		delete(deferredPushes, repoPath)
+		unlock()
+		leave()
+	}
+}
@@ -0,0 +1,25 @@
+package daemon
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// batteryPercent finds the charge in pmset output, e.g. "-InternalBattery-0 (id=123)	45%; discharging"
+var batteryPercent = regexp.MustCompile(`(\d+)%;`)
+
+// readBattery asks pmset for the power source and charge
+func readBattery() (onBattery bool, percent int, ok bool) {
+	output, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, 0, false
+	}
+	match := batteryPercent.FindStringSubmatch(string(output))
+	if match == nil {
+		return false, 0, false // No battery, e.g. a desktop Mac
+	}
+	percent, _ = strconv.Atoi(match[1])
+	return strings.Contains(string(output), "'Battery Power'"), percent, true
+}
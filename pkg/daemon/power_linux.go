@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readBattery reads /sys/class/power_supply: on battery when no mains or USB
+// supply is online, charge from the system batteries (not mice or headsets)
+func readBattery() (onBattery bool, percent int, ok bool) {
+	supplies, err := os.ReadDir("/sys/class/power_supply")
+	if err != nil {
+		return false, 0, false
+	}
+
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return strings.TrimSpace(string(data))
+	}
+	mains, batteries, total := false, 0, 0
+	for _, supply := range supplies {
+		dir := filepath.Join("/sys/class/power_supply", supply.Name())
+		switch read(dir, "type") {
+		case "Mains", "USB":
+			if read(dir, "online") == "1" {
+				mains = true
+			}
+		case "Battery":
+			if read(dir, "scope") == "Device" {
+				continue
+			}
+			if capacity, err := strconv.Atoi(read(dir, "capacity")); err == nil {
+				batteries++
+				total += capacity
+			}
+		}
+	}
+	if batteries == 0 {
+		return false, 0, false
+	}
+	return !mains, total / batteries, true
+}
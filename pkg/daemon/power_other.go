@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package daemon
+
+// readBattery cannot read the power state on this platform, git-air behaves
+// as if on mains power
+func readBattery() (onBattery bool, percent int, ok bool) {
+	return false, 0, false
+}
@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// ioprioBestEffortLowest is IOPRIO_CLASS_BE (2) at level 7, the lowest
+// priority that still gets disk time under load
+const ioprioBestEffortLowest = 2<<13 | 7
+
+// lowerPriority renices git-air and lowers its I/O priority, so the git
+// processes it starts inherit both. Linux applies them per thread, so every
+// thread of the process is changed; new threads inherit from those.
+func lowerPriority() error {
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, 10); err != nil {
+			return err
+		}
+		// ioprio_set(IOPRIO_WHO_PROCESS, tid, prio)
+		if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, 1, uintptr(tid), ioprioBestEffortLowest); errno != 0 {
+			return errno
+		}
+	}
+	return nil
+}
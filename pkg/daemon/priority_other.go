@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package daemon
+
+import "syscall"
+
+// lowerPriority renices git-air, which the git processes it starts inherit.
+// I/O priority is left alone on this platform.
+func lowerPriority() error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, 0, 10)
+}
@@ -0,0 +1,17 @@
+package daemon
+
+import "syscall"
+
+// belowNormalPriorityClass is BELOW_NORMAL_PRIORITY_CLASS, inherited by child processes
+const belowNormalPriorityClass = 0x00004000
+
+// lowerPriority moves git-air and the git processes it starts to the below
+// normal priority class
+func lowerPriority() error {
+	kernel32 := syscall.NewLazyDLL("kernel32.dll")
+	process, _, _ := kernel32.NewProc("GetCurrentProcess").Call()
+	if ok, _, err := kernel32.NewProc("SetPriorityClass").Call(process, belowNormalPriorityClass); ok == 0 {
+		return err
+	}
+	return nil
+}
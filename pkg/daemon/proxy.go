@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// launchDir is the directory git-air was started in. Repo paths, and so the
+// repos rules, are relative to it.
+var launchDir, _ = os.Getwd()
+
+// proxyOptions returns the -c options that send the current repo's remotes
+// through their configured proxies: http.proxy for the whole repo and
+// remote.<name>.proxy per remote, where "" means a direct connection
+func proxyOptions() []string {
+	repoPath := getCurrentDir()
+	if rel, err := filepath.Rel(launchDir, repoPath); err == nil {
+		repoPath = rel
+	}
+	return proxyOptionsFor(repoPath)
+}
+
+// proxyOptionsFor returns the proxy options of a repo given by its path
+// relative to launchDir, for git commands run from elsewhere with -C
+func proxyOptionsFor(repoPath string) []string {
+	settings := settingsFor(repoPath)
+
+	var options []string
+	if settings.Proxy != nil {
+		options = append(options, "-c", "http.proxy="+*settings.Proxy)
+	}
+	remotes := make([]string, 0, len(settings.RemoteProxies))
+	for remote := range settings.RemoteProxies {
+		remotes = append(remotes, remote)
+	}
+	sort.Strings(remotes)
+	for _, remote := range remotes {
+		options = append(options, "-c", "remote."+remote+".proxy="+settings.RemoteProxies[remote])
+	}
+	return options
+}
+
+// validateProxy checks a proxy setting: empty, or a URL such as
+// http://proxy:3128 or socks5h://localhost:1080
+func validateProxy(proxy string) error {
+	if proxy == "" {
+		return nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy %q, expected e.g. http://proxy:3128 or socks5://host:1080", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks4", "socks4a", "socks5", "socks5h":
+		return nil
+	}
+	return fmt.Errorf("invalid proxy %q, the scheme must be http, https, socks4, socks4a, socks5 or socks5h", proxy)
+}
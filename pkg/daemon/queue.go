@@ -0,0 +1,272 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queueRefPrefix holds the snapshots of capture "queue" mode, one commit
+// object per ref, numbered in capture order. Each snapshot's parent is the
+// previous one (the first's is HEAD), so a diff to the parent is what
+// changed in between.
+const queueRefPrefix = "refs/git-air/queue/"
+
+// queueEntry is one captured snapshot
+type queueEntry struct {
+	Ref    string
+	Commit string
+	Parent string
+	Tree   string
+	Time   time.Time
+}
+
+// queueEntries lists the current repo's snapshots, oldest first
+func queueEntries() []queueEntry {
+	output, err := gitCommand("for-each-ref", "--sort=refname",
+		"--format=%(refname) %(objectname) %(parent) %(tree) %(committerdate:unix)", queueRefPrefix).Output()
+	if err != nil {
+		return nil
+	}
+	var entries []queueEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[4], 10, 64)
+		entries = append(entries, queueEntry{Ref: fields[0], Commit: fields[1], Parent: fields[2], Tree: fields[3], Time: time.Unix(unix, 0)})
+	}
+	return entries
+}
+
+// snapshotTree writes what staging would commit to a tree object, through a
+// separate index so the real one is left alone
+func snapshotTree(settings RepoSettings, held ...string) (string, error) {
+	gitDir, err := gitOutput("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("%s", lastLine(gitDir))
+	}
+	index := filepath.Join(strings.TrimSpace(gitDir), "git-air-queue-index")
+	defer os.Remove(index)
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+index)
+	for _, args := range [][]string{{"read-tree", "HEAD"}, stageArgs(settings, held...)} {
+		cmd := gitCommand(args...)
+		cmd.Env = env
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("%s", lastLine(string(output)))
+		}
+	}
+	cmd := gitCommand("write-tree")
+	cmd.Env = env
+	tree, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(tree)), nil
+}
+
+// captureQueue records the changes of the current repo as a new snapshot
+// instead of committing them, if they differ from the last snapshot.
+// Returns true if a snapshot was added.
+func captureQueue(repoPath string, settings RepoSettings, held ...string) bool {
+	tree, err := snapshotTree(settings, held...)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not capture changes: %v\n"), displayName(repoPath), err)
+		return false
+	}
+
+	entries := queueEntries()
+	parent := "HEAD"
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		if last.Tree == tree {
+			return false // Nothing new since the last snapshot
+		}
+		parent = last.Commit
+	}
+	message := "git-air snapshot " + time.Now().Format("2006-01-02 15:04:05")
+	commit, err := gitOutput("commit-tree", "--no-gpg-sign", tree, "-p", parent, "-m", message)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not capture changes: %s\n"), displayName(repoPath), lastLine(commit))
+		return false
+	}
+
+	seq := 1
+	if len(entries) > 0 {
+		seq, _ = strconv.Atoi(strings.TrimPrefix(entries[len(entries)-1].Ref, queueRefPrefix))
+		seq++
+	}
+	if !runGit("update-ref", fmt.Sprintf("%s%06d", queueRefPrefix, seq), strings.TrimSpace(commit)) {
+		fmt.Printf(tr("  ❌ %s: could not store the snapshot\n"), displayName(repoPath))
+		return false
+	}
+	stat, _ := gitOutput("diff", "--shortstat", parent, strings.TrimSpace(commit))
+	fmt.Printf(tr("  📥 %s: queued snapshot #%d (%s), review with git-air review\n"), displayName(repoPath), seq, strings.TrimSpace(stat))
+	return true
+}
+
+// runReview implements `git-air review <repo...>`: commit queued snapshots in batches
+func runReview(args []string) int {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air review <repo...>"))
+		fmt.Println(tr("\nLists the snapshots captured in \"capture\": \"queue\" mode and commits them in"))
+		fmt.Println(tr("batches: all snapshots up to the one you pick become one commit with your"))
+		fmt.Println(tr("message, which is pushed to every remote."))
+	}
+	repos := parseInterspersed(fs, args)
+	if len(repos) == 0 {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintln(os.Stderr, tr("❌ Error: review needs an interactive terminal"))
+		return 1
+	}
+
+	status := 0
+	for _, repoPath := range repos {
+		leave, err := enterRepo(repoPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+			status = 1
+			continue
+		}
+		if !reviewQueue(repoPath) {
+			status = 1
+		}
+		leave()
+	}
+	return status
+}
+
+// reviewQueue walks the user through the current repo's snapshots until the
+// queue is empty or they quit
+func reviewQueue(repoPath string) bool {
+	for {
+		entries := queueEntries()
+		if len(entries) == 0 {
+			fmt.Printf(tr("✓ %s: no queued snapshots\n"), displayName(repoPath))
+			return true
+		}
+
+		// The snapshots hold whole trees: committing one on top of a HEAD that
+		// moved since would undo the commits in between
+		head, _ := gitOutput("rev-parse", "HEAD")
+		if entries[0].Parent != strings.TrimSpace(head) {
+			fmt.Printf(tr("⚠️  %s: HEAD moved since the snapshots were taken, they can only be discarded\n"), displayName(repoPath))
+			fmt.Print(tr("  Discard the queue? The working tree keeps every change [y/N]: "))
+			answer, _ := stdin.ReadString('\n')
+			if strings.ToLower(strings.TrimSpace(answer)) == "y" {
+				discardQueue(entries)
+			}
+			return true
+		}
+
+		fmt.Printf(tr("📋 %s: %d queued snapshot(s)\n"), displayName(repoPath), len(entries))
+		for i, entry := range entries {
+			stat, _ := gitOutput("diff", "--shortstat", entry.Parent, entry.Commit)
+			fmt.Printf("  %2d. %s  %s\n", i+1, entry.Time.Format("2006-01-02 15:04:05"), strings.TrimSpace(stat))
+		}
+		fmt.Printf(tr("  Commit up to snapshot [1-%d, a=all] / show [s N] / [d]iscard queue / [q]uit: "), len(entries))
+		answer, err := stdin.ReadString('\n')
+		if err != nil {
+			fmt.Println()
+			return true
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		switch fields := strings.Fields(answer); {
+		case answer == "q" || answer == "":
+			return true
+		case answer == "d":
+			discardQueue(entries)
+			fmt.Println(tr("  🗑️  Queue discarded, the working tree keeps every change"))
+		case answer == "a":
+			if !commitQueued(repoPath, entries, len(entries)) {
+				return false
+			}
+		case len(fields) == 2 && fields[0] == "s":
+			if n, err := strconv.Atoi(fields[1]); err == nil && n >= 1 && n <= len(entries) {
+				cmd := gitCommand("diff", entries[n-1].Parent, entries[n-1].Commit)
+				cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+				cmd.Run()
+			}
+		default:
+			n, err := strconv.Atoi(answer)
+			if err != nil || n < 1 || n > len(entries) {
+				fmt.Println(tr("  ⚠️  Please answer a snapshot number, a, s N, d or q"))
+				continue
+			}
+			if !commitQueued(repoPath, entries, n) {
+				return false
+			}
+		}
+	}
+}
+
+// commitQueued commits snapshot n (1-based) with a message from the user,
+// drops the snapshots up to it and pushes
+func commitQueued(repoPath string, entries []queueEntry, n int) bool {
+	message := fallbackMessage(nil, false)
+	fmt.Printf(tr("  💬 Message [%s]: "), message)
+	if answer, err := stdin.ReadString('\n'); err == nil && strings.TrimSpace(answer) != "" {
+		message = strings.TrimSpace(answer)
+	}
+	message = withTrailers(message, hostTrailers(settingsFor(repoPath).hostTrailer()))
+
+	unlock, busy := claimRepo(repoPath)
+	if busy != "" {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s, try again later\n"), repoPath, busy)
+		return false
+	}
+	defer unlock()
+
+	entry := entries[n-1]
+	commit, err := gitOutput("commit-tree", entry.Tree, "-p", "HEAD", "-m", message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s\n"), repoPath, lastLine(commit))
+		return false
+	}
+	commit = strings.TrimSpace(commit)
+	if output, err := gitOutput("update-ref", "-m", "git-air review", "HEAD", commit, entry.Parent); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s\n"), repoPath, lastLine(output))
+		return false
+	}
+	runGit("reset", "-q") // The index follows the new HEAD, the working tree stays
+	publish(commitEvent(message))
+	noteCommit(settingsFor(repoPath), "review", false)
+	discardQueue(entries[:n])
+
+	// The next snapshot now builds on the new commit
+	if n < len(entries) {
+		next := entries[n]
+		cmd := gitCommand("commit-tree", "--no-gpg-sign", next.Tree, "-p", commit, "-m", "git-air snapshot "+next.Time.Format("2006-01-02 15:04:05"))
+		cmd.Env = append(os.Environ(), fmt.Sprintf("GIT_COMMITTER_DATE=%d +0000", next.Time.Unix()))
+		if rebased, err := cmd.Output(); err == nil {
+			runGit("update-ref", next.Ref, strings.TrimSpace(string(rebased)))
+		}
+	}
+
+	pushToAllRemotes(repoPath)
+	return true
+}
+
+// discardQueue deletes snapshot refs; the working tree is not touched
+func discardQueue(entries []queueEntry) {
+	for _, entry := range entries {
+		runGit("update-ref", "-d", entry.Ref)
+	}
+}
@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// repoConfigName is the file in a repo's root whose settings override the
+// global config and the repos rules for that repo
+const repoConfigName = ".gitair.yml"
+
+// repoConfigAllowed are the settings a .gitair.yml may set: how commit
+// messages look, when and what triggers a commit, and what is held back,
+// including pushes and pulls while another branch than branch is checked
+// out (git-air never switches to it). The file arrives with every pull, so everything else stays with the
+// git-air config: whoever can push to a remote could otherwise run
+// commands, send diffs to an AI provider, change the identity or where
+// commits go, delete branches or have secrets sent elsewhere.
+var repoConfigAllowed = map[string]bool{
+	"transient_patterns": true, "settle_seconds": true, "untracked_limit": true, "template": true,
+	"tag_versions": true, "tag_prefix": true, "commitlint": true, "subject_length": true, "body_width": true,
+	"ignore_whitespace": true, "ignore_line_endings": true, "ignore_diffs": true, "generated": true,
+	"ignore_generated": true, "never_commit": true, "policies": true, "watchman": true, "immediate": true,
+	"priority": true, "depends_on": true, "interval": true, "message_template": true, "branch": true,
+}
+
+// repoConfigEntry is a parsed .gitair.yml and the mtime it was read at
+type repoConfigEntry struct {
+	ModTime  time.Time
+	Settings *RepoSettings
+}
+
+// repoConfigs caches each repo's .gitair.yml until it changes
+var repoConfigs = map[string]repoConfigEntry{}
+
+// cycleInterval is the daemon's check interval, which repo intervals extend
+var cycleInterval time.Duration
+
+// lastRepoCheck records when repos with their own interval were last checked
+var lastRepoCheck = map[string]time.Time{}
+
+// repoConfig returns the settings of a repo's .gitair.yml, nil if it has
+// none or the file can't be used, which raises an alert
+func repoConfig(repoPath string) *RepoSettings {
+	dir := repoPath
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(launchDir, dir) // Settings are also read outside the repo
+	}
+	path := filepath.Join(dir, repoConfigName)
+	info, err := os.Stat(path)
+	if err != nil {
+		if _, known := repoConfigs[repoPath]; known {
+			delete(repoConfigs, repoPath)
+			clearAlert(repoPath, "repo-config")
+		}
+		return nil
+	}
+	if entry, ok := repoConfigs[repoPath]; ok && entry.ModTime.Equal(info.ModTime()) {
+		return entry.Settings
+	}
+
+	settings, err := readRepoConfig(path)
+	if err != nil {
+		fmt.Printf(tr("  ⚠️  %s: %s ignored: %v\n"), displayName(repoPath), repoConfigName, err)
+		raiseAlert(repoPath, "repo-config", fmt.Sprintf(tr("%s: %s ignored: %v"), displayName(repoPath), repoConfigName, err))
+	} else {
+		clearAlert(repoPath, "repo-config")
+	}
+	repoConfigs[repoPath] = repoConfigEntry{ModTime: info.ModTime(), Settings: settings}
+	return settings
+}
+
+// readRepoConfig parses and checks a .gitair.yml
+func readRepoConfig(path string) (*RepoSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = yamlToJSON(data); err != nil {
+		return nil, err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("expected settings, got %s", strings.TrimSpace(string(data)))
+	}
+	var denied []string
+	for key := range keys {
+		if !repoConfigAllowed[key] {
+			denied = append(denied, key)
+		}
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return nil, fmt.Errorf("%s can only be set in the git-air config", strings.Join(denied, ", "))
+	}
+
+	var settings RepoSettings
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+	if err := settings.validate(); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// repoDue reports whether a repo with an interval longer than the check
+// interval may be checked this cycle, and if so starts its next interval
+func repoDue(repoPath string, settings RepoSettings) bool {
+	interval := settings.repoInterval()
+	if interval <= cycleInterval {
+		return true
+	}
+	if time.Since(lastRepoCheck[repoPath]) < interval {
+		return false
+	}
+	lastRepoCheck[repoPath] = time.Now()
+	return true
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runReport implements `git-air report --from --to --format csv|json`
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	from := fs.String("from", "", "Start of the period, YYYY-MM-DD or RFC 3339 (default: 30 days ago)")
+	to := fs.String("to", "", "End of the period, inclusive for dates (default: now)")
+	format := fs.String("format", "csv", "Output format: csv or json")
+	rawEvents := fs.Bool("events", false, "Export every recorded event instead of per-repo totals")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air report [--from <date>] [--to <date>] [--format csv|json] [--events]"))
+		fmt.Println(tr("\nExports per-repository activity and errors from the state store."))
+	}
+	if len(parseInterspersed(fs, args)) != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	now := time.Now()
+	start, err := parseReportTime(*from, now.AddDate(0, 0, -30), false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: --from: %v\n"), err)
+		return 2
+	}
+	end, err := parseReportTime(*to, now, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: --to: %v\n"), err)
+		return 2
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: unknown format %q (use csv or json)\n"), *format)
+		return 2
+	}
+
+	events, err := readEvents(start, end)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error reading history: %v\n"), err)
+		return 1
+	}
+
+	switch {
+	case *format == "json" && *rawEvents:
+		err = writeJSON(map[string]interface{}{"from": start, "to": end, "events": events})
+	case *format == "json":
+		repos, total := summarizeEvents(events)
+		err = writeJSON(map[string]interface{}{"from": start, "to": end, "repos": repos, "total": total})
+	case *rawEvents:
+		err = writeEventsCSV(events)
+	default:
+		repos, _ := summarizeEvents(events)
+		err = writeStatsCSV(repos)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error writing report: %v\n"), err)
+		return 1
+	}
+	return 0
+}
+
+// parseReportTime accepts a date or RFC 3339 timestamp. A plain date used as
+// the end of the period includes that whole day.
+func parseReportTime(value string, fallback time.Time, endOfPeriod bool) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfPeriod {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// writeJSON prints a value as indented JSON
+func writeJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeStatsCSV prints one row of totals per repository
+func writeStatsCSV(repos []RepoStats) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"repo", "commits", "files_changed", "lines_added", "lines_deleted", "pushes",
+		"push_failures", "pulls", "pull_failures", "commit_failures", "last_error"})
+	for _, r := range repos {
+		w.Write([]string{r.Repo, strconv.Itoa(r.Commits), strconv.Itoa(r.Files), strconv.Itoa(r.Added),
+			strconv.Itoa(r.Deleted), strconv.Itoa(r.Pushes), strconv.Itoa(r.PushFailures), strconv.Itoa(r.Pulls),
+			strconv.Itoa(r.PullFailures), strconv.Itoa(r.CommitFailures), r.LastError})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeEventsCSV prints one row per recorded event
+func writeEventsCSV(events []Event) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"time", "repo", "kind", "ok", "remote", "files", "added", "deleted", "error"})
+	for _, e := range events {
+		w.Write([]string{e.Time.Format(time.RFC3339), e.Repo, e.Kind, strconv.FormatBool(e.OK), e.Remote,
+			strconv.Itoa(e.Files), strconv.Itoa(e.Added), strconv.Itoa(e.Deleted), e.Error})
+	}
+	w.Flush()
+	return w.Error()
+}
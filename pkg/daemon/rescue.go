@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// unsafeRefChars are the characters replaced in the host part of branch names
+var unsafeRefChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// diverged reports whether HEAD and remote/branch each have commits the
+// other lacks, so neither can be fast-forwarded to the other
+func diverged(remote, branch string) bool {
+	upstream := remote + "/" + branch
+	return !runGit("merge-base", "--is-ancestor", "HEAD", upstream) &&
+		!runGit("merge-base", "--is-ancestor", upstream, "HEAD")
+}
+
+// hostLabel returns the hostname in a form that can be part of a branch name
+func hostLabel() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return unsafeRefChars.ReplaceAllString(host, "-")
+}
+
+// rescueBranch names the branch that keeps the local side of a divergence
+func rescueBranch() string {
+	return fmt.Sprintf("git-air/diverged-%s-%s", hostLabel(), time.Now().Format("20060102-150405"))
+}
+
+// rescueDiverged saves the local commits of a diverged branch on a rescue
+// branch pushed to remote, then fast-forwards the branch to remote/branch
+// so pulls and pushes work again. Uncommitted changes are kept.
+func rescueDiverged(repoPath, remote, branch string) error {
+	// Leave no half-done merge or rebase behind from the failed pull
+	runGit("merge", "--abort")
+	runGit("rebase", "--abort")
+
+	rescue := rescueBranch()
+	if output, err := gitOutput("branch", rescue, "HEAD"); err != nil {
+		return fmt.Errorf("creating %s: %s", rescue, lastLine(output))
+	}
+	if output, err := gitNetwork("push", remote, rescue); err != nil {
+		return fmt.Errorf("pushing %s: %s", rescue, lastLine(output))
+	}
+	if output, err := gitOutput("reset", "--keep", remote+"/"+branch); err != nil {
+		return fmt.Errorf("moving %s to %s/%s (local commits are on %s): %s", branch, remote, branch, rescue, lastLine(output))
+	}
+
+	fmt.Printf(tr("  🛟 %s: diverged from %s, local commits saved to %s and %s reset to %s/%s\n"), displayName(repoPath), remote, rescue, branch, remote, branch)
+	notify(fmt.Sprintf(tr("Git Air: %s diverged from %s"), displayName(repoPath), remote),
+		fmt.Sprintf(tr("Local commits were pushed to %s and %s now follows %s/%s. Merge the rescue branch to bring them back."), rescue, branch, remote, branch), repoPath)
+	return nil
+}
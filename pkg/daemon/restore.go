@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"archive/tar"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runRestore implements `git-air restore <repo> --at <time> [path...]`:
+// bring back the files as they were at a point in time
+func runRestore(args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	at := fs.String("at", "", "Point in time, anything git understands (\"yesterday 14:00\", \"2 hours ago\", \"2024-05-01 09:30\")")
+	to := fs.String("to", "", "Write the files to this directory instead of the working tree")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air restore <repo> --at <time> [--to <dir>] [path...]"))
+		fmt.Println(tr("\nFinds the latest commit or git-air snapshot (capture queue, stash and"))
+		fmt.Println(tr("snapshot modes) made at or before the time and restores the paths, or the"))
+		fmt.Println(tr("whole tree, from it. Uncommitted changes are stashed first; with --to the"))
+		fmt.Println(tr("repo is left alone and the files are written to the directory."))
+		fmt.Println(tr("\nOPTIONS:"))
+		fs.PrintDefaults()
+	}
+	positional := parseInterspersed(fs, args)
+	if len(positional) == 0 || *at == "" {
+		fs.Usage()
+		return 2
+	}
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	repoPath, paths := positional[0], positional[1:]
+
+	// --to is relative to where the command was run, not to the repo
+	dir := *to
+	if dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			return 1
+		}
+		dir = abs
+	}
+	leave, err := enterRepo(repoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error changing to %s: %v\n"), repoPath, err)
+		return 1
+	}
+	defer leave()
+
+	commit, err := restorePoint(*at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %v\n"), repoPath, err)
+		return 1
+	}
+	described, _ := gitOutput("log", "-1", "--format=%h %ci %s", commit)
+	fmt.Printf(tr("🕰️  Restoring from %s\n"), strings.TrimSpace(described))
+
+	if dir != "" {
+		if err := extractTree(commit, paths, dir); err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error: %v\n"), err)
+			return 1
+		}
+		fmt.Printf(tr("✓ Files written to %s\n"), dir)
+		return 0
+	}
+
+	unlock, busy := claimRepo(repoPath)
+	if busy != "" {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s: %s, try again later\n"), repoPath, busy)
+		return 1
+	}
+	defer unlock()
+
+	// Nothing uncommitted is lost: it goes to the stash like capture "stash"
+	// mode, and without a status nothing is overwritten
+	status, err := gitStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: git status failed: %v\n"), err)
+		return 1
+	}
+	if len(status) > 0 {
+		captureStash(repoPath, settingsFor(repoPath))
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	restoreArgs := append([]string{"restore", "--source=" + commit, "--worktree", "--"}, paths...)
+	if output, err := gitOutput(restoreArgs...); err != nil {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s\n"), lastLine(output))
+		return 1
+	}
+	fmt.Println(tr("✓ Restored into the working tree; git-air commits it like any change"))
+	return 0
+}
+
+// restorePoint finds the latest commit at or before at among the branch
+// history and the git-air snapshots
+func restorePoint(at string) (string, error) {
+	args := []string{"rev-list", "-1", "--date-order", "--before=" + at, "HEAD",
+		"--glob=" + snapshotRefPrefix + "*", "--glob=" + queueRefPrefix + "*"}
+	for _, entry := range stashSnapshots() {
+		args = append(args, entry.Commit)
+	}
+	output, err := gitOutput(args...)
+	if err != nil {
+		return "", fmt.Errorf("%s", lastLine(output))
+	}
+	commit := strings.TrimSpace(output)
+	if commit == "" {
+		return "", fmt.Errorf("nothing was recorded before %s", at)
+	}
+	return commit, nil
+}
+
+// extractTree writes the paths (all if none) of a commit below dir
+func extractTree(commit string, paths []string, dir string) error {
+	cmd := gitCommand(append([]string{"archive", "--format=tar", commit, "--"}, paths...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	reader := tar.NewReader(stdout)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cmd.Wait()
+			return fmt.Errorf("%s", lastLine(stderr.String()))
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			continue
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeSymlink:
+			os.MkdirAll(filepath.Dir(target), 0755)
+			os.Remove(target)
+			err = os.Symlink(header.Linkname, target)
+		case tar.TypeReg:
+			err = writeTarFile(reader, target, os.FileMode(header.Mode).Perm())
+		}
+		if err != nil {
+			cmd.Wait()
+			return err
+		}
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s", lastLine(stderr.String()))
+	}
+	return nil
+}
+
+// writeTarFile copies the current archive entry to path
+func writeTarFile(reader io.Reader, path string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,191 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pruneInterval is how often a repo's snapshots are thinned out
+const pruneInterval = time.Hour
+
+// lastPrune records when each repo's snapshots were last pruned
+var lastPrune = map[string]time.Time{}
+
+// Retention thins out git-air snapshots as they age: the newest per minute
+// is kept for MinutelyHours, the newest per hour for HourlyDays and the
+// newest per day for DailyDays; older ones are dropped. A zero skips that
+// tier. The newest snapshot is always kept.
+type Retention struct {
+	MinutelyHours int `json:"minutely_hours,omitempty"`
+	HourlyDays    int `json:"hourly_days,omitempty"`
+	DailyDays     int `json:"daily_days,omitempty"`
+}
+
+func (r *Retention) validate() error {
+	if r.MinutelyHours < 0 || r.HourlyDays < 0 || r.DailyDays < 0 {
+		return fmt.Errorf("retention periods must not be negative")
+	}
+	return nil
+}
+
+// keep picks the snapshots to keep from their times, newest first
+func (r *Retention) keep(times []time.Time, now time.Time) []bool {
+	keep := make([]bool, len(times))
+	seen := map[string]bool{}
+	for i, t := range times {
+		age := now.Sub(t)
+		var bucket string
+		switch {
+		case age <= time.Duration(r.MinutelyHours)*time.Hour:
+			bucket = "minute " + t.Format("2006-01-02 15:04")
+		case age <= time.Duration(r.HourlyDays)*24*time.Hour:
+			bucket = "hour " + t.Format("2006-01-02 15")
+		case age <= time.Duration(r.DailyDays)*24*time.Hour:
+			bucket = "day " + t.Format("2006-01-02")
+		default:
+			keep[i] = i == 0
+			continue
+		}
+		keep[i] = i == 0 || !seen[bucket]
+		seen[bucket] = true
+	}
+	return keep
+}
+
+// pruneSnapshots applies the repo's retention to its snapshot chains and
+// git-air stash entries, at most once per pruneInterval. Branch history is
+// shared with other machines and never rewritten; queued snapshots wait for
+// git-air review.
+func pruneSnapshots(repoPath string, settings RepoSettings) {
+	if settings.Retention == nil || time.Since(lastPrune[repoPath]) < pruneInterval {
+		return
+	}
+	lastPrune[repoPath] = time.Now()
+
+	dropped := 0
+	output, _ := gitCommand("for-each-ref", "--format=%(refname)", snapshotRefPrefix).Output()
+	for _, ref := range strings.Fields(string(output)) {
+		n, err := pruneChain(ref, settings.Retention)
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  %s: could not prune %s: %v\n"), displayName(repoPath), ref, err)
+			continue
+		}
+		if n > 0 {
+			dropped += n
+			pushSnapshots(repoPath, ref)
+		}
+	}
+
+	// Drop from the oldest end, so the stash@{n} names to drop stay valid
+	entries := stashSnapshots()
+	times := make([]time.Time, len(entries))
+	for i, entry := range entries {
+		times[i] = entry.Time
+	}
+	keep := settings.Retention.keep(times, time.Now())
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !keep[i] && runGit("stash", "drop", "-q", entries[i].Name) {
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		fmt.Printf(tr("  🧹 %s: pruned %d old snapshot(s)\n"), displayName(repoPath), dropped)
+	}
+}
+
+// chainCommit is one snapshot of a chain: Base is the branch commit it
+// records as parent besides the previous snapshot, "" if none
+type chainCommit struct {
+	Commit string
+	Base   string
+	Time   time.Time
+}
+
+// pruneChain rewrites a snapshot chain without the snapshots retention
+// drops, keeping the dates and messages of the others, and returns how many
+// were dropped
+func pruneChain(ref string, retention *Retention) (int, error) {
+	output, err := gitOutput("log", "--first-parent", "--format=%H %ct %P%x09%s", ref)
+	if err != nil {
+		return 0, fmt.Errorf("%s", lastLine(output))
+	}
+
+	// Snapshots have the previous snapshot as first parent and the branch
+	// commit as second, except the oldest, whose only parent is the branch
+	// commit: the first commit that isn't a snapshot ends the chain
+	var chain []chainCommit
+	var oldestParents []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		header, subject, _ := strings.Cut(line, "\t")
+		if !strings.HasPrefix(subject, stashPrefix) {
+			break
+		}
+		fields := strings.Fields(header)
+		unix, _ := strconv.ParseInt(fields[1], 10, 64)
+		entry := chainCommit{Commit: fields[0], Time: time.Unix(unix, 0)}
+		if len(fields) == 4 {
+			entry.Base = fields[3]
+		}
+		chain = append(chain, entry)
+		oldestParents = fields[2:]
+	}
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	chain[len(chain)-1].Base = strings.Join(oldestParents, "")
+
+	times := make([]time.Time, len(chain))
+	for i, entry := range chain {
+		times[i] = entry.Time
+	}
+	keep := retention.keep(times, time.Now())
+
+	// Oldest first: snapshots before the first dropped one stay as they are
+	dropped := 0
+	var tip, base string
+	for i := len(chain) - 1; i >= 0; i-- {
+		entry := chain[i]
+		if entry.Base != "" {
+			base = entry.Base
+		}
+		if !keep[i] {
+			dropped++
+			continue
+		}
+		if dropped == 0 {
+			tip, base = entry.Commit, ""
+			continue
+		}
+
+		message, err := gitOutput("log", "-1", "--format=%B", entry.Commit)
+		if err != nil {
+			return 0, fmt.Errorf("%s", lastLine(message))
+		}
+		args := []string{"commit-tree", "--no-gpg-sign", entry.Commit + "^{tree}", "-m", strings.TrimSpace(message)}
+		if tip != "" {
+			args = append(args, "-p", tip)
+		}
+		if base != "" {
+			args = append(args, "-p", base)
+		}
+		cmd := gitCommand(args...)
+		date := fmt.Sprintf("%d +0000", entry.Time.Unix())
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		rewritten, err := cmd.Output()
+		if err != nil {
+			return 0, fmt.Errorf("rewriting %s failed", entry.Commit[:7])
+		}
+		tip, base = strings.TrimSpace(string(rewritten)), ""
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+	if output, err := gitOutput("update-ref", "-m", "git-air prune", ref, tip, chain[0].Commit); err != nil {
+		return 0, fmt.Errorf("%s", lastLine(output))
+	}
+	return dropped, nil
+}
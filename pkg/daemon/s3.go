@@ -0,0 +1,356 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config uploads bundle backups to S3-compatible object storage (AWS S3,
+// MinIO, Backblaze B2, ...). Credentials are read from environment variables.
+type S3Config struct {
+	Endpoint     string `json:"endpoint,omitempty"`       // e.g. https://s3.eu-central-1.amazonaws.com or http://nas:9000
+	Bucket       string `json:"bucket,omitempty"`         // empty disables uploads
+	Region       string `json:"region,omitempty"`         // default us-east-1
+	Prefix       string `json:"prefix,omitempty"`         // key prefix inside the bucket, default git-air
+	AccessKeyEnv string `json:"access_key_env,omitempty"` // default AWS_ACCESS_KEY_ID
+	SecretKeyEnv string `json:"secret_key_env,omitempty"` // default AWS_SECRET_ACCESS_KEY
+	PathStyle    bool   `json:"path_style,omitempty"`     // endpoint/bucket/key instead of bucket.endpoint/key (MinIO)
+}
+
+// s3Object is one entry of a bucket listing
+type s3Object struct {
+	Key string `xml:"Key"`
+}
+
+// s3Client signs requests with AWS Signature Version 4
+type s3Client struct {
+	cfg       S3Config
+	accessKey string
+	secretKey string
+	http      http.Client
+}
+
+// newS3Client checks the configuration and credentials
+func newS3Client(cfg S3Config) (*s3Client, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("s3 endpoint is not set")
+	}
+	accessEnv, secretEnv := cfg.AccessKeyEnv, cfg.SecretKeyEnv
+	if accessEnv == "" {
+		accessEnv = "AWS_ACCESS_KEY_ID"
+	}
+	if secretEnv == "" {
+		secretEnv = "AWS_SECRET_ACCESS_KEY"
+	}
+	client := &s3Client{
+		cfg:       cfg,
+		accessKey: os.Getenv(accessEnv),
+		secretKey: os.Getenv(secretEnv),
+		http:      http.Client{Timeout: 10 * time.Minute},
+	}
+	if client.accessKey == "" || client.secretKey == "" {
+		return nil, fmt.Errorf("%s and %s must be set", accessEnv, secretEnv)
+	}
+	if client.cfg.Region == "" {
+		client.cfg.Region = "us-east-1"
+	}
+	return client, nil
+}
+
+// key returns the full object key for a path below the configured prefix
+func (c *s3Client) key(path string) string {
+	prefix := c.cfg.Prefix
+	if prefix == "" {
+		prefix = "git-air"
+	}
+	return strings.Trim(prefix, "/") + "/" + path
+}
+
+// s3PartSize is the size of the parts of a multipart upload, and the size
+// above which files are uploaded in parts. A single PUT can't exceed 5 GB
+// and would have to start over after any error.
+const s3PartSize = 64 << 20
+
+// s3MaxParts is the most parts a multipart upload may have
+const s3MaxParts = 10000
+
+// put uploads a local file, streaming it from disk: in one request up to
+// s3PartSize, as a multipart upload above
+func (c *s3Client) put(key, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if size > s3PartSize {
+		return c.putMultipart(key, f, size)
+	}
+	hash, err := sectionSHA256(f, 0, size)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.send("PUT", key, nil, io.NewSectionReader(f, 0, size), size, hash)
+	return err
+}
+
+// putMultipart uploads a large file in parts, aborting the upload on errors
+// so the bucket isn't billed for orphaned parts
+func (c *s3Client) putMultipart(key string, f *os.File, size int64) error {
+	partSize := int64(s3PartSize)
+	if parts := (size + partSize - 1) / partSize; parts > s3MaxParts {
+		partSize = (size + s3MaxParts - 1) / s3MaxParts
+	}
+
+	body, err := c.do("POST", key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(body, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("POST %s: unexpected reply to starting the upload", key)
+	}
+
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var parts []part
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+partSize, number+1 {
+		length := partSize
+		if offset+length > size {
+			length = size - offset
+		}
+		hash, err := sectionSHA256(f, offset, length)
+		if err == nil {
+			var header http.Header
+			query := url.Values{"partNumber": {fmt.Sprint(number)}, "uploadId": {initiated.UploadID}}
+			if _, header, err = c.send("PUT", key, query, io.NewSectionReader(f, offset, length), length, hash); err == nil {
+				parts = append(parts, part{PartNumber: number, ETag: header.Get("ETag")})
+				continue
+			}
+		}
+		c.do("DELETE", key, url.Values{"uploadId": {initiated.UploadID}}, nil)
+		return err
+	}
+
+	complete, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	body, err = c.do("POST", key, url.Values{"uploadId": {initiated.UploadID}}, complete)
+	if err != nil {
+		c.do("DELETE", key, url.Values{"uploadId": {initiated.UploadID}}, nil)
+		return err
+	}
+	// Completing can fail after a 200 OK, with the error in the body
+	var failed struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &failed) == nil && failed.Code != "" {
+		c.do("DELETE", key, url.Values{"uploadId": {initiated.UploadID}}, nil)
+		return fmt.Errorf("POST %s: %s: %s", key, failed.Code, failed.Message)
+	}
+	return nil
+}
+
+// sectionSHA256 hashes length bytes of f from offset, for the signature of
+// a payload that is streamed afterwards
+func sectionSHA256(f *os.File, offset, length int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(f, offset, length)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// remove deletes an object
+func (c *s3Client) remove(key string) error {
+	_, err := c.do("DELETE", key, nil, nil)
+	return err
+}
+
+// list returns all object keys starting with prefix, sorted
+func (c *s3Client) list(prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := c.do("GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents              []s3Object `xml:"Contents"`
+			IsTruncated           bool       `xml:"IsTruncated"`
+			NextContinuationToken string     `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("unexpected listing: %v", err)
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// do sends a signed request for an object key (or the bucket when key is "")
+func (c *s3Client) do(method, key string, query url.Values, payload []byte) ([]byte, error) {
+	body, _, err := c.send(method, key, query, bytes.NewReader(payload), int64(len(payload)), sha256Hex(payload))
+	return body, err
+}
+
+// send sends a signed request whose payload, size bytes with the SHA-256
+// payloadHash, is read from body, and returns the reply and its headers
+func (c *s3Client) send(method, key string, query url.Values, payload io.Reader, size int64, payloadHash string) ([]byte, http.Header, error) {
+	endpoint, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid s3 endpoint: %v", err)
+	}
+
+	host := endpoint.Host
+	path := "/"
+	if c.cfg.PathStyle {
+		path += c.cfg.Bucket + "/"
+	} else {
+		host = c.cfg.Bucket + "." + host
+	}
+	path += key
+
+	canonicalQuery := ""
+	if query != nil {
+		canonicalQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+	reqURL := endpoint.Scheme + "://" + host + s3EscapePath(path)
+	if canonicalQuery != "" {
+		reqURL += "?" + canonicalQuery
+	}
+
+	if size == 0 {
+		payload = nil
+	}
+	req, err := http.NewRequest(method, reqURL, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.ContentLength = size // S3 refuses chunked uploads
+	c.sign(req, host, s3EscapePath(path), canonicalQuery, payloadHash, time.Now().UTC())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode >= 300 {
+		var s3Err struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		if xml.Unmarshal(body, &s3Err) == nil && s3Err.Code != "" {
+			return nil, nil, fmt.Errorf("%s %s: %s: %s", method, key, s3Err.Code, s3Err.Message)
+		}
+		return nil, nil, fmt.Errorf("%s %s: %s", method, key, resp.Status)
+	}
+	return body, resp.Header, nil
+}
+
+// sign adds the AWS Signature Version 4 authorization headers
+func (c *s3Client) sign(req *http.Request, host, path, query, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Host = host
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("x-amz-date", amzDate)
+
+	// Sign every x-amz-* header plus host and range, in sorted order
+	headers := map[string]string{"host": host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, query, canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+	scope := date + "/" + c.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	key = hmacSHA256(key, c.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+// s3EscapePath URI-encodes each path segment the way SigV4 expects
+func s3EscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/http/cgi"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ServeConfig enables the embedded smart-HTTP git server for peer sync on a LAN
+type ServeConfig struct {
+	Listen   string `json:"listen,omitempty"`    // e.g. ":8730", empty disables
+	TokenEnv string `json:"token_env,omitempty"` // env var holding the password peers must use, required
+	ReadOnly bool   `json:"read_only,omitempty"` // only allow fetching, not pushing
+}
+
+// startServer serves the watched repos at http://<host><listen>/<name>.git
+// through `git http-backend`. Peers add that URL as a remote, after which the
+// normal push and pull cycle syncs with them.
+func startServer(repos []string) {
+	if config.Serve.Listen == "" {
+		return
+	}
+
+	backend, err := gitCommand("--exec-path").Output()
+	if err != nil {
+		fmt.Printf(tr("❌ Git server: %v\n"), err)
+		return
+	}
+
+	// Pushes overwrite checked-out working trees, so nobody gets in without
+	// the password, not even read-only: the repos may hold private work
+	if config.Serve.TokenEnv == "" {
+		fmt.Println(tr("❌ Git server: serve.token_env is not set, not serving"))
+		return
+	}
+	token := os.Getenv(config.Serve.TokenEnv)
+	if token == "" {
+		fmt.Printf(tr("❌ Git server: %s is not set, not serving\n"), config.Serve.TokenEnv)
+		return
+	}
+
+	// Repos are served by base name; the first one wins on collisions
+	served := map[string]string{}
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(abs)
+		if other, ok := served[name]; ok {
+			fmt.Printf(tr("⚠️  Git server: %s not served, %s already uses the name %s\n"), repo, other, name)
+			continue
+		}
+		served[name] = abs
+	}
+
+	handler := &serveHandler{
+		backend: filepath.Join(strings.TrimSpace(string(backend)), "git-http-backend"),
+		repos:   served,
+		token:   token,
+	}
+	go func() {
+		if err := http.ListenAndServe(config.Serve.Listen, handler); err != nil {
+			fmt.Printf(tr("❌ Git server stopped: %v\n"), err)
+		}
+	}()
+
+	mode := "fetch and push"
+	if config.Serve.ReadOnly {
+		mode = "fetch only"
+	}
+	fmt.Printf(tr("🌐 Git server: serving %d repos on %s (%s)\n"), len(served), config.Serve.Listen, mode)
+}
+
+// serveHandler routes /<name>.git/... to git http-backend for that repo
+type serveHandler struct {
+	backend string
+	repos   map[string]string
+	token   string
+}
+
+func (h *serveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, password, ok := r.BasicAuth(); !ok || subtle.ConstantTimeCompare([]byte(password), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="git-air"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	// First path segment is the repo, e.g. /myproject.git/info/refs
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	name := strings.TrimSuffix(parts[0], ".git")
+	repo, ok := h.repos[name]
+	if !ok || len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	push := r.URL.Query().Get("service") == "git-receive-pack" || strings.HasSuffix(r.URL.Path, "/git-receive-pack")
+	if push && config.Serve.ReadOnly {
+		http.Error(w, "this git-air server is read-only", http.StatusForbidden)
+		return
+	}
+
+	// Pushes update the peer's checked-out branch and working tree, which git
+	// refuses if the working tree has uncommitted changes
+	env := []string{
+		"GIT_PROJECT_ROOT=" + filepath.Dir(repo),
+		"GIT_HTTP_EXPORT_ALL=1",
+		"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=http.receivepack",
+		fmt.Sprintf("GIT_CONFIG_VALUE_0=%t", !config.Serve.ReadOnly),
+		"GIT_CONFIG_KEY_1=receive.denyCurrentBranch",
+		"GIT_CONFIG_VALUE_1=updateInstead",
+	}
+	if path := os.Getenv("PATH"); path != "" {
+		env = append(env, "PATH="+path)
+	}
+
+	// http-backend expects PATH_INFO relative to the project root
+	r.URL.Path = "/" + filepath.Base(repo) + "/" + parts[1]
+	(&cgi.Handler{Path: h.backend, Dir: repo, Env: env}).ServeHTTP(w, r)
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"git-air/pkg/gitair"
+)
+
+// quitExitCode is the exit code when a second signal cuts git-air short,
+// 128 + SIGINT as shells report an interrupted command
+const quitExitCode = 130
+
+// stopRequested is closed by the first SIGINT or SIGTERM (or Quit in the
+// tray): the repo being synced is finished, the rest of the cycle skipped
+// and git-air exits with a summary
+var stopRequested = make(chan struct{})
+
+// gitContext is cancelled by a second signal, which interrupts the git
+// commands still running so git-air can quit at once
+var gitContext, quitGit = context.WithCancel(context.Background())
+
+// onStop runs before git-air exits on a stop request, e.g. removing the
+// PID file of a detached daemon
+var onStop []func()
+
+// runTotals counts what happened since git-air started, for the summary
+// printed when it is stopped
+var runTotals struct {
+	cycles, commits, pushes, pulls, failed int
+}
+
+// gitCommand returns a git command for the repo being worked in
+func gitCommand(args ...string) *exec.Cmd {
+	return gitCommandIn(repoDir, args...)
+}
+
+// gitCommandIn returns a git command run in dir, "" for the directory
+// git-air was started in, and bound to gitContext. Cancelling asks git to
+// terminate rather than killing it, so it removes its lock files. Other
+// goroutines than the main one use it instead of gitCommand.
+func gitCommandIn(dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(gitContext, "git", args...)
+	cmd.Dir = dir
+	cmd.Cancel = func() error {
+		return terminateProcess(cmd.Process.Pid)
+	}
+	return cmd
+}
+
+// openGit returns the repo at path for the gitair helpers, its git commands
+// bound to gitContext like those of gitCommand
+func openGit(path string) *gitair.GitRepo {
+	repo := gitair.Open(path)
+	repo.Command = func(args ...string) *exec.Cmd {
+		return gitCommandIn("", args...)
+	}
+	return repo
+}
+
+// stopping reports whether git-air was asked to stop
+func stopping() bool {
+	select {
+	case <-stopRequested:
+		return true
+	default:
+		return false
+	}
+}
+
+// requestStop asks the main loop to stop after the current repo
+func requestStop() {
+	if !stopping() {
+		close(stopRequested)
+		wake()
+	}
+}
+
+// handleStopSignals turns SIGINT and SIGTERM into a stop request; a second
+// one interrupts git and exits right away
+func handleStopSignals() {
+	events.Subscribe(countTotals)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Println(tr("\n⏹️  Stopping after the current repository (press Ctrl-C again to quit at once)"))
+		requestStop()
+		<-signals
+		fmt.Println(tr("\n⏹️  Quitting, git commands still running are interrupted"))
+		quitGit()
+		for _, fn := range onStop {
+			fn()
+		}
+		os.Exit(quitExitCode)
+	}()
+}
+
+// countTotals is subscribed to the event stream for the stop summary
+func countTotals(event gitair.Event) {
+	switch event.Type {
+	case gitair.Committed:
+		runTotals.commits++
+	case gitair.Pushed:
+		runTotals.pushes++
+	case gitair.PullMerged:
+		runTotals.pulls++
+	case gitair.Error:
+		runTotals.failed++
+	}
+}
+
+// stopNow ends git-air after a stop request with a summary of the run. The
+// exit code is that of --once: 0 when nothing failed, 1 otherwise.
+func stopNow() {
+	fmt.Printf(tr("\n⏹️  Stopped after %d cycle(s): %d commit(s), %d push(es), %d pull(s), %d failed\n"),
+		runTotals.cycles, runTotals.commits, runTotals.pushes, runTotals.pulls, runTotals.failed)
+	for _, fn := range onStop {
+		fn()
+	}
+	if runTotals.failed > 0 {
+		os.Exit(onceFailed)
+	}
+	os.Exit(onceSynced)
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// signingTimeout bounds the test signature; a pinentry waiting for input
+// would otherwise block the cycle
+const signingTimeout = 10 * time.Second
+
+// signingEnabled reports whether git signs commits in the current repo
+func signingEnabled() bool {
+	return gitConfigValue("--bool", "commit.gpgsign") == "true"
+}
+
+// signingProblem makes a test signature the way git would, but so that it
+// fails instead of prompting, and returns why signing would block or fail,
+// "" if it works (or the format can't be checked)
+func signingProblem() string {
+	ctx, cancel := context.WithTimeout(context.Background(), signingTimeout)
+	defer cancel()
+
+	key := gitConfigValue("user.signingkey")
+	var cmd *exec.Cmd
+	switch format := gitConfigValue("gpg.format"); format {
+	case "", "openpgp":
+		program := gitConfigValue("gpg.openpgp.program")
+		if program == "" {
+			program = gitConfigValue("gpg.program")
+		}
+		if program == "" {
+			program = "gpg"
+		}
+		args := []string{"--batch", "--no-tty", "--pinentry-mode", "error", "--detach-sign", "--output", os.DevNull}
+		if key != "" {
+			args = append(args, "--local-user", key)
+		}
+		cmd = exec.CommandContext(ctx, program, args...)
+	case "ssh":
+		if key == "" {
+			return "gpg.format is ssh but user.signingkey is not set"
+		}
+		program := gitConfigValue("gpg.ssh.program")
+		if program == "" {
+			program = "ssh-keygen"
+		}
+		args := []string{"-Y", "sign", "-n", "git"}
+		if literal, ok := strings.CutPrefix(key, "key::"); ok {
+			// A public key itself: the agent must hold the private half
+			file, err := os.CreateTemp("", "git-air-signing-*.pub")
+			if err != nil {
+				return ""
+			}
+			defer os.Remove(file.Name())
+			file.WriteString(literal + "\n")
+			file.Close()
+			key = file.Name()
+			args = append(args, "-U")
+		}
+		cmd = exec.CommandContext(ctx, program, append(args, "-f", expandHome(key))...)
+		cmd.Env = append(os.Environ(), "SSH_ASKPASS_REQUIRE=never")
+	default:
+		return "" // x509 and others are left to git
+	}
+
+	cmd.Stdin = strings.NewReader("git-air signing check\n")
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return "the signing program is waiting for a passphrase"
+	}
+	if err != nil {
+		return lastLine(string(output))
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// snapshotRefPrefix holds the snapshots of capture "snapshot" mode, one
+// chain per branch. Remotes get them under <prefix><host>/<branch>, so
+// machines syncing the same branch don't overwrite each other's chains.
+const snapshotRefPrefix = "refs/git-air/snapshots/"
+
+// snapshotRef is the local ref of the current branch's snapshot chain
+func snapshotRef() string {
+	branch := getCurrentBranch()
+	if branch == "" {
+		branch = "detached"
+	}
+	return snapshotRefPrefix + branch
+}
+
+// captureSnapshot records the changes of the current repo as a commit on
+// its snapshot ref, leaving HEAD, the branch and the index alone, and pushes
+// the ref to every remote. Each snapshot has the previous one as first
+// parent and, when it moved, HEAD as second, so the chain shows what the
+// branch was at the time. Returns true if a snapshot was added.
+func captureSnapshot(repoPath string, settings RepoSettings, held ...string) bool {
+	tree, err := snapshotTree(settings, held...)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %v\n"), displayName(repoPath), err)
+		return false
+	}
+
+	ref := snapshotRef()
+	args := []string{"commit-tree", "--no-gpg-sign", tree}
+	previous, err := gitOutput("rev-parse", "--verify", "-q", ref)
+	previous = strings.TrimSpace(previous)
+	if err == nil {
+		if last, _ := gitOutput("rev-parse", previous+"^{tree}"); strings.TrimSpace(last) == tree {
+			return false // Nothing new since the last snapshot
+		}
+		args = append(args, "-p", previous)
+	}
+	// HEAD joins the chain when it moved since the previous snapshot
+	if head, err := gitOutput("rev-parse", "--verify", "-q", "HEAD"); err == nil {
+		if previous == "" || !runGit("merge-base", "--is-ancestor", "HEAD", previous) {
+			args = append(args, "-p", strings.TrimSpace(head))
+		}
+	}
+
+	message := withTrailers(stashPrefix+time.Now().Format("2006-01-02 15:04:05"), hostTrailers(settings.hostTrailer()))
+	commit, err := gitOutput(append(args, "-m", message)...)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %s\n"), displayName(repoPath), lastLine(commit))
+		return false
+	}
+	commit = strings.TrimSpace(commit)
+	if !runGit("update-ref", "-m", "git-air snapshot", ref, commit) {
+		fmt.Printf(tr("  ❌ %s: could not store the snapshot\n"), displayName(repoPath))
+		return false
+	}
+	stat, _ := gitOutput("diff", "--shortstat", "HEAD", commit)
+	fmt.Printf(tr("  📸 %s: snapshot in %s (%s)\n"), displayName(repoPath), ref, strings.TrimSpace(stat))
+
+	pushSnapshots(repoPath, ref)
+	return true
+}
+
+// pushSnapshots pushes a snapshot ref to every remote. Chains only grow,
+// except when pruned, so the push is forced; while pushes are held the next
+// snapshot carries this one along.
+func pushSnapshots(repoPath, ref string) {
+	if networkHeld != "" {
+		return
+	}
+	remoteRef := snapshotRefPrefix + hostLabel() + "/" + strings.TrimPrefix(ref, snapshotRefPrefix)
+	for _, remote := range getRemotes() {
+		output, err := gitNetwork("push", "-q", remote, "+"+ref+":"+remoteRef)
+		checkHostKey(repoPath, remote, output, err != nil)
+		if err != nil {
+			publish(errorEvent("push", remote, output))
+		}
+	}
+}
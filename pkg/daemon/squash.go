@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsquashedRefPrefix keeps the granular commits a squash replaced, as
+// <prefix><branch>/<timestamp>, until branch_retention_days
+const unsquashedRefPrefix = "refs/git-air/unsquashed/"
+
+// unpushedCommits lists the commits of HEAD no remote has, oldest first;
+// none without remotes, where there is nothing to push
+func unpushedCommits() []string {
+	if len(getRemotes()) == 0 {
+		return nil
+	}
+	output, err := gitOutput("rev-list", "--reverse", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(output)
+}
+
+// squashWait returns why the current repo's push waits with
+// squash_push_minutes, "" once its oldest unpushed commit is old enough.
+// Going by the commit date keeps the cadence across restarts.
+func squashWait(settings RepoSettings) string {
+	cadence := settings.squashPushInterval()
+	commits := unpushedCommits()
+	if cadence == 0 || len(commits) == 0 {
+		return ""
+	}
+	date, err := gitOutput("log", "-1", "--format=%ct", commits[0])
+	if err != nil {
+		return ""
+	}
+	unix, _ := strconv.ParseInt(strings.TrimSpace(date), 10, 64)
+	due := time.Unix(unix, 0).Add(cadence)
+	if !time.Now().Before(due) {
+		return ""
+	}
+	return fmt.Sprintf(tr("squash on push: %d commit(s), pushing at %s"), len(commits), due.Format("15:04"))
+}
+
+// squashUnpushed turns the current repo's unpushed auto-commits into one
+// commit before they are pushed. It leaves them alone if any of them is a
+// merge, tagged or written by hand, since those should reach the remote
+// as they are.
+func squashUnpushed(repoPath string, settings RepoSettings) {
+	commits := unpushedCommits()
+	if settings.squashPushInterval() == 0 || len(commits) < 2 {
+		return
+	}
+	for _, commit := range commits {
+		info, err := gitOutput("log", "-1", "--format=%P%x00%D%x00%B", commit)
+		if err != nil {
+			return
+		}
+		parents, rest, _ := strings.Cut(info, "\x00")
+		refs, message, _ := strings.Cut(rest, "\x00")
+		if len(strings.Fields(parents)) != 1 || strings.Contains(refs, "tag: ") || !isAutoCommit(message) {
+			fmt.Printf(tr("  🧩 %s: not squashing, the unpushed commits include merges, tags or manual commits\n"), displayName(repoPath))
+			return
+		}
+	}
+	base, err := gitOutput("rev-parse", commits[0]+"^")
+	if err != nil {
+		return
+	}
+	base = strings.TrimSpace(base)
+	tip := commits[len(commits)-1]
+
+	// The granular commits stay reachable for recovery
+	branch := getCurrentBranch()
+	if branch == "" {
+		branch = "detached"
+	}
+	keep := unsquashedRefPrefix + branch + "/" + time.Now().Format("20060102-150405")
+	if !runGit("update-ref", keep, tip) {
+		fmt.Printf(tr("  ⚠️  %s: could not keep the commits to squash, pushing them as they are\n"), displayName(repoPath))
+		return
+	}
+
+	// With ai_messages the provider summarizes the whole run
+	useAI := settings.aiMessages()
+	rules, _ := settings.commitlintRules(useAI)
+	summary := fallbackMessage(rules, false)
+	var trailers []string
+	if useAI {
+		if message := aiSquashMessage(displayName(repoPath), rules, settings, commits); message != "" {
+			summary = message
+			trailers = append(trailers, aiMessageTrailer)
+		}
+	}
+	message := squashMessage(commits, summary)
+	message = withTrailers(message, append(trailers, hostTrailers(settings.hostTrailer())...))
+	if !runGit("reset", "--soft", base) {
+		return
+	}
+	if output, err := gitOutput("commit", "-q", "--no-verify", "-m", message); err != nil {
+		runGit("reset", "--soft", tip)
+		fmt.Printf(tr("  ⚠️  %s: squashing failed, pushing the commits as they are: %s\n"), displayName(repoPath), lastLine(output))
+		return
+	}
+	noteCommit(settings, "squash", len(trailers) > 0)
+	fmt.Printf(tr("  🧩 %s: squashed %d auto-commits into one (originals kept in %s)\n"), displayName(repoPath), len(commits), keep)
+}
+
+// squashMessage is the message plus a line saying which commits were squashed
+func squashMessage(commits []string, message string) string {
+	first, _ := gitOutput("log", "-1", "--format=%ci", commits[0])
+	last, _ := gitOutput("log", "-1", "--format=%ci", commits[len(commits)-1])
+	return fmt.Sprintf("%s\n\nSquashes %d auto-commits made between %s and %s.", message, len(commits), strings.TrimSpace(first), strings.TrimSpace(last))
+}
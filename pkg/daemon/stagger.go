@@ -0,0 +1,31 @@
+package daemon
+
+import "time"
+
+// processStaggered processes the repos one by one, spread evenly across
+// period instead of all at once, and watches the immediate paths in between.
+// Returns whether any repo had changes and how much of period is left.
+func processStaggered(repos, order []string, period time.Duration) (bool, time.Duration) {
+	start := time.Now()
+	slot := period / time.Duration(len(order))
+	changesFound := false
+	for i, repo := range order {
+		if wait := time.Until(start.Add(time.Duration(i) * slot)); wait > 0 {
+			if sleepWatchingImmediate(repos, wait) {
+				slot = 0 // "Sync now": the remaining repos go right away
+			}
+		}
+		if interrupted() {
+			break
+		}
+		if syncRepo(repo) {
+			changesFound = true
+		}
+	}
+
+	left := time.Until(start.Add(period))
+	if left < 0 {
+		left = 0
+	}
+	return changesFound, left
+}
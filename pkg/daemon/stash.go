@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stashPrefix starts the message of every stash entry git-air records
+const stashPrefix = "git-air snapshot "
+
+// maxStashSnapshots is how many git-air entries the stash keeps; older ones
+// are dropped, the user's own entries never
+const maxStashSnapshots = 100
+
+// stashEntry is a git-air entry of the stash
+type stashEntry struct {
+	Name   string // "stash@{n}"
+	Commit string
+	Time   time.Time
+}
+
+// stashSnapshots lists the git-air entries of the stash, newest first
+func stashSnapshots() []stashEntry {
+	output, err := gitCommand("stash", "list", "--format=%gd %H %ct %gs").Output()
+	if err != nil {
+		return nil
+	}
+	var entries []stashEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) == 4 && strings.Contains(fields[3], stashPrefix) {
+			unix, _ := strconv.ParseInt(fields[2], 10, 64)
+			entries = append(entries, stashEntry{Name: fields[0], Commit: fields[1], Time: time.Unix(unix, 0)})
+		}
+	}
+	return entries
+}
+
+// captureStash records the changes of the current repo as a stash entry,
+// leaving the branch, index and working tree alone, if they differ from the
+// last git-air entry. Restore one with git stash apply. Returns true if an
+// entry was added.
+func captureStash(repoPath string, settings RepoSettings, held ...string) bool {
+	tree, err := snapshotTree(settings, held...)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %v\n"), displayName(repoPath), err)
+		return false
+	}
+	if entries := stashSnapshots(); len(entries) > 0 {
+		if last, _ := gitOutput("rev-parse", entries[0].Commit+"^{tree}"); strings.TrimSpace(last) == tree {
+			return false // Nothing new since the last snapshot
+		}
+	}
+
+	// A stash entry is a commit of the working tree with HEAD and a commit
+	// of the index as parents; new files go into the working tree commit so
+	// git stash apply restores them too
+	head, err := gitOutput("rev-parse", "HEAD")
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %s\n"), displayName(repoPath), lastLine(head))
+		return false
+	}
+	head = strings.TrimSpace(head)
+	index, err := gitOutput("write-tree")
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %s\n"), displayName(repoPath), lastLine(index))
+		return false
+	}
+	message := stashPrefix + time.Now().Format("2006-01-02 15:04:05")
+	indexCommit, err := gitOutput("commit-tree", "--no-gpg-sign", strings.TrimSpace(index), "-p", head, "-m", "index on "+message)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %s\n"), displayName(repoPath), lastLine(indexCommit))
+		return false
+	}
+	commit, err := gitOutput("commit-tree", "--no-gpg-sign", tree, "-p", head, "-p", strings.TrimSpace(indexCommit), "-m", message)
+	if err != nil {
+		fmt.Printf(tr("  ❌ %s: could not snapshot changes: %s\n"), displayName(repoPath), lastLine(commit))
+		return false
+	}
+	if output, err := gitOutput("stash", "store", "-m", message, strings.TrimSpace(commit)); err != nil {
+		fmt.Printf(tr("  ❌ %s: could not store the snapshot: %s\n"), displayName(repoPath), lastLine(output))
+		return false
+	}
+
+	// Drop from the oldest end, so the stash@{n} names to drop stay valid
+	entries := stashSnapshots()
+	for i := len(entries) - 1; i >= maxStashSnapshots; i-- {
+		runGit("stash", "drop", "-q", entries[i].Name)
+	}
+
+	stat, _ := gitOutput("diff", "--shortstat", head, strings.TrimSpace(commit))
+	fmt.Printf(tr("  📸 %s: stashed snapshot (%s), restore with git stash apply\n"), displayName(repoPath), strings.TrimSpace(stat))
+	return true
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitStats are the per-repo numbers `git-air stats` prints
+type commitStats struct {
+	auto, manual  int
+	lines, files  int
+	hours         [24]int
+	pushes, total int
+}
+
+// runStats implements `git-air stats [repo...]`
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	days := fs.Int("days", 30, "Number of days to look back")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air stats [--days N] [repo...]"))
+		fmt.Println(tr("\nShows auto vs manual commits, average commit size, busiest hours and"))
+		fmt.Println(tr("push success rate. Without repos, all repos below the current directory."))
+	}
+	repos := parseInterspersed(fs, args)
+
+	if len(repos) == 0 {
+		found, err := findGitRepos(".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ Error finding repositories: %v\n"), err)
+			return 1
+		}
+		repos = found
+	}
+	if len(repos) == 0 {
+		fmt.Println(tr("⚠️  No Git repositories found in current directory"))
+		return 0
+	}
+
+	since := time.Now().AddDate(0, 0, -*days)
+	events, err := readEvents(since, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, tr("⚠️  State store unavailable, push rates omitted: %v\n"), err)
+	}
+
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		stats, err := repoCommitStats(abs, since)
+		if err != nil {
+			fmt.Printf("📊 %s: ❌ %v\n\n", repo, err)
+			continue
+		}
+		for _, event := range events {
+			if event.Repo == abs && event.Kind == "push" {
+				stats.total++
+				if event.OK {
+					stats.pushes++
+				}
+			}
+		}
+		printCommitStats(repo, *days, stats)
+	}
+	return 0
+}
+
+// repoCommitStats reads the history of a repository since the given time
+func repoCommitStats(repo string, since time.Time) (commitStats, error) {
+	var stats commitStats
+	cmd := gitCommand("log", "--since="+since.Format(time.RFC3339), "--date=format-local:%H",
+		"--numstat", "--format=%x1e%ad%x1f%s%x1f%(trailers:only,unfold)%x1f")
+	cmd.Dir = repo
+	output, err := cmd.Output()
+	if err != nil {
+		return stats, fmt.Errorf("reading history failed")
+	}
+
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(record, "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		if hour, err := strconv.Atoi(fields[0]); err == nil && hour >= 0 && hour < 24 {
+			stats.hours[hour]++
+		}
+		if isAutoCommit(fields[1] + "\n\n" + fields[2]) {
+			stats.auto++
+		} else {
+			stats.manual++
+		}
+		for _, line := range strings.Split(strings.TrimSpace(fields[3]), "\n") {
+			numbers := strings.Fields(line)
+			if len(numbers) < 3 {
+				continue
+			}
+			stats.files++
+			added, _ := strconv.Atoi(numbers[0])
+			deleted, _ := strconv.Atoi(numbers[1])
+			stats.lines += added + deleted
+		}
+	}
+	return stats, nil
+}
+
+// printCommitStats renders one repository's stats block
+func printCommitStats(repo string, days int, stats commitStats) {
+	commits := stats.auto + stats.manual
+	fmt.Printf(tr("📊 %s (last %d days)\n"), repo, days)
+	fmt.Printf(tr("  Commits:       %d (%d auto, %d manual)\n"), commits, stats.auto, stats.manual)
+	if commits > 0 {
+		fmt.Printf(tr("  Average size:  %.1f lines, %.1f files\n"),
+			float64(stats.lines)/float64(commits), float64(stats.files)/float64(commits))
+
+		hours := make([]int, 24)
+		for i := range hours {
+			hours[i] = i
+		}
+		sort.SliceStable(hours, func(i, j int) bool { return stats.hours[hours[i]] > stats.hours[hours[j]] })
+		var busiest []string
+		for _, hour := range hours[:3] {
+			if stats.hours[hour] > 0 {
+				busiest = append(busiest, fmt.Sprintf("%02d:00 (%d)", hour, stats.hours[hour]))
+			}
+		}
+		fmt.Printf(tr("  Busiest hours: %s\n"), strings.Join(busiest, ", "))
+	}
+	if stats.total > 0 {
+		fmt.Printf(tr("  Pushes:        %d/%d succeeded (%.0f%%)\n"), stats.pushes, stats.total,
+			100*float64(stats.pushes)/float64(stats.total))
+	} else {
+		fmt.Printf(tr("  Pushes:        none recorded\n"))
+	}
+	fmt.Println()
+}
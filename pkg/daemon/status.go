@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+
+	"git-air/pkg/gitair"
+)
+
+// fsmonitorBuiltin records whether this git has the built-in fsmonitor daemon,
+// nil until checked
+var fsmonitorBuiltin *bool
+
+// statusSpeedups caches the -c options for git status per repository
+var statusSpeedups = map[string][]string{}
+
+// gitStatus lists every change in the current repo, read in-process by
+// go-git where it sees the repo the way git does, otherwise parsed from git
+// status --porcelain=v2. Status uses the v1 two-letter codes ("??" for
+// untracked, " M" for modified in the worktree). A failed status is an
+// error with git's last line, never an empty list that would pass for clean.
+func gitStatus() ([]fileChange, error) {
+	if repo := goGitRepo(); repo != nil {
+		if changes, err := repo.Status(); err == nil {
+			return changes, nil
+		}
+	}
+
+	args := append(statusOptions(), "status", "--porcelain=v2", "-z", "--untracked-files=all")
+	output, err := gitCommand(args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, errors.New(lastLine(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	return gitair.ParseStatus(output), nil
+}
+
+// goGitRepo opens the current repo with go-git, nil where git has to do
+// the work: the repos gitair.OpenGoGit refuses, and those on network file
+// systems, where go-git would read every file that git only stats
+func goGitRepo() *gitair.GoGitRepo {
+	dir := getCurrentDir()
+	if safeModeRepos[dir] != "" {
+		return nil
+	}
+	repo, err := gitair.OpenGoGit(dir)
+	if err != nil {
+		return nil
+	}
+	repo.GitRepo = openGit(dir)
+	return repo
+}
+
+// commitStaged commits the index of the current repo with options for git
+// commit, through go-git where it can do the same (gitair.GoGitRepo.Commit)
+func commitStaged(message string, options ...string) error {
+	if repo := goGitRepo(); repo != nil {
+		return repo.Commit(message, options...)
+	}
+	return openGit(getCurrentDir()).Commit(message, options...)
+}
+
+// statusOptions turns on the untracked cache and the built-in fsmonitor for
+// the current repo unless its config already decides, so status in huge
+// repos doesn't rescan the whole tree every cycle
+func statusOptions() []string {
+	dir := getCurrentDir()
+	if options, ok := statusSpeedups[dir]; ok {
+		return options
+	}
+
+	// Neither cache is reliable on network file systems, and the fsmonitor
+	// daemon can't watch them
+	var options []string
+	if safeModeRepos[dir] != "" {
+		statusSpeedups[dir] = options
+		return options
+	}
+	if !gitConfigSet("core.untrackedCache") {
+		options = append(options, "-c", "core.untrackedCache=true")
+	}
+	if fsmonitorBuiltin == nil {
+		output, _ := gitCommand("version", "--build-options").Output()
+		available := strings.Contains(string(output), "fsmonitor--daemon")
+		fsmonitorBuiltin = &available
+	}
+	if *fsmonitorBuiltin && !gitConfigSet("core.fsmonitor") {
+		options = append(options, "-c", "core.fsmonitor=true")
+	}
+	statusSpeedups[dir] = options
+	return options
+}
+
+// gitConfigSet reports whether a git config key has a value for the current repo
+func gitConfigSet(key string) bool {
+	return gitCommand("config", "--get", key).Run() == nil
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// heldPointers returns the submodules whose new pointer in the current repo
+// references a commit that none of the submodule's remotes has, and unstages
+// those bumps, so the superproject never pushes a SHA nobody can fetch
+func heldPointers(repoPath string) []string {
+	output, err := gitCommand("diff", "HEAD", "--raw", "--no-abbrev", "-z").Output()
+	if err != nil {
+		return nil
+	}
+
+	var held []string
+	fields := strings.Split(string(output), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		// :oldmode newmode oldsha newsha status, then the path
+		parts := strings.Fields(fields[i])
+		if len(parts) < 5 || parts[1] != "160000" {
+			continue
+		}
+		path := fields[i+1]
+		if commitPublished(path, parts[3]) {
+			continue
+		}
+		held = append(held, path)
+		gitCommand("reset", "-q", "--", path).Run()
+	}
+
+	if len(held) == 0 {
+		clearAlert(repoPath, "pointer")
+		return nil
+	}
+	fmt.Printf(tr("  ⏸️  Holding the pointer bump of %s until the submodule commit is pushed\n"), describeCount(held))
+	raiseAlert(repoPath, "pointer", fmt.Sprintf(tr("%s: submodule pointer bump of %s not committed, the submodule commit isn't on its remote yet"), displayName(repoPath), describeCount(held)))
+	return held
+}
+
+// commitPublished reports whether a submodule commit is on one of the
+// submodule's remote-tracking branches. A worktree pointer (all zeros in the
+// raw diff) is checked through the submodule's HEAD.
+func commitPublished(path, sha string) bool {
+	if strings.Trim(sha, "0") == "" {
+		sha = "HEAD"
+	}
+	output, err := gitCommand("-C", path, "rev-list", "--count", sha, "--not", "--remotes").Output()
+	return err == nil && strings.TrimSpace(string(output)) == "0"
+}
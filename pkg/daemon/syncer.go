@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"git-air/pkg/gitair"
+)
+
+// Syncer is the daemon's gitair.Syncer: processRepo with the config
+// rules, alerts, capture modes and deferred pushes, its Result gathered from
+// the events processRepo publishes. In a dry run Committed means changes
+// that would be committed. Other programs run it with
+// airsync.Runner{Syncer: daemon.Syncer{}} after LoadConfig.
+type Syncer struct{}
+
+// syncer is what the main loop, staggered cycles, immediate paths and
+// editor or watch triggers sync every repo with
+var syncer gitair.Syncer = Syncer{}
+
+// Sync runs processRepo on the repo. A failed commit is the error; failed
+// pushes are listed in Result.Failed.
+func (Syncer) Sync(repo gitair.Repo) (gitair.Result, error) {
+	var result gitair.Result
+	var err error
+	abs, _ := filepath.Abs(repo.Path())
+	unsubscribe := events.Subscribe(func(event gitair.Event) {
+		if event.Repo != abs {
+			return
+		}
+		switch {
+		case event.Type == gitair.Committed:
+			result.Message = event.Message
+		case event.Type == gitair.Pushed:
+			result.Pushed = append(result.Pushed, event.Remote)
+		case event.Type == gitair.Error && event.Op == "push":
+			if result.Failed == nil {
+				result.Failed = map[string]string{}
+			}
+			result.Failed[event.Remote] = event.Err
+		case event.Type == gitair.Error && event.Op == "commit":
+			err = fmt.Errorf("%s: commit: %s", repo.Path(), event.Err)
+		}
+	})
+	defer unsubscribe()
+
+	result.Committed = processRepo(repo.Path(), forceMonorepo)
+	return result, err
+}
+
+// syncRepo syncs one repo through syncer and reports whether it committed.
+// Failures need no handling here, the console and the state store get
+// them from the event stream.
+func syncRepo(repoPath string) bool {
+	result, _ := syncer.Sync(openGit(repoPath))
+	return result.Committed
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// versionFiles are the files whose version field triggers a release tag
+var versionFiles = []string{"package.json", "Cargo.toml", "VERSION"}
+
+// cargoVersion matches the version line of a Cargo.toml [package] section
+var cargoVersion = regexp.MustCompile(`^version\s*=\s*"([^"]+)"`)
+
+// tagVersionBump creates an annotated tag when the last commit changed the
+// version in one of the version files, and returns the tag name ("" if none)
+func tagVersionBump(prefix string) string {
+	if !runGit("rev-parse", "-q", "--verify", "HEAD~1") {
+		return "" // First commit, nothing to compare against
+	}
+
+	for _, file := range versionFiles {
+		version := versionAt("HEAD", file)
+		if version == "" || version == versionAt("HEAD~1", file) {
+			continue
+		}
+
+		tag := prefix + version
+		if runGit("rev-parse", "-q", "--verify", "refs/tags/"+tag) {
+			return "" // Already tagged by the release tooling
+		}
+		if output, err := gitOutput("tag", "-a", tag, "-m", "Release "+tag+" (tagged by git-air)"); err != nil {
+			fmt.Printf(tr("  ⚠️  Tagging %s failed: %s\n"), tag, lastLine(output))
+			return ""
+		}
+		fmt.Printf(tr("  🏷️  Version bump in %s, tagged %s\n"), file, tag)
+		return tag
+	}
+	return ""
+}
+
+// versionAt reads the version declared in a file at the given revision
+func versionAt(rev, file string) string {
+	cmd := gitCommand("show", rev+":"+file)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	switch file {
+	case "package.json":
+		var pkg struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(output, &pkg) != nil {
+			return ""
+		}
+		return pkg.Version
+	case "Cargo.toml":
+		inPackage := false
+		for _, line := range strings.Split(string(output), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				inPackage = line == "[package]"
+				continue
+			}
+			if m := cargoVersion.FindStringSubmatch(line); inPackage && m != nil {
+				return m[1]
+			}
+		}
+		return ""
+	default:
+		return strings.TrimSpace(string(output))
+	}
+}
+
+// pushTagToAllRemotes pushes a tag to every configured remote
+func pushTagToAllRemotes(tag string) {
+	for _, remote := range getRemotes() {
+		fmt.Printf(tr("  🏷️  Pushing %s to %s..."), tag, remote)
+		if _, err := gitNetwork("push", remote, "refs/tags/"+tag); err == nil {
+			fmt.Printf(" ✓\n")
+		} else {
+			fmt.Printf(tr(" ❌ failed\n"))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Template describes the files and settings a newly adopted repository starts with
+type Template struct {
+	Branch    string `json:"branch,omitempty"`    // initial branch name, e.g. "main"
+	Gitignore string `json:"gitignore,omitempty"` // "auto" (by detected language) or path to a file
+	License   string `json:"license,omitempty"`   // "MIT" or path to a license file
+	Readme    bool   `json:"readme,omitempty"`    // create a README.md stub
+}
+
+// languageMarkers detect a project's language from files in its root
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "go"},
+	{"Cargo.toml", "rust"},
+	{"package.json", "node"},
+	{"pyproject.toml", "python"},
+	{"requirements.txt", "python"},
+	{"setup.py", "python"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"build.gradle.kts", "java"},
+}
+
+// languageIgnores are the default .gitignore entries per language
+var languageIgnores = map[string][]string{
+	"go":     {"/bin/", "*.test", "*.out"},
+	"rust":   {"/target/"},
+	"node":   {"node_modules/", "dist/", "npm-debug.log*", ".env"},
+	"python": {"__pycache__/", "*.py[cod]", ".venv/", "venv/", "*.egg-info/", ".pytest_cache/"},
+	"java":   {"target/", "build/", ".gradle/", "*.class"},
+}
+
+// mitLicense is the MIT license text, filled with year and copyright holder
+const mitLicense = `MIT License
+
+Copyright (c) %d %s
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+// applyTemplate writes the template files into the current repo. Files
+// that already exist are never overwritten.
+func applyTemplate(tmpl Template, name string) {
+	if tmpl.Gitignore != "" {
+		var content string
+		if tmpl.Gitignore == "auto" {
+			if language := detectLanguage(); language != "" {
+				content = strings.Join(languageIgnores[language], "\n") + "\n"
+			}
+		} else if data, err := os.ReadFile(inRepo(expandHome(tmpl.Gitignore))); err == nil {
+			content = string(data)
+		} else {
+			fmt.Printf(tr("  ⚠️  Template .gitignore: %v\n"), err)
+		}
+		writeTemplateFile(".gitignore", content)
+	}
+
+	if tmpl.License != "" {
+		var content string
+		if strings.EqualFold(tmpl.License, "MIT") {
+			holder := gitConfigValue("user.name")
+			content = fmt.Sprintf(mitLicense, time.Now().Year(), holder)
+		} else if data, err := os.ReadFile(inRepo(expandHome(tmpl.License))); err == nil {
+			content = string(data)
+		} else {
+			fmt.Printf(tr("  ⚠️  Template license: %v\n"), err)
+		}
+		writeTemplateFile("LICENSE", content)
+	}
+
+	if tmpl.Readme {
+		writeTemplateFile("README.md", "# "+name+"\n")
+	}
+}
+
+// writeTemplateFile creates a file unless it exists or the content is empty
+func writeTemplateFile(name, content string) {
+	if content == "" {
+		return
+	}
+	if _, err := os.Stat(inRepo(name)); err == nil {
+		return
+	}
+	if err := os.WriteFile(inRepo(name), []byte(content), 0644); err != nil {
+		fmt.Printf(tr("  ⚠️  Could not write %s: %v\n"), name, err)
+		return
+	}
+	fmt.Printf(tr("  📄 Added %s from template\n"), name)
+}
+
+// detectLanguage guesses the project language from marker files in the current repo
+func detectLanguage() string {
+	for _, marker := range languageMarkers {
+		if _, err := os.Stat(inRepo(marker.file)); err == nil {
+			return marker.language
+		}
+	}
+	if matches, _ := filepath.Glob(inRepo("*.py")); len(matches) > 0 {
+		return "python"
+	}
+	return ""
+}
@@ -0,0 +1,205 @@
+package daemon
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// recentEventCount is how many events "Recent events" shows
+const recentEventCount = 50
+
+// trayEnabled is set by `git-air tray`
+var trayEnabled bool
+
+// paused stops commits, pushes and pulls until resumed from the tray
+var paused atomic.Bool
+
+// syncNow cuts the current sleep short, for "Sync now" and resuming
+var syncNow = make(chan struct{}, 1)
+
+// trayIcon is a running tray helper. set shows a state ("green", "yellow"
+// or "red") with a tooltip; commands delivers the menu choices: "toggle"
+// (pause/resume), "sync", "events" and "quit".
+type trayIcon struct {
+	set      func(state, tooltip string, paused bool)
+	commands <-chan string
+	stop     func()
+}
+
+var (
+	tray *trayIcon
+
+	// trayMu guards what the tray goroutine reads: the last state and the
+	// recent events
+	trayMu       sync.Mutex
+	trayState    = "green"
+	trayTooltip  = "Git Air"
+	recentEvents []gitair.Event
+)
+
+// runTray implements `git-air tray [options]`: the daemon with a tray icon
+func runTray(args []string) int {
+	flag.CommandLine.Parse(args)
+	trayEnabled = true
+	runDaemon()
+	return 0
+}
+
+// startTray opens the tray icon and starts handling its menu. Without a
+// tray helper git-air keeps running on the console.
+func startTray(repoCount int) {
+	if !trayEnabled {
+		return
+	}
+	icon, err := openTray()
+	if err != nil {
+		fmt.Printf(tr("⚠️  Tray: %v - running without an icon\n"), err)
+		return
+	}
+	tray = icon
+	events.Subscribe(rememberEvent)
+	updateTray(repoCount)
+	fmt.Println(tr("🟢 Tray: icon shown (pause, sync now and recent events in its menu)"))
+
+	go func() {
+		for command := range icon.commands {
+			handleTrayCommand(command)
+		}
+		fmt.Println(tr("⚠️  Tray: the icon was closed, git-air keeps running"))
+	}()
+}
+
+// handleTrayCommand acts on a menu choice, in the tray goroutine
+func handleTrayCommand(command string) {
+	switch command {
+	case "toggle":
+		if paused.Load() {
+			paused.Store(false)
+			fmt.Println(tr("▶️  Resumed from the tray"))
+		} else {
+			paused.Store(true)
+			fmt.Println(tr("⏸️  Paused from the tray: no commits, pushes or pulls until resumed"))
+		}
+		wake() // Resuming starts a cycle right away, pausing shows it at once
+	case "sync":
+		fmt.Println(tr("🔄 Sync requested from the tray"))
+		paused.Store(false)
+		wake()
+	case "events":
+		showRecentEvents()
+	case "quit":
+		fmt.Println(tr("👋 Quit from the tray"))
+		tray.stop()
+		requestStop()
+	}
+}
+
+// wake ends the main loop's current sleep
+func wake() {
+	select {
+	case syncNow <- struct{}{}:
+	default:
+	}
+}
+
+// updateTray shows the aggregate status: red while alerts are open, yellow
+// while paused or pushes wait, green otherwise. Runs on the main goroutine,
+// which owns the alerts.
+func updateTray(repoCount int) {
+	if tray == nil {
+		return
+	}
+	state, tooltip := "green", fmt.Sprintf(tr("Git Air: %d repo(s) in sync"), repoCount)
+	switch {
+	case paused.Load():
+		state, tooltip = "yellow", tr("Git Air: paused")
+	case len(alerts) > 0:
+		state, tooltip = "red", fmt.Sprintf(tr("Git Air: %d problem(s), see recent events"), len(alerts))
+	case networkHeld != "":
+		state, tooltip = "yellow", fmt.Sprintf(tr("Git Air: pushes and pulls paused (%s)"), networkHeld)
+	case len(deferredPushes) > 0:
+		state, tooltip = "yellow", fmt.Sprintf(tr("Git Air: %d repo(s) waiting to push"), len(deferredPushes))
+	}
+
+	trayMu.Lock()
+	changed := state != trayState || tooltip != trayTooltip
+	trayState, trayTooltip = state, tooltip
+	trayMu.Unlock()
+	if changed {
+		tray.set(state, tooltip, paused.Load())
+	}
+}
+
+// rememberEvent keeps the latest events for "Recent events"
+func rememberEvent(event gitair.Event) {
+	switch event.Type {
+	case gitair.RepoDirty:
+		return // Every commit follows one, it would only double the list
+	case gitair.UpToDate:
+		return // Every cycle has them
+	}
+	trayMu.Lock()
+	defer trayMu.Unlock()
+	recentEvents = append(recentEvents, event)
+	if len(recentEvents) > recentEventCount {
+		recentEvents = recentEvents[len(recentEvents)-recentEventCount:]
+	}
+}
+
+// showRecentEvents writes the status and the recent events, newest first,
+// to a file in the state dir and opens it
+func showRecentEvents() {
+	trayMu.Lock()
+	var lines []string
+	lines = append(lines, trayTooltip, "")
+	for i := len(recentEvents) - 1; i >= 0; i-- {
+		lines = append(lines, describeEvent(recentEvents[i]))
+	}
+	trayMu.Unlock()
+	if len(lines) == 2 {
+		lines = append(lines, "Nothing happened since git-air started.")
+	}
+
+	file := filepath.Join(stateDir(), "recent-events.txt")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		fmt.Printf(tr("⚠️  Tray: %v\n"), err)
+		return
+	}
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		fmt.Printf(tr("⚠️  Tray: %v\n"), err)
+		return
+	}
+	if err := openFile(file); err != nil {
+		fmt.Printf(tr("⚠️  Tray: could not open %s: %v\n"), file, err)
+	}
+}
+
+// describeEvent is one line of the recent events list
+func describeEvent(event gitair.Event) string {
+	line := event.Time.Format(time.DateTime) + "  " + filepath.Base(event.Repo) + ": "
+	switch event.Type {
+	case gitair.Committed:
+		subject, _, _ := strings.Cut(event.Message, "\n")
+		return line + "committed " + subject
+	case gitair.Pushed:
+		return line + "pushed to " + event.Remote
+	case gitair.PullMerged:
+		return line + "pulled from " + event.Remote
+	case gitair.Diverged:
+		return line + "diverged from " + event.Remote
+	case gitair.Error:
+		if event.Remote != "" {
+			return line + event.Op + " " + event.Remote + " failed: " + event.Err
+		}
+		return line + event.Op + " failed: " + event.Err
+	}
+	return line + string(event.Type)
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// trayScript shows a menu bar item through the Cocoa bridge of JavaScript
+// for Automation. Like the Windows tray it polls the status file git-air
+// writes and prints the menu choices to stdout.
+const trayScript = `
+ObjC.import('Cocoa');
+const app = $.NSApplication.sharedApplication;
+app.setActivationPolicy($.NSApplicationActivationPolicyAccessory);
+const out = $.NSFileHandle.fileHandleWithStandardOutput;
+function send(choice) { out.writeData($(choice + '\n').dataUsingEncoding($.NSUTF8StringEncoding)); }
+
+const dots = { green: '🟢', yellow: '🟡', red: '🔴' };
+const statusFile = $.NSProcessInfo.processInfo.environment.objectForKey('GIT_AIR_TRAY_STATUS').js;
+let item, toggle;
+function update() {
+	const text = $.NSString.stringWithContentsOfFileEncodingError(statusFile, $.NSUTF8StringEncoding, null);
+	if (text.isNil()) return;
+	const status = text.js.split('\n');
+	if (status.length < 3) return;
+	item.button.title = dots[status[0]] || dots.green;
+	item.button.toolTip = status[1];
+	toggle.title = status[2] === 'true' ? 'Resume' : 'Pause';
+}
+
+ObjC.registerSubclass({
+	name: 'GitAirTray',
+	methods: {
+		'toggle:': { types: ['void', ['id']], implementation: function (sender) { send('toggle'); } },
+		'sync:': { types: ['void', ['id']], implementation: function (sender) { send('sync'); } },
+		'events:': { types: ['void', ['id']], implementation: function (sender) { send('events'); } },
+		'quit:': { types: ['void', ['id']], implementation: function (sender) { send('quit'); app.terminate(null); } },
+		'tick:': { types: ['void', ['id']], implementation: function (timer) { update(); } },
+	},
+});
+const target = $.GitAirTray.alloc.init;
+const menu = $.NSMenu.alloc.init;
+function add(title, action) {
+	const entry = $.NSMenuItem.alloc.initWithTitleActionKeyEquivalent(title, action, '');
+	entry.target = target;
+	menu.addItem(entry);
+	return entry;
+}
+toggle = add('Pause', 'toggle:');
+add('Sync now', 'sync:');
+add('Recent events', 'events:');
+add('Quit', 'quit:');
+
+item = $.NSStatusBar.systemStatusBar.statusItemWithLength($.NSVariableStatusItemLength);
+item.button.title = dots.green;
+item.button.toolTip = 'Git Air';
+item.menu = menu;
+$.NSTimer.scheduledTimerWithTimeIntervalTargetSelectorUserInfoRepeats(1, target, 'tick:', null, true);
+app.run;
+`
+
+// openTray runs osascript with the tray script
+func openTray() (*trayIcon, error) {
+	status := filepath.Join(stateDir(), "tray-status.txt")
+	if err := os.MkdirAll(filepath.Dir(status), 0755); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("osascript", "-l", "JavaScript", "-e", trayScript)
+	cmd.Env = append(os.Environ(), "GIT_AIR_TRAY_STATUS="+status)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting osascript: %v", err)
+	}
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+		cmd.Wait()
+		close(commands)
+	}()
+
+	return &trayIcon{
+		set: func(state, tooltip string, paused bool) {
+			content := fmt.Sprintf("%s\n%s\n%t\n", state, strings.ReplaceAll(tooltip, "\n", " "), paused)
+			tmp := status + ".tmp"
+			if os.WriteFile(tmp, []byte(content), 0644) == nil {
+				os.Rename(tmp, status)
+			}
+		},
+		commands: commands,
+		stop: func() {
+			cmd.Process.Kill()
+			os.Remove(status)
+		},
+	}, nil
+}
+
+// openFile shows a file in its default application
+func openFile(path string) error {
+	return exec.Command("open", path).Start()
+}
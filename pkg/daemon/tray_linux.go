@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// trayIcons are freedesktop icon names for the three states
+var trayIcons = map[string]string{
+	"green":  "emblem-default",
+	"yellow": "dialog-warning",
+	"red":    "dialog-error",
+}
+
+// openTray runs yad as a notification-area icon. yad runs the menu
+// commands itself; they echo the choice to its stdout, which is read here.
+func openTray() (*trayIcon, error) {
+	if _, err := exec.LookPath("yad"); err != nil {
+		return nil, fmt.Errorf("the tray icon needs yad (e.g. apt install yad)")
+	}
+	cmd := exec.Command("yad", "--notification", "--listen",
+		"--image="+trayIcons["green"], "--text=Git Air",
+		"--command=echo events", "--menu="+yadMenu(false))
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+		cmd.Wait()
+		close(commands)
+	}()
+
+	return &trayIcon{
+		set: func(state, tooltip string, paused bool) {
+			fmt.Fprintf(stdin, "icon:%s\ntooltip:%s\nmenu:%s\n", trayIcons[state], strings.ReplaceAll(tooltip, "\n", " "), yadMenu(paused))
+		},
+		commands: commands,
+		stop: func() {
+			stdin.Close()
+			cmd.Process.Kill()
+		},
+	}, nil
+}
+
+// yadMenu is the right-click menu in yad's Label!command|... format
+func yadMenu(paused bool) string {
+	toggle := "Pause"
+	if paused {
+		toggle = "Resume"
+	}
+	return toggle + "!echo toggle|Sync now!echo sync|Recent events!echo events|Quit!echo quit"
+}
+
+// openFile shows a file in the desktop's default application
+func openFile(path string) error {
+	return exec.Command("xdg-open", path).Start()
+}
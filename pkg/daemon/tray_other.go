@@ -0,0 +1,19 @@
+//go:build !linux && !windows && !darwin
+
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// openTray has no tray helper on this platform
+func openTray() (*trayIcon, error) {
+	return nil, fmt.Errorf("no tray icon on %s yet", runtime.GOOS)
+}
+
+// openFile shows a file in the default application
+func openFile(path string) error {
+	return exec.Command("open", path).Start()
+}
@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// trayScript shows a NotifyIcon through Windows Forms. It polls the status
+// file git-air writes and prints the menu choices to stdout.
+const trayScript = `
+Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$icons = @{
+	green  = [System.Drawing.SystemIcons]::Information
+	yellow = [System.Drawing.SystemIcons]::Warning
+	red    = [System.Drawing.SystemIcons]::Error
+}
+function Send($choice) { [Console]::Out.WriteLine($choice); [Console]::Out.Flush() }
+
+$icon = New-Object System.Windows.Forms.NotifyIcon
+$icon.Icon = $icons.green
+$icon.Text = 'Git Air'
+$menu = New-Object System.Windows.Forms.ContextMenuStrip
+$toggle = $menu.Items.Add('Pause')
+$toggle.add_Click({ Send 'toggle' })
+$menu.Items.Add('Sync now').add_Click({ Send 'sync' }) | Out-Null
+$menu.Items.Add('Recent events').add_Click({ Send 'events' }) | Out-Null
+$menu.Items.Add('Quit').add_Click({ Send 'quit'; $icon.Visible = $false; [System.Windows.Forms.Application]::Exit() }) | Out-Null
+$icon.ContextMenuStrip = $menu
+$icon.add_MouseClick({ if ($_.Button -eq [System.Windows.Forms.MouseButtons]::Left) { Send 'events' } })
+$icon.Visible = $true
+
+$timer = New-Object System.Windows.Forms.Timer
+$timer.Interval = 1000
+$timer.add_Tick({
+	$status = Get-Content -LiteralPath $env:GIT_AIR_TRAY_STATUS -ErrorAction SilentlyContinue
+	if ($status.Count -ge 3) {
+		$icon.Icon = $icons[$status[0]]
+		$icon.Text = $status[1]
+		$toggle.Text = $(if ($status[2] -eq 'true') { 'Resume' } else { 'Pause' })
+	}
+})
+$timer.Start()
+[System.Windows.Forms.Application]::Run()
+$icon.Visible = $false
+`
+
+// notifyIconText is the longest tooltip a NotifyIcon takes
+const notifyIconText = 63
+
+// openTray runs PowerShell with the tray script
+func openTray() (*trayIcon, error) {
+	status := filepath.Join(stateDir(), "tray-status.txt")
+	if err := os.MkdirAll(filepath.Dir(status), 0755); err != nil {
+		return nil, err
+	}
+
+	// -EncodedCommand takes UTF-16LE in base64
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune(trayScript)) {
+		encoded = append(encoded, byte(unit), byte(unit>>8))
+	}
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-WindowStyle", "Hidden",
+		"-EncodedCommand", base64.StdEncoding.EncodeToString(encoded))
+	cmd.Env = append(os.Environ(), "GIT_AIR_TRAY_STATUS="+status)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting PowerShell: %v", err)
+	}
+
+	commands := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			commands <- strings.TrimSpace(scanner.Text())
+		}
+		cmd.Wait()
+		close(commands)
+	}()
+
+	return &trayIcon{
+		set: func(state, tooltip string, paused bool) {
+			if runes := []rune(tooltip); len(runes) > notifyIconText {
+				tooltip = string(runes[:notifyIconText])
+			}
+			content := fmt.Sprintf("%s\r\n%s\r\n%t\r\n", state, strings.ReplaceAll(tooltip, "\n", " "), paused)
+			tmp := status + ".tmp"
+			if os.WriteFile(tmp, []byte(content), 0644) == nil {
+				os.Rename(tmp, status)
+			}
+		},
+		commands: commands,
+		stop: func() {
+			cmd.Process.Kill()
+			os.Remove(status)
+		},
+	}, nil
+}
+
+// openFile shows a file in its default application
+func openFile(path string) error {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", path).Start()
+}
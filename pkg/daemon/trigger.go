@@ -0,0 +1,360 @@
+package daemon
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TriggerConfig enables the local endpoint editor plugins call on save
+type TriggerConfig struct {
+	Listen   string `json:"listen,omitempty"`    // e.g. "127.0.0.1:8731", empty disables
+	TokenEnv string `json:"token_env,omitempty"` // env var holding the bearer token callers must send, empty allows local callers
+}
+
+// triggerRequest is what an editor sends: the saved file, either absolute
+// or relative to Repo
+type triggerRequest struct {
+	File string `json:"file"`
+	Repo string `json:"repo,omitempty"`
+}
+
+// syncRequest asks the main loop to sync a repo, named by its top-level
+// directory, and says what noticed the change: "editor", "watch" or
+// "control" (git-air trigger through the control socket)
+type syncRequest struct {
+	root    string
+	trigger string
+}
+
+// triggered carries the repos with saved or changed files from the endpoint
+// and the file watcher to the main loop, which syncs one repo at a time
+var triggered = make(chan syncRequest, 64)
+
+// triggerDue is when each saved repo is synced: its settle time after the
+// last save, so a burst of saves is one commit
+var triggerDue = map[string]time.Time{}
+
+// triggerSource is what reported each repo in triggerDue
+var triggerSource = map[string]string{}
+
+// triggerRoots caches the top-level directory of each watched repo
+var triggerRoots = map[string]string{}
+
+// startTrigger listens for save notifications on config.Trigger.Listen
+func startTrigger() {
+	if config.Trigger.Listen == "" {
+		return
+	}
+	token, ok := triggerToken()
+	if !ok {
+		fmt.Printf(tr("❌ Trigger endpoint: %s is not set, not listening\n"), config.Trigger.TokenEnv)
+		return
+	}
+	// /events shows commit messages and changed paths
+	if token == "" && !loopbackListen(config.Trigger.Listen) {
+		fmt.Printf(tr("❌ Trigger endpoint: %s is reachable from other machines, set trigger.token_env or listen on 127.0.0.1\n"), config.Trigger.Listen)
+		return
+	}
+
+	handler := &triggerHandler{token: token}
+	go func() {
+		if err := http.ListenAndServe(config.Trigger.Listen, handler); err != nil {
+			fmt.Printf(tr("❌ Trigger endpoint stopped: %v\n"), err)
+		}
+	}()
+	fmt.Printf(tr("✏️  Trigger endpoint: listening for editor saves on %s\n"), config.Trigger.Listen)
+}
+
+// triggerToken returns the configured token, false if token_env names an unset variable
+func triggerToken() (string, bool) {
+	if config.Trigger.TokenEnv == "" {
+		return "", true
+	}
+	token := os.Getenv(config.Trigger.TokenEnv)
+	return token, token != ""
+}
+
+// triggerHandler accepts POST /saved with a triggerRequest body and
+// streams the engine events at GET /events
+type triggerHandler struct {
+	token string
+}
+
+func (h *triggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.token != "" {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(h.token)) != 1 {
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+			return
+		}
+	} else if !localRequest(r) {
+		http.Error(w, "only local requests are allowed without trigger.token_env", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/saved" && r.Method == http.MethodPost:
+		h.saved(w, r)
+	case r.URL.Path == "/events" && r.Method == http.MethodGet:
+		streamEvents(w, r)
+	case r.URL.Path == "/saved" || r.URL.Path == "/events":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// loopbackListen reports whether a listen address only accepts connections
+// from this machine
+func loopbackListen(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && loopbackHost(host)
+}
+
+// loopbackHost reports whether host names this machine's loopback interface
+func loopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// localRequest reports whether a request to an endpoint without a token
+// comes from this machine and not from a web page: the Host header must
+// name the loopback interface, which DNS rebinding can't fake, and a
+// browser's Origin must be local as well
+func localRequest(r *http.Request) bool {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !loopbackHost(host) {
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && loopbackHost(u.Hostname())
+}
+
+// saved queues the repo of a saved file for the main loop
+func (h *triggerHandler) saved(w http.ResponseWriter, r *http.Request) {
+	var req triggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.File == "" {
+		http.Error(w, `expected {"file": "<path>"}`, http.StatusBadRequest)
+		return
+	}
+	file := normalizePath(req.File)
+	if !filepath.IsAbs(file) && req.Repo != "" {
+		file = filepath.Join(normalizePath(req.Repo), file)
+	}
+	root := repoRoot(filepath.Dir(file))
+	if root == "" {
+		http.Error(w, "not inside a git repository", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case triggered <- syncRequest{root: root, trigger: "editor"}:
+	default: // A cycle is running long; it checks every repo anyway
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"repo": root})
+}
+
+// streamEvents sends every engine event as a server-sent event until the
+// client goes away
+func streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	stream, cancel := events.Channel(64)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case event := <-stream:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// repoRoot returns the top-level directory of the work tree containing dir, or ""
+func repoRoot(dir string) string {
+	output, err := gitCommandIn(dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Clean(strings.TrimSpace(string(output)))
+}
+
+// sleepHandlingTriggers sleeps for duration, meanwhile syncing the watched
+// repos that editors reported saves in, or the file watcher changes in, once
+// their settle time has passed.
+// Returns true if "Sync now" in the tray ended the sleep early.
+func sleepHandlingTriggers(repos []string, duration time.Duration) bool {
+	if config.Trigger.Listen == "" && !watchMode && !controlListening {
+		select {
+		case <-syncNow:
+			return true
+		case <-time.After(duration):
+			return false
+		}
+	}
+
+	// Saves name the repo by its top-level directory, the list by its scan path
+	watched := map[string]string{}
+	for _, repo := range repos {
+		root, ok := triggerRoots[repo]
+		if !ok {
+			root = repoRoot(repo)
+			triggerRoots[repo] = root
+		}
+		if root != "" {
+			watched[root] = repo
+		}
+	}
+
+	deadline := time.Now().Add(duration)
+	for {
+		if interrupted() {
+			return true
+		}
+		for root, due := range triggerDue {
+			if time.Now().Before(due) {
+				continue
+			}
+			delete(triggerDue, root)
+			if repo, ok := watched[root]; ok {
+				if triggerSource[root] == "watch" {
+					fmt.Printf(tr("👀 %s: files changed, syncing now\n"), displayName(repo))
+				} else if triggerSource[root] == "control" {
+					fmt.Printf(tr("🔄 %s: sync requested with git-air trigger\n"), displayName(repo))
+				} else {
+					fmt.Printf(tr("✏️  %s: saved in an editor, syncing now\n"), displayName(repo))
+				}
+				syncTrigger = triggerSource[root]
+				syncRepo(repo)
+				syncTrigger = "cycle"
+			}
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return false
+		}
+		for _, due := range triggerDue {
+			if until := time.Until(due); until < wait {
+				wait = until
+			}
+		}
+
+		select {
+		case request := <-triggered:
+			if repo, ok := watched[request.root]; ok {
+				triggerDue[request.root] = time.Now().Add(settingsFor(repo).settleTime())
+				if request.trigger == "control" {
+					triggerDue[request.root] = time.Now() // Asked for explicitly, processRepo still defers files being written
+				}
+				triggerSource[request.root] = request.trigger
+			}
+		case <-syncNow:
+			return true
+		case <-time.After(wait):
+		}
+	}
+}
+
+// runTrigger implements `git-air trigger [file...]`: tell the running git-air
+// that files were saved, for editor on-save hooks, or to sync now
+func runTrigger(args []string) int {
+	fs := flag.NewFlagSet("trigger", flag.ExitOnError)
+	cfgPath := fs.String("config", "", "Path to config file")
+	fs.StringVar(cfgPath, "c", "", "Path to config file")
+	fs.Usage = func() {
+		fmt.Println(tr("USAGE:"))
+		fmt.Println(tr("  git-air trigger [file...]"))
+		fmt.Println(tr("\nTells the running git-air that the files were just saved, so their repos"))
+		fmt.Println(tr("are synced as soon as they settle instead of at the next cycle; meant for"))
+		fmt.Println(tr("editor on-save hooks. With trigger.listen in the config it goes to that"))
+		fmt.Println(tr("endpoint, otherwise to the control socket of the git-air syncing the"))
+		fmt.Println(tr("files, which syncs them right away. Without files, the git-air syncing"))
+		fmt.Println(tr("the current directory starts a cycle now."))
+	}
+	files := parseInterspersed(fs, args)
+	if !loadConfigOrReport(*cfgPath) {
+		return 1
+	}
+	if config.Trigger.Listen == "" || len(files) == 0 {
+		return triggerInstances(files)
+	}
+	token, ok := triggerToken()
+	if !ok {
+		fmt.Fprintf(os.Stderr, tr("❌ Error: %s is not set\n"), config.Trigger.TokenEnv)
+		return 1
+	}
+
+	// ":8731" listens on every interface, the local one is enough to reach it
+	host := config.Trigger.Listen
+	if strings.HasPrefix(host, ":") {
+		host = "127.0.0.1" + host
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	status := 0
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			status = 1
+			continue
+		}
+		body, _ := json.Marshal(triggerRequest{File: abs})
+		req, err := http.NewRequest(http.MethodPost, "http://"+host+"/saved", bytes.NewReader(body))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", file, err)
+			return 1
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, tr("❌ %s: git-air is not reachable: %v\n"), file, err)
+			return 1
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			fmt.Fprintf(os.Stderr, "❌ %s: %s\n", file, resp.Status)
+			status = 1
+		}
+	}
+	return status
+}
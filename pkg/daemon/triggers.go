@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DiffRule makes a file's changes non-triggering when every added and
+// removed line matches Pattern, e.g. a regenerated timestamp
+type DiffRule struct {
+	Path    string `json:"path,omitempty"` // pattern as in transient_patterns, empty matches every file
+	Pattern string `json:"pattern"`        // regular expression
+}
+
+// validate checks the path pattern and regex
+func (r DiffRule) validate() error {
+	if _, err := filepath.Match(r.Path, ""); err != nil {
+		return fmt.Errorf("invalid ignore_diffs path %q", r.Path)
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil || r.Pattern == "" {
+		return fmt.Errorf("invalid ignore_diffs pattern %q", r.Pattern)
+	}
+	return nil
+}
+
+// triggeringChanges drops the changes that should not cause a commit on their
+// own. They are still committed along with any other change.
+func triggeringChanges(changes []fileChange, settings RepoSettings) []fileChange {
+	ignoreWhitespace := settings.IgnoreWhitespace != nil && *settings.IgnoreWhitespace
+	ignoreGenerated := settings.IgnoreGenerated != nil && *settings.IgnoreGenerated
+	if !ignoreWhitespace && !ignoreGenerated && len(settings.IgnoreDiffs) == 0 {
+		return changes
+	}
+
+	generated := map[string]bool{}
+	if ignoreGenerated {
+		generated = generatedFiles(changePaths(changes), settings.Generated)
+	}
+
+	var result []fileChange
+	for _, change := range changes {
+		if generated[change.Path] {
+			continue
+		}
+		if isModified(change) {
+			if ignoreWhitespace && whitespaceOnly(change.Path) {
+				continue
+			}
+			if patterns := diffPatterns(settings.IgnoreDiffs, change.Path); len(patterns) > 0 && onlyMatchingLines(change.Path, patterns) {
+				continue
+			}
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+// diffPatterns returns the compiled patterns of the rules that apply to a path
+func diffPatterns(rules []DiffRule, path string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, rule := range rules {
+		if rule.Path != "" && !matchesAny([]string{rule.Path}, path) {
+			continue
+		}
+		if re, err := regexp.Compile(rule.Pattern); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
+// onlyMatchingLines reports whether every line added or removed in a file
+// since HEAD matches one of the patterns
+func onlyMatchingLines(path string, patterns []*regexp.Regexp) bool {
+	output, err := gitCommand("diff", "HEAD", "-U0", "--no-color", "--no-ext-diff", "--", path).Output()
+	if err != nil {
+		return false
+	}
+
+	changed := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- ") {
+			continue
+		}
+		if !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") {
+			continue
+		}
+		changed++
+		matched := false
+		for _, re := range patterns {
+			if re.MatchString(line[1:]) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return changed > 0 // Binary changes have no lines and always count
+}
+
+// isModified reports whether a tracked file has content changes only, as
+// opposed to being added, deleted, renamed or untracked
+func isModified(change fileChange) bool {
+	for _, c := range change.Status {
+		if c != ' ' && c != 'M' {
+			return false
+		}
+	}
+	return true
+}
+
+// lineEndingOnly returns the modified files that differ from HEAD only in
+// CRLF vs LF line endings. git diff --numstat leaves them out when told to
+// ignore the CR, while binary and real changes are listed.
+func lineEndingOnly(changes []fileChange) []string {
+	var modified []string
+	for _, change := range changes {
+		if isModified(change) {
+			modified = append(modified, change.Path)
+		}
+	}
+	if len(modified) == 0 {
+		return nil
+	}
+
+	// The whole tree, as a long path list could exceed the command line limit
+	output, err := gitCommand("diff", "HEAD", "--numstat", "-z", "--ignore-cr-at-eol", "--no-ext-diff").Output()
+	if err != nil {
+		return nil
+	}
+	changed := map[string]bool{}
+	for _, entry := range nulList(output) {
+		// added TAB deleted TAB path
+		if fields := strings.SplitN(entry, "\t", 3); len(fields) == 3 {
+			changed[fields[2]] = true
+		}
+	}
+	var result []string
+	for _, path := range modified {
+		if !changed[path] {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// whitespaceOnly reports whether a file differs from HEAD only in whitespace
+// and line endings
+func whitespaceOnly(path string) bool {
+	cmd := gitCommand("diff", "HEAD", "--quiet", "-w", "--ignore-cr-at-eol", "--", path)
+	return cmd.Run() == nil
+}
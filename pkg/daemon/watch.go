@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// watchMode syncs repos as soon as their files change, from the file
+// watcher, instead of waiting for the next cycle (--watch or watch in the config)
+var watchMode bool
+
+// watchFallbackInterval is how often the cycle still checks a watched repo,
+// for changes the watcher can't see, such as those made from another
+// machine on a network file system
+const watchFallbackInterval = 5 * time.Minute
+
+// watchedRepos maps each watched repo to when a cycle last checked it
+var watchedRepos = map[string]time.Time{}
+
+// watcherStarted is set once the platform watcher is running
+var watcherStarted bool
+
+// watcherStopped is set when the watcher fails, so every cycle checks every
+// repo again
+var watcherStopped atomic.Bool
+
+// watchRepos starts watching the work trees of the repos not watched yet.
+// Repos that can't be watched are checked every cycle as before.
+func watchRepos(repos []string) {
+	if !watchMode {
+		return
+	}
+	if !watcherStarted {
+		if err := startWatcher(); err != nil {
+			fmt.Printf(tr("⚠️  Watch mode: %v, checking every interval instead\n"), err)
+			watchMode = false
+			return
+		}
+		watcherStarted = true
+	}
+
+	for _, repo := range repos {
+		if _, ok := watchedRepos[repo]; ok {
+			continue
+		}
+		root := repoRoot(repo)
+		if root == "" {
+			continue
+		}
+		dirs, err := watchTree(root)
+		if err != nil {
+			fmt.Printf(tr("  ⚠️  %s: not watched, checking every interval: %v\n"), displayName(repo), err)
+			continue
+		}
+		watchedRepos[repo] = time.Time{}
+		fmt.Printf(tr("  👀 %s: watching %d directories\n"), displayName(repo), dirs)
+	}
+}
+
+// watchFallbackDue reports whether a cycle should check a repo: always for
+// repos without a watcher, else once every watchFallbackInterval
+func watchFallbackDue(repoPath string) bool {
+	last, watched := watchedRepos[repoPath]
+	if !watched || watcherStopped.Load() {
+		return true
+	}
+	if time.Since(last) < watchFallbackInterval {
+		return false
+	}
+	watchedRepos[repoPath] = time.Now()
+	return true
+}
+
+// fileChanged hands a repo with changed files to the main loop, which syncs
+// it once its settle time has passed without further changes
+func fileChanged(root string) {
+	select {
+	case triggered <- syncRequest{root: root, trigger: "watch"}:
+	default: // A cycle is running long; the main loop catches up
+	}
+}
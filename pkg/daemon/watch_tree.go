@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var (
+	watcher   *fsnotify.Watcher
+	watchMu   sync.Mutex
+	watchDirs = map[string]string{} // Watched directory -> top-level directory of its repo
+)
+
+// startWatcher opens the file watcher all repos share (inotify on Linux,
+// kqueue on macOS and the BSDs, ReadDirectoryChangesW on Windows) and reads
+// its events in the background
+func startWatcher() error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("file watcher unavailable: %v", err)
+	}
+	watcher = w
+	go readWatchEvents()
+	return nil
+}
+
+// watchTree watches every directory of a work tree, except .git, ignored
+// directories and nested repos, which are watched on their own. Returns the
+// number of directories watched.
+func watchTree(root string) (int, error) {
+	count, err := addWatches(root, root, ignoredDirs(root))
+	if errors.Is(err, syscall.ENOSPC) {
+		return count, errors.New("the inotify watch limit is reached, raise fs.inotify.max_user_watches")
+	}
+	return count, err
+}
+
+// addWatches watches top and the directories below it
+func addWatches(root, top string, ignored map[string]bool) (int, error) {
+	count := 0
+	err := filepath.WalkDir(top, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == top {
+				return err
+			}
+			return nil // Unreadable directories are skipped
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != top {
+			if entry.Name() == ".git" || ignored[path] {
+				return filepath.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(path, ".git")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		if err := watcher.Add(path); err != nil {
+			return err
+		}
+		watchMu.Lock()
+		watchDirs[path] = root
+		watchMu.Unlock()
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// ignoredDirs lists the directories git ignores as a whole, such as
+// node_modules, whose churn never shows up in git status
+func ignoredDirs(root string) map[string]bool {
+	ignored := map[string]bool{}
+	output, err := gitCommandIn(root, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory").Output()
+	if err != nil {
+		return ignored
+	}
+	for _, entry := range nulList(output) {
+		if dir, ok := strings.CutSuffix(entry, "/"); ok {
+			ignored[filepath.Join(root, dir)] = true
+		}
+	}
+	return ignored
+}
+
+// readWatchEvents turns file events into syncs until the watcher closes
+func readWatchEvents() {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				fmt.Printf(tr("❌ Watch mode stopped: %v, checking every interval instead\n"), tr("the watcher closed"))
+				watcherStopped.Store(true)
+				return
+			}
+			handleWatchEvent(event)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				continue // Events reports the close
+			}
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				fmt.Printf(tr("  ⚠️  Watch mode: %v\n"), err)
+			}
+			// Events may have been lost, so any repo may have changed
+			roots := map[string]bool{}
+			watchMu.Lock()
+			for _, root := range watchDirs {
+				roots[root] = true
+			}
+			watchMu.Unlock()
+			for root := range roots {
+				fileChanged(root)
+			}
+		}
+	}
+}
+
+// handleWatchEvent reports the repo an event happened in, and watches
+// directories created or moved into it
+func handleWatchEvent(event fsnotify.Event) {
+	watchMu.Lock()
+	root, ok := watchDirs[filepath.Dir(event.Name)]
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		if _, watched := watchDirs[event.Name]; watched {
+			delete(watchDirs, event.Name) // Gone; the parent reports the change
+			watcher.Remove(event.Name)
+		}
+	}
+	watchMu.Unlock()
+	if !ok || filepath.Base(event.Name) == ".git" {
+		return
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Lstat(event.Name); err == nil && info.IsDir() {
+			if gitCommandIn(root, "check-ignore", "-q", "--", event.Name).Run() != nil {
+				if _, err := addWatches(root, event.Name, nil); err != nil {
+					fmt.Printf(tr("  ⚠️  Watch mode: %s not watched: %v\n"), event.Name, err)
+				}
+			}
+		}
+	}
+	fileChanged(root)
+}
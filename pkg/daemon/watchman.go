@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// watchState is what git-air knows about a repo watched through Watchman
+type watchState struct {
+	root     string // Watchman's watch root, may be a parent of the repo
+	relative string // repo path below root
+	clock    string // clock of the last query
+	settled  bool   // the last cycle left nothing waiting (deferred files, held deletions)
+	failed   bool   // Watchman is unusable for this repo, poll instead
+}
+
+// watched holds the Watchman state per repo path
+var watched = map[string]*watchState{}
+
+// watchmanChanged asks Watchman whether anything outside .git changed since
+// the last cycle. Repos are still checked with git status when the previous
+// cycle left work waiting, on a fresh Watchman instance and on any error.
+func watchmanChanged(repoPath string) bool {
+	state := watched[repoPath]
+	if state == nil {
+		state = &watchState{}
+		watched[repoPath] = state
+		var result struct {
+			Watch        string `json:"watch"`
+			RelativePath string `json:"relative_path"`
+		}
+		if err := watchmanCommand(&result, "watch-project", repoDir); err != nil {
+			state.failed = true
+			if !watchLimitReached(repoPath, err.Error()) {
+				fmt.Printf(tr("  ⚠️  %s: Watchman unavailable, polling instead: %v\n"), displayName(repoPath), err)
+			}
+			return true
+		}
+		state.root, state.relative = result.Watch, result.RelativePath
+	}
+	if state.failed {
+		return true
+	}
+
+	query := map[string]interface{}{
+		"fields":     []string{"name"},
+		"expression": []interface{}{"not", []interface{}{"anyof", []string{"dirname", ".git"}, []string{"name", ".git"}}},
+	}
+	if state.relative != "" {
+		query["relative_root"] = state.relative
+	}
+	if state.clock != "" {
+		query["since"] = state.clock
+	}
+	var result struct {
+		Clock           string   `json:"clock"`
+		IsFreshInstance bool     `json:"is_fresh_instance"`
+		Files           []string `json:"files"`
+		Warning         string   `json:"warning"`
+	}
+	err := watchmanCommand(&result, "query", state.root, query)
+	problem := result.Warning
+	if err != nil {
+		problem = err.Error()
+	}
+	if watchLimitReached(repoPath, problem) {
+		// Watchman can't see part of the tree, its answers are incomplete
+		state.failed = true
+		return true
+	}
+	if err != nil {
+		fmt.Printf(tr("  ⚠️  %s: Watchman query failed: %v\n"), displayName(repoPath), err)
+		state.clock = ""
+		return true
+	}
+	clearAlert(repoPath, "watch-limit")
+
+	changed := state.clock == "" || result.IsFreshInstance || len(result.Files) > 0 || !state.settled
+	state.clock = result.Clock
+	state.settled = false
+	return changed
+}
+
+// watchLimitReached reports whether a Watchman error or warning says the
+// inotify watch limit ran out, and tells the user which sysctl to raise
+func watchLimitReached(repoPath, message string) bool {
+	if !strings.Contains(message, "max_user_watches") && !strings.Contains(message, "No space left on device") && !strings.Contains(message, "ENOSPC") {
+		return false
+	}
+	fmt.Printf(tr("  ⚠️  %s: inotify watch limit reached, polling instead\n"), displayName(repoPath))
+	raiseAlert(repoPath, "watch-limit", fmt.Sprintf(tr("%s: inotify watch limit reached, changes are polled - raise it with sudo sysctl fs.inotify.max_user_watches=524288 (add fs.inotify.max_user_watches=524288 to /etc/sysctl.d/99-inotify.conf to keep it)"), displayName(repoPath)))
+	return true
+}
+
+// watchmanSettled records that a cycle left nothing waiting for the repo, so
+// the next one can be skipped when Watchman reports no changes
+func watchmanSettled(repoPath string) {
+	if state := watched[repoPath]; state != nil {
+		state.settled = true
+	}
+}
+
+// watchmanCommand runs one Watchman command through its JSON CLI interface
+func watchmanCommand(result interface{}, args ...interface{}) error {
+	request, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("watchman", "-j", "--no-pretty")
+	cmd.Stdin = bytes.NewReader(request)
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	var reply struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(output, &reply); err != nil {
+		return err
+	}
+	if reply.Error != "" {
+		return fmt.Errorf("%s", reply.Error)
+	}
+	return json.Unmarshal(output, result)
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"git-air/pkg/gitair"
+)
+
+// workers is how many repos a cycle checks and fetches at the same time
+// (--workers, or workers in the config). processRepo and the merges of a
+// pull work in repoDir, so they still go one repo at a time on the main
+// goroutine; the workers only run git commands that name their repo with -C
+// and print nothing, so the output stays in repo order.
+var workers = 1
+
+// fetchResult is the outcome of a fetch a worker ran
+type fetchResult struct {
+	output string
+	err    error
+}
+
+// prefetched holds the fetches of the pull phase by repo and remote, which
+// pullFromRemotes takes instead of fetching again
+var prefetched = map[string]fetchResult{}
+
+// runWorkers calls work for 0..n-1 on up to workers goroutines and returns
+// once every call is done
+func runWorkers(n int, work func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// scanRepos runs git status in the repos concurrently and returns, in
+// order, those processRepo has to look at: repos with changes, repos whose
+// status failed (processRepo reports why) and repos where changes only
+// appear while processing them (submodule updates, encrypted files)
+func scanRepos(repos []string) []string {
+	start := time.Now()
+	repoGits := make([]gitair.Repo, len(repos))
+	dirty := make([]bool, len(repos))
+	for i, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			dirty[i] = true
+			continue
+		}
+		if enc := settingsFor(repo).Encrypt; enc != nil && len(enc.Patterns) > 0 {
+			dirty[i] = true
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(abs, ".gitmodules")); err == nil {
+			dirty[i] = true
+			continue
+		}
+		// go-git reads the status without starting git; repos it can't open
+		// (unusual extensions, sparse index) fall back to git status
+		if repoGit, err := gitair.OpenGoGit(abs); err == nil {
+			repoGit.GitRepo = openGit(abs)
+			repoGits[i] = repoGit
+			continue
+		}
+		repoGit := openGit(abs)
+		repoGit.Options = statusSpeedups[abs] // Set by processRepo's first status
+		repoGits[i] = repoGit
+	}
+
+	runWorkers(len(repos), func(i int) {
+		if repoGits[i] == nil {
+			return
+		}
+		changes, err := repoGits[i].Status()
+		dirty[i] = err != nil || len(changes) > 0
+	})
+
+	var changed []string
+	for i, repo := range repos {
+		if dirty[i] {
+			changed = append(changed, repo)
+		}
+	}
+	fmt.Printf(tr("🔍 %d of %d repos to check (status by %d workers in %s)\n"), len(changed), len(repos), min(workers, len(repos)), benchDuration(time.Since(start)))
+	return changed
+}
+
+// prefetchRemotes fetches every remote of the repos concurrently, through
+// the network slots, before the pull phase merges one repo at a time
+func prefetchRemotes(repos []string) {
+	start := time.Now()
+	type fetchJob struct {
+		repo   string
+		git    *gitair.GitRepo
+		remote string
+	}
+	var jobs []fetchJob
+	for _, repo := range repos {
+		abs, err := filepath.Abs(repo)
+		if err != nil {
+			continue
+		}
+		repoGit := openGit(abs)
+		repoGit.Options = proxyOptionsFor(repo)
+		for _, remote := range repoGit.Remotes() {
+			jobs = append(jobs, fetchJob{repo: repo, git: repoGit, remote: remote})
+		}
+	}
+
+	results := make([]fetchResult, len(jobs))
+	runWorkers(len(jobs), func(i int) {
+		netSlots <- struct{}{}
+		defer func() { <-netSlots }()
+		results[i].output, results[i].err = jobs[i].git.Git("fetch", jobs[i].remote)
+	})
+
+	failed := 0
+	for i, job := range jobs {
+		prefetched[job.repo+"\x00"+job.remote] = results[i]
+		if results[i].err != nil {
+			failed++
+		}
+	}
+	fmt.Printf(tr("  📡 Fetched %d remote(s) of %d repos with %d workers in %s (%d failed)\n"), len(jobs), len(repos), min(workers, max(len(jobs), 1)), benchDuration(time.Since(start)), failed)
+}
+
+// fetchRemote fetches a remote of the current repo, or returns the fetch a
+// worker already ran for it this pull phase
+func fetchRemote(repoPath, remote string) (string, error) {
+	key := repoPath + "\x00" + remote
+	if result, ok := prefetched[key]; ok {
+		delete(prefetched, key)
+		return result.output, result.err
+	}
+	return gitNetwork("fetch", remote)
+}
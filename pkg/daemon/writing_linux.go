@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// openForWrite returns the paths that some process currently holds open for
+// writing, found by scanning /proc/<pid>/fd. Processes we cannot inspect are skipped.
+func openForWrite(paths []string) []string {
+	wanted := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if abs, err := filepath.Abs(inRepo(path)); err == nil {
+			wanted[abs] = path
+		}
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	var busy []string
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || wanted[target] == "" {
+				continue
+			}
+			if fdWritable(filepath.Join("/proc", proc.Name(), "fdinfo", fd.Name())) {
+				busy = append(busy, wanted[target])
+				delete(wanted, target)
+			}
+		}
+	}
+	return busy
+}
+
+// fdWritable reads the open flags from an fdinfo file and checks for write access
+func fdWritable(fdinfo string) bool {
+	data, err := os.ReadFile(fdinfo)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "flags:"); ok {
+			flags, err := strconv.ParseUint(strings.TrimSpace(value), 8, 64)
+			return err == nil && flags&uint64(os.O_WRONLY|os.O_RDWR) != 0
+		}
+	}
+	return false
+}
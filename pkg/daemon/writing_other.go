@@ -0,0 +1,9 @@
+//go:build !linux
+
+package daemon
+
+// openForWrite cannot detect open files on this platform, only the
+// modification time check applies
+func openForWrite(paths []string) []string {
+	return nil
+}
@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var (
+	// mountedDrive matches a Windows drive mounted into WSL, /mnt/c/...
+	mountedDrive = regexp.MustCompile(`^/mnt/([a-zA-Z])(/.*)?$`)
+
+	// driveLetterPath matches a Windows path such as C:\Users\me or C:/Users/me
+	driveLetterPath = regexp.MustCompile(`^([a-zA-Z]):([\\/].*)?$`)
+
+	// wslShare matches the Windows view of a WSL distribution's files,
+	// \\wsl$\Ubuntu\home\me or \\wsl.localhost\Ubuntu\home\me
+	wslShare = regexp.MustCompile(`(?i)^[\\/]{2}(wsl\$|wsl\.localhost)[\\/]([^\\/]+)(.*)$`)
+)
+
+// crossingWarned remembers the repos warned about, so the warning is shown once
+var crossingWarned = map[string]bool{}
+
+// inWSL is set when git-air runs inside the Windows Subsystem for Linux
+var inWSL = detectWSL()
+
+// detectWSL reports whether this is a Linux kernel of WSL 1 or 2
+func detectWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	version, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(version)), "microsoft")
+}
+
+// otherSidePath returns how the other side of the WSL boundary sees a repo:
+// the Windows path of a repo on a drive mounted into WSL (/mnt/c/... is
+// C:\...), or the WSL path of a repo reached through \\wsl$ from Windows.
+// It returns "" for repos on git-air's own side.
+func otherSidePath(repoPath string) string {
+	abs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "" // Not a path, e.g. an alert key
+	}
+	if match := wslShare.FindStringSubmatch(abs); match != nil {
+		return match[2] + ":" + filepath.ToSlash(match[3])
+	}
+	if !inWSL {
+		return ""
+	}
+	if match := mountedDrive.FindStringSubmatch(abs); match != nil {
+		rest := match[2]
+		if rest == "" {
+			rest = "/"
+		}
+		return strings.ToUpper(match[1]) + ":" + strings.ReplaceAll(rest, "/", `\`)
+	}
+	return ""
+}
+
+// normalizePath turns a Windows path given to git-air in WSL, e.g. in the
+// config or on the command line, into its /mnt/<drive> form. Other paths are
+// returned unchanged.
+func normalizePath(path string) string {
+	if !inWSL {
+		return path
+	}
+	if match := driveLetterPath.FindStringSubmatch(path); match != nil {
+		return "/mnt/" + strings.ToLower(match[1]) + strings.ReplaceAll(match[2], `\`, "/")
+	}
+	return path
+}
+
+// warnCrossingWSL warns once per repo about repos on the far side of the WSL
+// boundary: every file access goes through a network file system (9P), so
+// status is slow, file change notification doesn't work and file locks
+// aren't seen by programs on the other side
+func warnCrossingWSL(repos []string) {
+	for _, repo := range repos {
+		other := otherSidePath(repo)
+		if other == "" || crossingWarned[repo] {
+			continue
+		}
+		crossingWarned[repo] = true
+		side := "Windows"
+		if runtime.GOOS == "windows" {
+			side = "WSL"
+		}
+		fmt.Printf(tr("  ⚠️  %s (%s) is in the %s file system: git is slow across the WSL boundary and file locks aren't shared.\n"), repo, other, side)
+		fmt.Printf(tr("      Move it to this side or run git-air on the %s side for it\n"), side)
+	}
+}
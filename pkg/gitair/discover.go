@@ -0,0 +1,53 @@
+package gitair
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SkipDirs are the directory names discovery never descends into:
+// dependency trees full of vendored repos
+var SkipDirs = []string{"node_modules", "vendor"}
+
+// WalkDiscoverer finds repositories by walking the tree below root and
+// looking for .git directories. Repos nested in other repos are found too.
+type WalkDiscoverer struct{}
+
+// Discover returns the directories below root that hold a .git directory
+func (WalkDiscoverer) Discover(root string) ([]string, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	var scan func(dir string)
+	scan = func(dir string) {
+		items, err := os.ReadDir(dir)
+		if err != nil {
+			return // Skip errors
+		}
+		for _, item := range items {
+			if !item.IsDir() {
+				continue
+			}
+			switch name := item.Name(); {
+			case name == ".git":
+				repos = append(repos, dir)
+			case !Skipped(name):
+				scan(filepath.Join(dir, name))
+			}
+		}
+	}
+	scan(root)
+	return repos, nil
+}
+
+// Skipped reports whether discovery leaves a directory of this name out
+func Skipped(name string) bool {
+	for _, skip := range SkipDirs {
+		if name == skip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package gitair
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType says what happened to a repo
+type EventType string
+
+const (
+	RepoDirty  EventType = "repo_dirty"  // changes were found, Changes lists them
+	Committed  EventType = "committed"   // a commit was made, with Message and its size
+	Pushed     EventType = "pushed"      // Remote accepted a push
+	PullMerged EventType = "pull_merged" // changes from Remote were merged
+	UpToDate   EventType = "up_to_date"  // Remote had nothing to pull
+	Diverged   EventType = "diverged"    // both sides committed, the pull from Remote is settled or rescued next
+	Error      EventType = "error"       // Op ("commit", "push" or "pull") failed with Err
+)
+
+// Event is one thing the engine did, for the console, the state store, the
+// /events stream and anyone embedding git-air
+type Event struct {
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	Repo    string    `json:"repo"`
+	Remote  string    `json:"remote,omitempty"`
+	Op      string    `json:"op,omitempty"`
+	Err     string    `json:"error,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Changes []Change  `json:"changes,omitempty"`
+	Files   int       `json:"files,omitempty"`
+	Added   int       `json:"added,omitempty"`
+	Deleted int       `json:"deleted,omitempty"`
+}
+
+// Bus delivers events to its subscribers. The zero value is ready to use
+// and a nil *Bus drops everything, so publishing needs no checks.
+type Bus struct {
+	mu   sync.Mutex
+	next int
+	subs []subscription
+}
+
+type subscription struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe calls fn for every event published from now on, in the
+// publisher's goroutine and in subscription order. The returned function
+// unsubscribes.
+func (b *Bus) Subscribe(fn func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.next
+	b.next++
+	b.subs = append(b.subs, subscription{id, fn})
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subs {
+			if sub.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Channel subscribes a channel holding up to size events. Events that
+// don't fit are dropped rather than holding up the engine. The returned
+// function unsubscribes; the channel is not closed.
+func (b *Bus) Channel(size int) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+	cancel := b.Subscribe(func(event Event) {
+		select {
+		case ch <- event:
+		default:
+		}
+	})
+	return ch, cancel
+}
+
+// Publish hands an event to every subscriber, stamping the time if unset
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.Lock()
+	subs := b.subs
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.fn(event)
+	}
+}
@@ -0,0 +1,74 @@
+// Package gitair is the embeddable core of git-air: finding repositories,
+// reading and committing their changes and pushing them to every remote.
+//
+// The git-air daemon is a Syncer too, one that layers its policies (config
+// rules, alerts, deferred pushes, review modes) on top and that its loops
+// run every repo through; a program that only wants the auto-sync behavior
+// combines a Discoverer, Repos from Open and an AutoSyncer:
+//
+//	paths, _ := gitair.WalkDiscoverer{}.Discover(root)
+//	syncer := gitair.AutoSyncer{Messages: gitair.TimestampMessages{}, Push: true}
+//	for _, path := range paths {
+//		result, err := syncer.Sync(gitair.Open(path))
+//		...
+//	}
+package gitair
+
+// Change is one entry of git status: Status uses the two-letter porcelain v1
+// codes ("??" for untracked, " M" for modified in the worktree)
+type Change struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+}
+
+// Discoverer finds the repositories below a root directory
+type Discoverer interface {
+	Discover(root string) ([]string, error)
+}
+
+// Repo is a git working tree the engine can sync
+type Repo interface {
+	// Path is the directory of the working tree
+	Path() string
+
+	// Status lists the uncommitted changes
+	Status() ([]Change, error)
+
+	// Stage stages every change except the paths in exclude
+	Stage(exclude ...string) error
+
+	// Commit commits the staged changes; args are extra git commit options
+	Commit(message string, args ...string) error
+
+	// Branch is the checked-out branch, "" when HEAD is detached
+	Branch() string
+
+	// Remotes lists the names of the configured remotes
+	Remotes() []string
+
+	// Push and Pull sync branch with a remote and return git's output
+	Push(remote, branch string) (string, error)
+	Pull(remote, branch string) (string, error)
+}
+
+// CommitMessageGenerator writes the message for an auto-commit of changes
+type CommitMessageGenerator interface {
+	Message(repo Repo, changes []Change) (string, error)
+}
+
+// Syncer brings a repo in step with its remotes
+type Syncer interface {
+	Sync(repo Repo) (Result, error)
+}
+
+// Result is what one Sync did
+type Result struct {
+	// Committed is set when changes were committed, with the Message used
+	Committed bool
+	Message   string
+
+	// Pushed lists the remotes pushed to, Failed the ones that refused, with
+	// git's output
+	Pushed []string
+	Failed map[string]string
+}
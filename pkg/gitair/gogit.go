@@ -0,0 +1,334 @@
+package gitair
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// GoGitRepo is a Repo run in-process with go-git: status, staging, commits,
+// fetches and pushes start no git process and never touch the working
+// directory. What go-git can't do the way git does falls back to the git
+// command line of the embedded GitRepo: commits with extra options, hooks
+// or signing, merges of diverged branches, and network operations it can't
+// authenticate or that need Options (proxies and the like).
+type GoGitRepo struct {
+	*GitRepo
+	repo *git.Repository
+}
+
+// OpenGoGit returns the repo whose working tree is at path, or an error if
+// go-git can't read it or would read it differently from git: submodules,
+// attributes (line endings, LFS and other filters), core.autocrlf,
+// core.fileMode off and sparse checkouts
+func OpenGoGit(path string) (*GoGitRepo, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	r := &GoGitRepo{GitRepo: Open(path), repo: repo}
+	if reason := r.unlikeGit(); reason != "" {
+		return nil, fmt.Errorf("%s: %s needs git", path, reason)
+	}
+	return r, nil
+}
+
+// unlikeGit names what go-git would see differently from git, "" if nothing
+func (r *GoGitRepo) unlikeGit() string {
+	for _, file := range []string{".gitmodules", ".gitattributes"} {
+		if _, err := os.Lstat(filepath.Join(r.path, file)); err == nil {
+			return file
+		}
+	}
+	if storage, ok := r.repo.Storer.(*filesystem.Storage); ok {
+		if _, err := storage.Filesystem().Stat("info/attributes"); err == nil {
+			return "info/attributes"
+		}
+	}
+	if autocrlf := r.option("core", "autocrlf"); autocrlf != "" && !isFalse(autocrlf) {
+		return "core.autocrlf"
+	}
+	if isFalse(r.option("core", "fileMode")) {
+		return "core.fileMode"
+	}
+	if isTrue(r.option("core", "sparseCheckout")) {
+		return "core.sparseCheckout"
+	}
+	return ""
+}
+
+// option returns a setting of the repo's config, or else of the global and
+// system ones, "" if none sets it
+func (r *GoGitRepo) option(section, key string) string {
+	if cfg, err := r.repo.Config(); err == nil && cfg.Raw.Section(section).HasOption(key) {
+		return cfg.Raw.Section(section).Option(key)
+	}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if cfg, err := config.LoadConfig(scope); err == nil && cfg.Raw.Section(section).HasOption(key) {
+			return cfg.Raw.Section(section).Option(key)
+		}
+	}
+	return ""
+}
+
+// isTrue and isFalse read a git boolean the way git does
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+func isFalse(value string) bool {
+	switch strings.ToLower(value) {
+	case "false", "no", "off", "0":
+		return true
+	}
+	return false
+}
+
+// expandPath resolves a path from the git config: ~/ is the home directory,
+// relative paths start at the working tree
+func (r *GoGitRepo) expandPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return filepath.Join(r.path, path)
+	}
+	return path
+}
+
+// globalExcludes reads the user's excludes file (core.excludesFile, by
+// default $XDG_CONFIG_HOME/git/ignore), which go-git's status leaves out
+func (r *GoGitRepo) globalExcludes() []gitignore.Pattern {
+	file := r.option("core", "excludesFile")
+	if file != "" {
+		file = r.expandPath(file)
+	} else if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		file = filepath.Join(dir, "git", "ignore")
+	} else if home, err := os.UserHomeDir(); err == nil {
+		file = filepath.Join(home, ".config", "git", "ignore")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns
+}
+
+// Status lists the uncommitted changes, untracked files included, sorted
+// by path
+func (r *GoGitRepo) Status() ([]Change, error) {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	worktree.Excludes = r.globalExcludes()
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for path, file := range status {
+		if file.Staging == git.Unmodified && file.Worktree == git.Unmodified {
+			continue
+		}
+		changes = append(changes, Change{Status: string([]byte{byte(file.Staging), byte(file.Worktree)}), Path: path})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// Stage stages every change except the paths in exclude and below them
+func (r *GoGitRepo) Stage(exclude ...string) error {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if len(exclude) == 0 {
+		return worktree.AddWithOptions(&git.AddOptions{All: true})
+	}
+	changes, err := r.Status()
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if excluded(change.Path, exclude) {
+			continue
+		}
+		if _, err := worktree.Add(change.Path); err != nil {
+			return fmt.Errorf("%s: %v", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// excluded reports whether path is one of the paths or below one of them
+func excluded(path string, paths []string) bool {
+	for _, exclude := range paths {
+		exclude = strings.TrimSuffix(exclude, "/")
+		if path == exclude || strings.HasPrefix(path, exclude+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit commits the staged changes as the configured user, with the
+// message cleaned up like git commit -m does. Extra git commit options,
+// commit hooks, signing and identities from the environment need git
+// itself, as do commits go-git fails at.
+func (r *GoGitRepo) Commit(message string, args ...string) error {
+	if len(args) > 0 || r.commitNeedsGit() {
+		return r.GitRepo.Commit(message, args...)
+	}
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return r.GitRepo.Commit(message)
+	}
+	if _, err := worktree.Commit(cleanMessage(message), &git.CommitOptions{}); err != nil {
+		return r.GitRepo.Commit(message)
+	}
+	return nil
+}
+
+// commitHooks are the hooks git commit runs
+var commitHooks = []string{"pre-commit", "prepare-commit-msg", "commit-msg", "post-commit"}
+
+// commitNeedsGit reports whether git has to make the commit: go-git runs
+// no hooks, signs nothing and ignores GIT_AUTHOR_* and GIT_COMMITTER_*
+func (r *GoGitRepo) commitNeedsGit() bool {
+	if isTrue(r.option("commit", "gpgSign")) {
+		return true
+	}
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_AUTHOR_DATE", "GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL", "GIT_COMMITTER_DATE"} {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+
+	hooks := r.option("core", "hooksPath")
+	if hooks != "" {
+		hooks = r.expandPath(hooks)
+	} else {
+		// A linked worktree keeps its hooks in the main repo
+		storage, ok := r.repo.Storer.(*filesystem.Storage)
+		if info, err := os.Lstat(filepath.Join(r.path, ".git")); !ok || err != nil || !info.IsDir() {
+			return true
+		}
+		hooks = filepath.Join(storage.Filesystem().Root(), "hooks")
+	}
+	for _, hook := range commitHooks {
+		if _, err := os.Stat(filepath.Join(hooks, hook)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// blankLines matches the blank lines git commit folds into one
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// cleanMessage does what git commit -m does to a message: strips trailing
+// whitespace from every line, drops leading and trailing blank lines, folds
+// runs of blank lines and ends it with a newline
+func cleanMessage(message string) string {
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	message = strings.Trim(strings.Join(lines, "\n"), "\n")
+	return blankLines.ReplaceAllString(message, "\n\n") + "\n"
+}
+
+// Branch is the checked-out branch, "" when HEAD is detached
+func (r *GoGitRepo) Branch() string {
+	// Not Head(), which fails on a branch without commits yet
+	head, err := r.repo.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return ""
+	}
+	return head.Target().Short()
+}
+
+// Remotes lists the names of the configured remotes, sorted
+func (r *GoGitRepo) Remotes() []string {
+	remotes, err := r.repo.Remotes()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, remote := range remotes {
+		names = append(names, remote.Config().Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fetch fetches the branches of a remote
+func (r *GoGitRepo) Fetch(remote string) (string, error) {
+	if len(r.Options) > 0 {
+		return r.Git("fetch", remote)
+	}
+	err := r.repo.Fetch(&git.FetchOptions{RemoteName: remote})
+	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", nil
+	}
+	return r.Git("fetch", remote)
+}
+
+// Push pushes branch to remote. A rejected push is returned as is; other
+// failures, like credentials only git's helpers know, are retried with git.
+func (r *GoGitRepo) Push(remote, branch string) (string, error) {
+	if len(r.Options) > 0 {
+		return r.GitRepo.Push(remote, branch)
+	}
+	ref := config.RefSpec(plumbing.NewBranchReferenceName(branch) + ":" + plumbing.NewBranchReferenceName(branch))
+	err := r.repo.Push(&git.PushOptions{RemoteName: remote, RefSpecs: []config.RefSpec{ref}})
+	switch {
+	case err == nil, errors.Is(err, git.NoErrAlreadyUpToDate):
+		return "", nil
+	case errors.Is(err, git.ErrNonFastForwardUpdate), strings.Contains(err.Error(), "non-fast-forward"):
+		return err.Error(), err
+	}
+	return r.GitRepo.Push(remote, branch)
+}
+
+// Pull merges branch from remote into the checked-out branch. go-git only
+// fast-forwards, so diverged branches are merged by git.
+func (r *GoGitRepo) Pull(remote, branch string) (string, error) {
+	if len(r.Options) > 0 {
+		return r.GitRepo.Pull(remote, branch)
+	}
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	err = worktree.Pull(&git.PullOptions{RemoteName: remote, ReferenceName: plumbing.NewBranchReferenceName(branch), SingleBranch: true})
+	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return "", nil
+	}
+	return r.GitRepo.Pull(remote, branch)
+}
@@ -0,0 +1,34 @@
+package gitair
+
+import (
+	"strings"
+	"time"
+)
+
+// AutoCommitPrefix starts every auto-commit message git-air writes itself
+const AutoCommitPrefix = "auto commit"
+
+// TimestampMessage is the default auto-commit message, "auto commit - <time>"
+func TimestampMessage(monorepo bool) string {
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	if monorepo {
+		return AutoCommitPrefix + " (monorepo) - " + timestamp
+	}
+	return AutoCommitPrefix + " - " + timestamp
+}
+
+// TimestampMessages is the CommitMessageGenerator of the daemon without an
+// AI provider
+type TimestampMessages struct {
+	Monorepo bool
+}
+
+// Message returns the timestamped auto-commit message
+func (g TimestampMessages) Message(Repo, []Change) (string, error) {
+	return TimestampMessage(g.Monorepo), nil
+}
+
+// IsAutoCommit reports whether a commit message's subject was written by git-air
+func IsAutoCommit(message string) bool {
+	return strings.HasPrefix(message, AutoCommitPrefix)
+}
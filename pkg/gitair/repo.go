@@ -0,0 +1,133 @@
+package gitair
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// GitRepo is a Repo run through the git command line. Every command gets
+// -C, so it never depends on the working directory and repos can be synced
+// from several goroutines.
+type GitRepo struct {
+	path string
+
+	// Options go before every git subcommand, e.g. "-c", "http.proxy=..."
+	Options []string
+
+	// Command builds the git commands from their arguments, exec.Command
+	// with "git" if nil; set it to bind them to a context or a timeout
+	Command func(args ...string) *exec.Cmd
+}
+
+// Open returns the repo whose working tree is at path
+func Open(path string) *GitRepo {
+	return &GitRepo{path: path}
+}
+
+// Path is the directory of the working tree
+func (r *GitRepo) Path() string {
+	return r.path
+}
+
+// command returns the git command running args in the repo
+func (r *GitRepo) command(args ...string) *exec.Cmd {
+	args = append(append([]string{"-C", r.path}, r.Options...), args...)
+	if r.Command != nil {
+		return r.Command(args...)
+	}
+	return exec.Command("git", args...)
+}
+
+// Git runs a git command in the repo and returns its combined output
+func (r *GitRepo) Git(args ...string) (string, error) {
+	output, err := r.command(args...).CombinedOutput()
+	return string(output), err
+}
+
+// Status lists the uncommitted changes, untracked files included
+func (r *GitRepo) Status() ([]Change, error) {
+	output, err := r.command("status", "--porcelain=v2", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseStatus(output), nil
+}
+
+// ParseStatus parses the output of git status --porcelain=v2 -z
+func ParseStatus(output []byte) []Change {
+	var changes []Change
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		fields := 0
+		switch {
+		case strings.HasPrefix(entry, "? "):
+			changes = append(changes, Change{Status: "??", Path: entry[2:]})
+			continue
+		case strings.HasPrefix(entry, "1 "):
+			fields = 8 // 1 XY sub mH mI mW hH hI path
+		case strings.HasPrefix(entry, "2 "):
+			fields = 9 // 2 XY sub mH mI mW hH hI Xscore path, then the original path
+			i++
+		case strings.HasPrefix(entry, "u "):
+			fields = 10 // u XY sub m1 m2 m3 mW h1 h2 h3 path
+		default:
+			continue
+		}
+		parts := strings.SplitN(entry, " ", fields+1)
+		if len(parts) <= fields {
+			continue
+		}
+		changes = append(changes, Change{Status: strings.ReplaceAll(parts[1], ".", " "), Path: parts[fields]})
+	}
+	return changes
+}
+
+// Stage stages every change except the paths in exclude
+func (r *GitRepo) Stage(exclude ...string) error {
+	args := []string{"add", "-A", "--", "."}
+	for _, path := range exclude {
+		args = append(args, ":(exclude,literal)"+path)
+	}
+	_, err := r.Git(args...)
+	return err
+}
+
+// Commit commits the staged changes; args are extra git commit options.
+// A failed commit returns git's output as the error.
+func (r *GitRepo) Commit(message string, args ...string) error {
+	output, err := r.Git(append(append([]string{"commit"}, args...), "-m", message)...)
+	if err != nil && strings.TrimSpace(output) != "" {
+		return errors.New(strings.TrimSpace(output))
+	}
+	return err
+}
+
+// Branch is the checked-out branch, "" when HEAD is detached
+func (r *GitRepo) Branch() string {
+	output, err := r.Git("branch", "--show-current")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
+
+// Remotes lists the names of the configured remotes
+func (r *GitRepo) Remotes() []string {
+	output, err := r.command("remote").Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(output))
+}
+
+// Push pushes branch to remote
+func (r *GitRepo) Push(remote, branch string) (string, error) {
+	return r.Git("push", remote, branch)
+}
+
+// Pull merges branch from remote into the checked-out branch
+func (r *GitRepo) Pull(remote, branch string) (string, error) {
+	return r.Git("pull", remote, branch)
+}
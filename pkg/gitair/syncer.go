@@ -0,0 +1,68 @@
+package gitair
+
+import "fmt"
+
+// AutoSyncer is the plain git-air cycle for one repo: commit every change
+// with a generated message, then push to all remotes
+type AutoSyncer struct {
+	Messages CommitMessageGenerator
+
+	// Push sends new commits to every remote, not just origin
+	Push bool
+
+	// Exclude lists paths that are never staged
+	Exclude []string
+
+	// Events receives what Sync does, if set
+	Events *Bus
+}
+
+// Sync commits the repo's changes and pushes them. A repo without changes
+// is left alone; a failed push is reported in Result.Failed, not as an error.
+func (s AutoSyncer) Sync(repo Repo) (Result, error) {
+	var result Result
+	changes, err := repo.Status()
+	if err != nil {
+		return result, fmt.Errorf("%s: status: %v", repo.Path(), err)
+	}
+	if len(changes) == 0 {
+		return result, nil
+	}
+	s.Events.Publish(Event{Type: RepoDirty, Repo: repo.Path(), Changes: changes})
+
+	if err := repo.Stage(s.Exclude...); err != nil {
+		return result, fmt.Errorf("%s: staging: %v", repo.Path(), err)
+	}
+	messages := s.Messages
+	if messages == nil {
+		messages = TimestampMessages{}
+	}
+	message, err := messages.Message(repo, changes)
+	if err != nil {
+		return result, fmt.Errorf("%s: commit message: %v", repo.Path(), err)
+	}
+	if err := repo.Commit(message); err != nil {
+		s.Events.Publish(Event{Type: Error, Repo: repo.Path(), Op: "commit", Err: err.Error()})
+		return result, fmt.Errorf("%s: commit: %v", repo.Path(), err)
+	}
+	result.Committed, result.Message = true, message
+	s.Events.Publish(Event{Type: Committed, Repo: repo.Path(), Message: message, Files: len(changes)})
+
+	branch := repo.Branch()
+	if !s.Push || branch == "" {
+		return result, nil
+	}
+	for _, remote := range repo.Remotes() {
+		if output, err := repo.Push(remote, branch); err != nil {
+			if result.Failed == nil {
+				result.Failed = map[string]string{}
+			}
+			result.Failed[remote] = output
+			s.Events.Publish(Event{Type: Error, Repo: repo.Path(), Op: "push", Remote: remote, Err: output})
+			continue
+		}
+		result.Pushed = append(result.Pushed, remote)
+		s.Events.Publish(Event{Type: Pushed, Repo: repo.Path(), Remote: remote})
+	}
+	return result, nil
+}